@@ -0,0 +1,199 @@
+package jsonappender
+
+// arrayChunkSize is how many elements BufWriter formats at a time for typed arrays.
+const arrayChunkSize = 256
+
+// growBuf makes sure buf has room for n more bytes.
+func growBuf(buf []byte, n int) []byte {
+	if cap(buf)-len(buf) >= n {
+		return buf
+	}
+	grown := make([]byte, len(buf), 2*cap(buf)+n)
+	copy(grown, buf)
+	return grown
+}
+
+// Int64Array appends an array of int64 values
+func Int64Array(vals []int64, buf []byte) []byte {
+	buf = growBuf(buf, 2+len(vals)*8)
+	buf = append(buf, '[')
+	buf = appendInt64s(vals, false, buf)
+	return append(buf, ']')
+}
+
+// appendInt64s appends vals separated by commas, quoting large ones when quote is set
+// like SetQuoteLargeInts.
+func appendInt64s(vals []int64, quote bool, buf []byte) []byte {
+	for i, val := range vals {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendInt64Quoted(val, quote, buf)
+	}
+	return buf
+}
+
+// Int64Array writes an array of int64 values
+func (bw *BufWriter) Int64Array(vals []int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeByte('[')
+	for i := 0; i < len(vals) && bw.Error == nil; i += arrayChunkSize {
+		bw.stringBuf = bw.stringBuf[:0]
+		if i > 0 {
+			bw.stringBuf = append(bw.stringBuf, ',')
+		}
+		end := i + arrayChunkSize
+		if end > len(vals) {
+			end = len(vals)
+		}
+		bw.stringBuf = appendInt64s(vals[i:end], bw.quoteLargeInts, bw.stringBuf)
+		bw.write(bw.stringBuf)
+	}
+	if bw.Error != nil {
+		return
+	}
+	bw.writeByte(']')
+}
+
+// Float64Array appends an array of float64 values
+func Float64Array(vals []float64, buf []byte) ([]byte, error) {
+	buf = growBuf(buf, 2+len(vals)*12)
+	buf = append(buf, '[')
+	buf, err := appendFloat64s(vals, FloatOptions{}, buf)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, ']'), nil
+}
+
+func appendFloat64s(vals []float64, opts FloatOptions, buf []byte) ([]byte, error) {
+	var err error
+	for i, val := range vals {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf, err = Float64WithOptions(val, opts, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+// Float64Array writes an array of float64 values formatted with the BufWriter's
+// FloatOptions
+func (bw *BufWriter) Float64Array(vals []float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeByte('[')
+	for i := 0; i < len(vals) && bw.Error == nil; i += arrayChunkSize {
+		bw.stringBuf = bw.stringBuf[:0]
+		if i > 0 {
+			bw.stringBuf = append(bw.stringBuf, ',')
+		}
+		end := i + arrayChunkSize
+		if end > len(vals) {
+			end = len(vals)
+		}
+		bw.stringBuf, bw.Error = appendFloat64s(vals[i:end], bw.floatOpts, bw.stringBuf)
+		if bw.Error != nil {
+			return
+		}
+		bw.write(bw.stringBuf)
+	}
+	if bw.Error != nil {
+		return
+	}
+	bw.writeByte(']')
+}
+
+// Int64Matrix appends an array of arrays of int64 values
+func Int64Matrix(rows [][]int64, buf []byte) []byte {
+	n := 2
+	for _, row := range rows {
+		n += 3 + len(row)*8
+	}
+	buf = growBuf(buf, n)
+	buf = append(buf, '[')
+	for i, row := range rows {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '[')
+		buf = appendInt64s(row, false, buf)
+		buf = append(buf, ']')
+	}
+	return append(buf, ']')
+}
+
+// Int64Matrix writes an array of arrays of int64 values
+func (bw *BufWriter) Int64Matrix(rows [][]int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeByte('[')
+	for i, row := range rows {
+		if i > 0 {
+			bw.writeByte(',')
+		}
+		bw.Int64Array(row)
+	}
+	if bw.Error != nil {
+		return
+	}
+	bw.writeByte(']')
+}
+
+// Float64Matrix appends an array of arrays of float64 values
+func Float64Matrix(rows [][]float64, buf []byte) ([]byte, error) {
+	return Float64MatrixWithOptions(rows, FloatOptions{}, buf)
+}
+
+// Float64MatrixWithOptions is like Float64Matrix but formats values according to opts.
+func Float64MatrixWithOptions(rows [][]float64, opts FloatOptions, buf []byte) ([]byte, error) {
+	n := 2
+	for _, row := range rows {
+		n += 3 + len(row)*12
+	}
+	buf = growBuf(buf, n)
+	buf = append(buf, '[')
+	var err error
+	for i, row := range rows {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '[')
+		buf, err = appendFloat64s(row, opts, buf)
+		if err != nil {
+			return buf, err
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, ']'), nil
+}
+
+// Float64Matrix writes an array of arrays of float64 values formatted with the
+// BufWriter's FloatOptions
+func (bw *BufWriter) Float64Matrix(rows [][]float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeByte('[')
+	for i, row := range rows {
+		if i > 0 {
+			bw.writeByte(',')
+		}
+		bw.Float64Array(row)
+	}
+	if bw.Error != nil {
+		return
+	}
+	bw.writeByte(']')
+}
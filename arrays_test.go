@@ -0,0 +1,118 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestInt64Array(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val []int64, buf string) bool {
+			val = append([]int64{}, val...)
+			got := Int64Array(val, []byte(buf))
+			if !matchesEncodingJSON(val, []byte(buf), got, nil) {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriter(&bb)
+			bw.Int64Array(val)
+			err := bw.Flush()
+			return matchesEncodingJSON(val, nil, bb.Bytes(), err)
+		}, gen.SliceOf(gen.Int64()), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	long := make([]int64, 1000)
+	for i := range long {
+		long[i] = int64(i)
+	}
+	var bb bytes.Buffer
+	bw := NewBufWriter(&bb)
+	bw.Int64Array(long)
+	err := bw.Flush()
+	if !matchesEncodingJSON(long, nil, bb.Bytes(), err) {
+		t.Errorf("got %s", bb.String())
+	}
+}
+
+func TestFloat64Array(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val []float64, buf string) bool {
+			val = append([]float64{}, val...)
+			got, err := Float64Array(val, []byte(buf))
+			if !matchesEncodingJSON(val, []byte(buf), got, err) {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriter(&bb)
+			bw.Float64Array(val)
+			err = bw.Flush()
+			return matchesEncodingJSON(val, nil, bb.Bytes(), err)
+		}, gen.SliceOf(gen.Float64()), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	long := make([]float64, 1000)
+	long[999] = math.NaN()
+	bw := NewBufWriter(&bytes.Buffer{})
+	bw.Float64Array(long)
+	if bw.Error == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestInt64Matrix(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val [][]int64, buf string) bool {
+			got := Int64Matrix(val, []byte(buf))
+			if !matchesEncodingJSON(val, []byte(buf), got, nil) {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriter(&bb)
+			bw.Int64Matrix(val)
+			err := bw.Flush()
+			return matchesEncodingJSON(val, nil, bb.Bytes(), err)
+		}, gen.SliceOf(gen.SliceOf(gen.Int64())), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+}
+
+func TestFloat64Matrix(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val [][]float64, buf string) bool {
+			got, err := Float64Matrix(val, []byte(buf))
+			if !matchesEncodingJSON(val, []byte(buf), got, err) {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriter(&bb)
+			bw.Float64Matrix(val)
+			err = bw.Flush()
+			return matchesEncodingJSON(val, nil, bb.Bytes(), err)
+		}, gen.SliceOf(gen.SliceOf(gen.Float64())), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	got, err := Float64MatrixWithOptions([][]float64{{1, 0.125}, {}, {-2.5}}, FloatOptions{Precision: 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[[1.00,0.12],[],[-2.50]]"; string(got) != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+	bw := NewBufWriter(&bytes.Buffer{})
+	bw.Float64Matrix([][]float64{{1}, {math.Inf(1)}})
+	if bw.Error == nil {
+		t.Error("expected an error")
+	}
+}
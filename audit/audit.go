@@ -0,0 +1,132 @@
+// Package audit builds audit log records. Records with missing required fields are
+// refused instead of written.
+package audit
+
+import (
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+// Outcomes an Event can have.
+const (
+	Success Outcome = "success"
+	Failure Outcome = "failure"
+	Denied  Outcome = "denied"
+)
+
+// MissingFieldError is returned when an Event is missing a required field or has an
+// invalid Outcome.
+type MissingFieldError struct {
+	Field string
+}
+
+func (e *MissingFieldError) Error() string {
+	return "audit: event is missing required field " + e.Field
+}
+
+// Field is an additional detail of an Event. Value can be anything jsonappender.Value
+// accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Event is one audit record. Time, Actor, Action, Resource and Outcome are required.
+// The other fields are omitted when empty.
+type Event struct {
+	Time     time.Time
+	Actor    string
+	Action   string
+	Resource string
+	Outcome  Outcome
+
+	RequestID     string
+	TraceID       string
+	CorrelationID string
+	// Details is written in order as the members of "details".
+	Details []Field
+}
+
+// NewEvent returns an Event with all required fields set and Time set to now.
+func NewEvent(actor, action, resource string, outcome Outcome) *Event {
+	return &Event{
+		Time:     time.Now(),
+		Actor:    actor,
+		Action:   action,
+		Resource: resource,
+		Outcome:  outcome,
+	}
+}
+
+// Validate returns a *MissingFieldError for the first required field that isn't set.
+func (e *Event) Validate() error {
+	switch {
+	case e.Time.IsZero():
+		return &MissingFieldError{Field: "time"}
+	case e.Actor == "":
+		return &MissingFieldError{Field: "actor"}
+	case e.Action == "":
+		return &MissingFieldError{Field: "action"}
+	case e.Resource == "":
+		return &MissingFieldError{Field: "resource"}
+	}
+	switch e.Outcome {
+	case Success, Failure, Denied:
+		return nil
+	}
+	return &MissingFieldError{Field: "outcome"}
+}
+
+// AppendJSON implements jsonappender.JSONAppender. It appends nothing and returns the
+// error from Validate when a required field is missing.
+func (e *Event) AppendJSON(buf []byte) ([]byte, error) {
+	err := e.Validate()
+	if err != nil {
+		return buf, err
+	}
+	start := len(buf)
+	buf = append(buf, `{"time":`...)
+	tm, err := jsonappender.Time(e.Time, buf)
+	if err != nil {
+		return buf[:start], err
+	}
+	buf = tm
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "actor", value: e.Actor},
+		{name: "action", value: e.Action},
+		{name: "resource", value: e.Resource},
+		{name: "outcome", value: string(e.Outcome)},
+		{name: "request_id", value: e.RequestID},
+		{name: "trace_id", value: e.TraceID},
+		{name: "correlation_id", value: e.CorrelationID},
+	} {
+		if f.value == "" {
+			continue
+		}
+		buf = append(buf, ',')
+		buf = jsonappender.FieldName(f.name, buf)
+		buf = jsonappender.String(f.value, buf)
+	}
+	if len(e.Details) > 0 {
+		buf = append(buf, `,"details":{`...)
+		for i, f := range e.Details {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = jsonappender.FieldName(f.Name, buf)
+			buf, err = jsonappender.Value(f.Value, buf)
+			if err != nil {
+				return buf[:start], err
+			}
+		}
+		buf = append(buf, '}')
+	}
+	return append(buf, '}'), nil
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvent(t *testing.T) {
+	e := NewEvent("alice", "user.delete", "users/42", Denied)
+	e.Time = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	e.RequestID = "r1"
+	e.Details = []Field{{Name: "reason", Value: "not admin"}, {Name: "attempt", Value: 2}}
+	got, err := e.AppendJSON([]byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `x{"time":"2020-01-02T03:04:05Z","actor":"alice","action":"user.delete","resource":"users/42",` +
+		`"outcome":"denied","request_id":"r1","details":{"reason":"not admin","attempt":2}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestEvent_missing(t *testing.T) {
+	valid := func() *Event {
+		return NewEvent("alice", "login", "session", Success)
+	}
+	for field, mutate := range map[string]func(e *Event){
+		"time":     func(e *Event) { e.Time = time.Time{} },
+		"actor":    func(e *Event) { e.Actor = "" },
+		"action":   func(e *Event) { e.Action = "" },
+		"resource": func(e *Event) { e.Resource = "" },
+		"outcome":  func(e *Event) { e.Outcome = "maybe" },
+	} {
+		e := valid()
+		mutate(e)
+		got, err := e.AppendJSON([]byte("x"))
+		var missing *MissingFieldError
+		if !errors.As(err, &missing) || missing.Field != field {
+			t.Errorf("%s: got error %v", field, err)
+		}
+		if string(got) != "x" {
+			t.Errorf("%s: expected nothing appended, got %s", field, got)
+		}
+	}
+
+	e := valid()
+	e.Details = []Field{{Name: "bad", Value: make(chan int)}}
+	got, err := e.AppendJSON([]byte("x"))
+	if err == nil || string(got) != "x" {
+		t.Errorf("expected an error and nothing appended, got %v %s", err, got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package avrojson appends values in the Avro JSON encoding, so payloads validate
+// against Avro schemas from a schema registry without an Avro library. Union values
+// other than null are wrapped in an object keyed by the branch's type name, and bytes
+// and fixed values are strings with one code point per byte.
+package avrojson
+
+import (
+	"github.com/killa-beez/jsonappender"
+)
+
+// Union appends the union branch named branch, the full name for named types, holding
+// value. A "null" branch appends null and ignores value.
+func Union(branch string, value jsonappender.JSONAppender, buf []byte) ([]byte, error) {
+	if branch == "null" {
+		return jsonappender.Null(buf), nil
+	}
+	buf = append(buf, '{')
+	buf = jsonappender.FieldName(branch, buf)
+	b, err := value.AppendJSON(buf)
+	if err != nil {
+		return b, err
+	}
+	return append(b, '}'), nil
+}
+
+// Bytes appends b as an Avro bytes or fixed value. Each byte is the code point with
+// the same value, so the string is b read as ISO-8859-1.
+func Bytes(b []byte, buf []byte) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, c := range b {
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		case c < 0x80:
+			buf = append(buf, c)
+		default:
+			buf = append(buf, 0xC0|c>>6, 0x80|c&0x3F)
+		}
+	}
+	return append(buf, '"')
+}
+
+// NullableString appends a ["null","string"] union.
+func NullableString(s *string, buf []byte) []byte {
+	if s == nil {
+		return jsonappender.Null(buf)
+	}
+	buf = append(buf, `{"string":`...)
+	buf = jsonappender.String(*s, buf)
+	return append(buf, '}')
+}
+
+// NullableBytes appends a ["null","bytes"] union. A nil b is null.
+func NullableBytes(b []byte, buf []byte) []byte {
+	if b == nil {
+		return jsonappender.Null(buf)
+	}
+	buf = append(buf, `{"bytes":`...)
+	buf = Bytes(b, buf)
+	return append(buf, '}')
+}
+
+// NullableInt appends a ["null","int"] union.
+func NullableInt(n *int32, buf []byte) []byte {
+	if n == nil {
+		return jsonappender.Null(buf)
+	}
+	buf = append(buf, `{"int":`...)
+	buf = jsonappender.Int64(int64(*n), buf)
+	return append(buf, '}')
+}
+
+// NullableLong appends a ["null","long"] union.
+func NullableLong(n *int64, buf []byte) []byte {
+	if n == nil {
+		return jsonappender.Null(buf)
+	}
+	buf = append(buf, `{"long":`...)
+	buf = jsonappender.Int64(*n, buf)
+	return append(buf, '}')
+}
+
+// NullableDouble appends a ["null","double"] union. It fails for NaN and infinities
+// like jsonappender.Float64.
+func NullableDouble(f *float64, buf []byte) ([]byte, error) {
+	if f == nil {
+		return jsonappender.Null(buf), nil
+	}
+	buf = append(buf, `{"double":`...)
+	b, err := jsonappender.Float64(*f, buf)
+	if err != nil {
+		return b, err
+	}
+	return append(b, '}'), nil
+}
+
+// NullableBoolean appends a ["null","boolean"] union.
+func NullableBoolean(v *bool, buf []byte) []byte {
+	if v == nil {
+		return jsonappender.Null(buf)
+	}
+	buf = append(buf, `{"boolean":`...)
+	buf = jsonappender.Bool(*v, buf)
+	return append(buf, '}')
+}
@@ -0,0 +1,74 @@
+package avrojson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestBytes(t *testing.T) {
+	in := []byte{0, 'a', '"', '\\', 0x7f, 0x80, 0xe9, 0xff}
+	got := Bytes(in, nil)
+	var s string
+	if err := json.Unmarshal(got, &s); err != nil {
+		t.Fatalf("%s: %v", got, err)
+	}
+	runes := []rune(s)
+	if len(runes) != len(in) {
+		t.Fatalf("got %d code points, wanted %d", len(runes), len(in))
+	}
+	for i, r := range runes {
+		if r != rune(in[i]) {
+			t.Errorf("code point %d: got %U, wanted %U", i, r, rune(in[i]))
+		}
+	}
+}
+
+func TestUnions(t *testing.T) {
+	s := "x"
+	n := int64(-2)
+	i := int32(3)
+	f := 1.5
+	v := true
+	buf := []byte("[")
+	buf = NullableString(&s, buf)
+	buf = append(buf, ',')
+	buf = NullableString(nil, buf)
+	buf = append(buf, ',')
+	buf = NullableLong(&n, buf)
+	buf = append(buf, ',')
+	buf = NullableInt(&i, buf)
+	buf = append(buf, ',')
+	buf = NullableBoolean(&v, buf)
+	buf = append(buf, ',')
+	buf = NullableBytes([]byte{0xe9}, buf)
+	buf = append(buf, ',')
+	buf, err := NullableDouble(&f, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = append(buf, ',')
+	buf, err = Union("com.example.Point", jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, `{"x":1}`...), nil
+	}), buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = append(buf, ',')
+	buf, err = Union("null", nil, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = append(buf, ']')
+	want := `[{"string":"x"},null,{"long":-2},{"int":3},{"boolean":true},{"bytes":"é"},{"double":1.5},{"com.example.Point":{"x":1}},null]`
+	if string(buf) != want {
+		t.Errorf("got  %s\nwant %s", buf, want)
+	}
+
+	nan := math.NaN()
+	if _, err := NullableDouble(&nan, nil); err == nil {
+		t.Error("expected an error for NaN")
+	}
+}
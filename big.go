@@ -0,0 +1,112 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"errors"
+	"math/big"
+)
+
+// BigInt appends n in decimal. A nil n is null.
+func BigInt(n *big.Int, buf []byte) []byte {
+	return BigIntWithOptions(n, BigOptions{}, buf)
+}
+
+// BigIntWithOptions is like BigInt but formats n according to opts.
+func BigIntWithOptions(n *big.Int, opts BigOptions, buf []byte) []byte {
+	if n == nil {
+		return Null(buf)
+	}
+	if opts.Quoted {
+		buf = append(buf, '"')
+		buf = n.Append(buf, 10)
+		return append(buf, '"')
+	}
+	return n.Append(buf, 10)
+}
+
+// BigInt writes n in decimal formatted with the BufWriter's BigOptions. A nil n is
+// null.
+func (bw *BufWriter) BigInt(n *big.Int) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = BigIntWithOptions(n, bw.bigOpts, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+const defaultRatPrecision = 20
+
+// BigFloat appends f with the fewest digits that identify it. A nil f is null. It fails
+// for infinities.
+func BigFloat(f *big.Float, buf []byte) ([]byte, error) {
+	return BigFloatWithOptions(f, BigOptions{}, buf)
+}
+
+// BigFloatWithOptions is like BigFloat but formats f according to opts.
+func BigFloatWithOptions(f *big.Float, opts BigOptions, buf []byte) ([]byte, error) {
+	if f == nil {
+		return Null(buf), nil
+	}
+	if f.IsInf() {
+		return buf, errors.New("unsupported value: " + f.String())
+	}
+	prec := opts.Precision
+	if prec <= 0 {
+		prec = -1
+	}
+	if opts.Quoted {
+		buf = append(buf, '"')
+		buf = f.Append(buf, 'g', prec)
+		return append(buf, '"'), nil
+	}
+	return f.Append(buf, 'g', prec), nil
+}
+
+// BigFloat writes f formatted with the BufWriter's BigOptions. A nil f is null.
+func (bw *BufWriter) BigFloat(f *big.Float) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = BigFloatWithOptions(f, bw.bigOpts, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// BigRat appends r in decimal with 20 digits after the decimal point. A nil r is null.
+func BigRat(r *big.Rat, buf []byte) []byte {
+	return BigRatWithOptions(r, BigOptions{}, buf)
+}
+
+// BigRatWithOptions is like BigRat but formats r according to opts.
+func BigRatWithOptions(r *big.Rat, opts BigOptions, buf []byte) []byte {
+	if r == nil {
+		return Null(buf)
+	}
+	prec := opts.Precision
+	if prec <= 0 {
+		prec = defaultRatPrecision
+	}
+	if opts.Quoted {
+		buf = append(buf, '"')
+		buf = append(buf, r.FloatString(prec)...)
+		return append(buf, '"')
+	}
+	return append(buf, r.FloatString(prec)...)
+}
+
+// BigRat writes r in decimal formatted with the BufWriter's BigOptions. A nil r is
+// null.
+func (bw *BufWriter) BigRat(r *big.Rat) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = BigRatWithOptions(r, bw.bigOpts, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,93 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestBigInt(t *testing.T) {
+	n, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	for _, val := range []*big.Int{n, big.NewInt(0), nil} {
+		got := BigInt(val, []byte("x"))
+		if !matchesEncodingJSON(val, []byte("x"), got, nil) {
+			t.Errorf("%v: got %s", val, got)
+		}
+	}
+	got := BigIntWithOptions(n, BigOptions{Quoted: true}, nil)
+	if want := `"-123456789012345678901234567890"`; string(got) != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BigInt(big.NewInt(7))
+	bw.SetBigOptions(BigOptions{Quoted: true})
+	bw.RawByte(',')
+	bw.BigInt(big.NewInt(8))
+	bw.Flush()
+	if out.String() != `7,"8"` {
+		t.Errorf("got %q", out.String())
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	for _, td := range []struct {
+		f    *big.Float
+		opts BigOptions
+		want string
+	}{
+		{f: big.NewFloat(1.5), want: `1.5`},
+		{f: big.NewFloat(-0.001), want: `-0.001`},
+		{f: big.NewFloat(1e100), want: `1e+100`},
+		{f: big.NewFloat(2.0 / 3), opts: BigOptions{Precision: 3}, want: `0.667`},
+		{f: big.NewFloat(2.5), opts: BigOptions{Quoted: true}, want: `"2.5"`},
+		{want: `null`},
+	} {
+		got, err := BigFloatWithOptions(td.f, td.opts, nil)
+		if err != nil || string(got) != td.want {
+			t.Errorf("%v: got %s, %v, wanted %s", td.f, got, err, td.want)
+		}
+	}
+	_, err := BigFloat(new(big.Float).SetInf(false), nil)
+	if err == nil {
+		t.Error("expected an error for +Inf")
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BigFloat(new(big.Float).SetInf(true))
+	if bw.Error == nil {
+		t.Error("expected an error for -Inf")
+	}
+}
+
+func TestBigRat(t *testing.T) {
+	for _, td := range []struct {
+		r    *big.Rat
+		opts BigOptions
+		want string
+	}{
+		{r: big.NewRat(1, 4), want: `0.25000000000000000000`},
+		{r: big.NewRat(-2, 3), opts: BigOptions{Precision: 4}, want: `-0.6667`},
+		{r: big.NewRat(1, 8), opts: BigOptions{Precision: 3, Quoted: true}, want: `"0.125"`},
+		{want: `null`},
+	} {
+		got := BigRatWithOptions(td.r, td.opts, nil)
+		if string(got) != td.want {
+			t.Errorf("%v: got %s, wanted %s", td.r, got, td.want)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetBigOptions(BigOptions{Precision: 2})
+	bw.BigRat(big.NewRat(3, 2))
+	bw.Flush()
+	if out.String() != `1.50` {
+		t.Errorf("got %q", out.String())
+	}
+}
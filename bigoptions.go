@@ -0,0 +1,17 @@
+package jsonappender
+
+// BigOptions changes how BigInt, BigFloat and BigRat format numbers.
+type BigOptions struct {
+	// Quoted writes numbers as strings, for parsers that would read them as a float64
+	// and lose precision.
+	Quoted bool
+	// Precision is the number of significant digits BigFloat writes and the number of
+	// digits after the decimal point BigRat writes. When it's 0 BigFloat writes the
+	// fewest digits that identify the value and BigRat writes 20.
+	Precision int
+}
+
+// SetBigOptions sets the options BigInt, BigFloat and BigRat format with.
+func (bw *BufWriter) SetBigOptions(opts BigOptions) {
+	bw.bigOpts = opts
+}
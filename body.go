@@ -0,0 +1,42 @@
+package jsonappender
+
+import (
+	"context"
+	"io"
+)
+
+// NewStreamingBody returns a reader of the output build writes to a BufWriter, for use as
+// an http.Request's Body when the document is too big to build in memory first. build
+// runs on its own goroutine as the body is read. Its error, or the error from the
+// BufWriter's Close when it returns nil, is returned by Read once the output before it
+// has been read. That includes containers build left open with BeginObject or
+// BeginArray.
+//
+// Cancelling ctx makes Read return ctx.Err() and later writes fail, so build should
+// stop once the BufWriter has an Error. Closing the body, as http.Client does when it's
+// done, does the same. The request has no GetBody, so it won't be retried on redirects
+// that need the body again.
+func NewStreamingBody(ctx context.Context, build func(bw *BufWriter) error) io.ReadCloser {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			pw.CloseWithError(ctx.Err()) //nolint:errcheck // always returns nil
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(done)
+		bw := NewBufWriter(pw)
+		err := build(bw)
+		if err == nil {
+			err = bw.Close()
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		pw.CloseWithError(err) //nolint:errcheck // always returns nil
+	}()
+	return pr
+}
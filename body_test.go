@@ -0,0 +1,81 @@
+package jsonappender
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestNewStreamingBody(t *testing.T) {
+	body := NewStreamingBody(context.Background(), func(bw *BufWriter) error {
+		bw.RawByte('[')
+		for i := 0; i < 2000; i++ {
+			if i > 0 {
+				bw.RawByte(',')
+			}
+			bw.Int64(int64(i))
+		}
+		bw.RawByte(']')
+		return nil
+	})
+	got, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "[0,1,2,") || !strings.HasSuffix(string(got), ",1999]") {
+		t.Errorf("unexpected body %.20q...", got)
+	}
+	if err := body.Close(); err != nil {
+		t.Error(err)
+	}
+
+	errBuild := errors.New("build failed")
+	body = NewStreamingBody(context.Background(), func(bw *BufWriter) error {
+		bw.RawString("[1,")
+		bw.Flush()
+		return errBuild
+	})
+	got, err = ioutil.ReadAll(body)
+	if err != errBuild {
+		t.Errorf("got error %v, wanted %v", err, errBuild)
+	}
+	if string(got) != "[1," {
+		t.Errorf("got %q, wanted %q", got, "[1,")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	body = NewStreamingBody(ctx, func(bw *BufWriter) error {
+		for bw.Error == nil {
+			bw.RawString("1,")
+		}
+		stopped <- bw.Error
+		return nil
+	})
+	buf := make([]byte, 10)
+	if _, err := body.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := <-stopped; err == nil {
+		t.Error("expected a write error after cancelling")
+	}
+	if _, err = ioutil.ReadAll(body); err != context.Canceled {
+		t.Errorf("got error %v, wanted %v", err, context.Canceled)
+	}
+}
+
+func TestStreamingBodyUnfinished(t *testing.T) {
+	body := NewStreamingBody(context.Background(), func(bw *BufWriter) error {
+		bw.BeginObject()
+		bw.StringField("a", "b")
+		return nil
+	})
+	defer body.Close() //nolint:errcheck // test
+	got, err := ioutil.ReadAll(body)
+	if _, ok := err.(*NestingError); !ok || string(got) != `{"a":"b"` {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
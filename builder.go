@@ -0,0 +1,286 @@
+package jsonappender
+
+import "time"
+
+// ObjectBuilder writes an object with chained calls that take care of commas, nesting
+// and the closing brace, like
+//
+//	bw.BuildObject().Str("a", "b").Int("n", 1).Obj("child").Bool("ok", true).End().End()
+//
+// Errors go to the BufWriter's Error as usual.
+type ObjectBuilder struct {
+	bw     *BufWriter
+	parent *ObjectBuilder
+	// parentArr is set instead of parent for objects that are array elements.
+	parentArr *ArrayBuilder
+	members   int
+	// own is set when the builder came from AppendObject and bw is its own.
+	own bool
+}
+
+// BuildObject starts an object and returns a builder for its members.
+func (bw *BufWriter) BuildObject() *ObjectBuilder {
+	bw.valueComma()
+	bw.RawByte('{')
+	return &ObjectBuilder{
+		bw: bw,
+	}
+}
+
+// AppendObject starts an object appended to buf. Get buf back from Bytes after the
+// last End.
+func AppendObject(buf []byte) *ObjectBuilder {
+	bw := &BufWriter{}
+	bw.ResetWithBuffer(nil, buf)
+	o := bw.BuildObject()
+	o.own = true
+	return o
+}
+
+// Bytes returns the buffer of a builder from AppendObject and the first error building
+// it. It returns nil for other builders.
+func (o *ObjectBuilder) Bytes() ([]byte, error) {
+	if !o.own {
+		return nil, nil
+	}
+	return o.bw.TakeBuffer(), o.bw.Error
+}
+
+// End closes the object and returns the builder of the object it's a member of, or nil
+// when there isn't one.
+func (o *ObjectBuilder) End() *ObjectBuilder {
+	o.bw.RawByte('}')
+	return o.parent
+}
+
+// EndElem closes the object and returns the builder of the array it's an element of,
+// or nil when there isn't one.
+func (o *ObjectBuilder) EndElem() *ArrayBuilder {
+	o.bw.RawByte('}')
+	return o.parentArr
+}
+
+func (o *ObjectBuilder) name(name string) {
+	if o.members > 0 {
+		o.bw.RawByte(',')
+	}
+	o.members++
+	o.bw.FieldName(name)
+}
+
+// Str writes a string member.
+func (o *ObjectBuilder) Str(name, val string) *ObjectBuilder {
+	o.name(name)
+	o.bw.String(val)
+	return o
+}
+
+// Int writes an int64 member.
+func (o *ObjectBuilder) Int(name string, val int64) *ObjectBuilder {
+	o.name(name)
+	o.bw.Int64(val)
+	return o
+}
+
+// Uint writes a uint64 member.
+func (o *ObjectBuilder) Uint(name string, val uint64) *ObjectBuilder {
+	o.name(name)
+	o.bw.Uint64(val)
+	return o
+}
+
+// Float writes a float64 member.
+func (o *ObjectBuilder) Float(name string, val float64) *ObjectBuilder {
+	o.name(name)
+	o.bw.Float64(val)
+	return o
+}
+
+// Bool writes a bool member.
+func (o *ObjectBuilder) Bool(name string, val bool) *ObjectBuilder {
+	o.name(name)
+	o.bw.Bool(val)
+	return o
+}
+
+// Time writes a time.Time member.
+func (o *ObjectBuilder) Time(name string, val time.Time) *ObjectBuilder {
+	o.name(name)
+	o.bw.Time(val)
+	return o
+}
+
+// Null writes a null member.
+func (o *ObjectBuilder) Null(name string) *ObjectBuilder {
+	o.name(name)
+	o.bw.Null()
+	return o
+}
+
+// Val writes a member with any value.
+func (o *ObjectBuilder) Val(name string, val interface{}) *ObjectBuilder {
+	o.name(name)
+	o.bw.Value(val)
+	return o
+}
+
+// Obj starts an object member and returns its builder. Its End returns o.
+func (o *ObjectBuilder) Obj(name string) *ObjectBuilder {
+	o.name(name)
+	o.bw.RawByte('{')
+	return &ObjectBuilder{
+		bw:     o.bw,
+		parent: o,
+	}
+}
+
+// Arr starts an array member and returns its builder. Its End returns o.
+func (o *ObjectBuilder) Arr(name string) *ArrayBuilder {
+	o.name(name)
+	o.bw.RawByte('[')
+	return &ArrayBuilder{
+		bw:     o.bw,
+		parent: o,
+	}
+}
+
+// ArrayBuilder writes an array with chained calls that take care of commas, nesting and
+// the closing bracket, like
+//
+//	bw.BuildArray().Int(1).Str("two").Obj().Bool("ok", true).EndElem().End()
+//
+// Errors go to the BufWriter's Error as usual.
+type ArrayBuilder struct {
+	bw     *BufWriter
+	parent *ObjectBuilder
+	// parentArr is set instead of parent for arrays that are array elements.
+	parentArr *ArrayBuilder
+	elems     int
+	// own is set when the builder came from AppendArray and bw is its own.
+	own bool
+}
+
+// BuildArray starts an array and returns a builder for its elements.
+func (bw *BufWriter) BuildArray() *ArrayBuilder {
+	bw.valueComma()
+	bw.RawByte('[')
+	return &ArrayBuilder{
+		bw: bw,
+	}
+}
+
+// AppendArray starts an array appended to buf. Get buf back from Bytes after the last
+// End.
+func AppendArray(buf []byte) *ArrayBuilder {
+	bw := &BufWriter{}
+	bw.ResetWithBuffer(nil, buf)
+	a := bw.BuildArray()
+	a.own = true
+	return a
+}
+
+// Bytes returns the buffer of a builder from AppendArray and the first error building
+// it. It returns nil for other builders.
+func (a *ArrayBuilder) Bytes() ([]byte, error) {
+	if !a.own {
+		return nil, nil
+	}
+	return a.bw.TakeBuffer(), a.bw.Error
+}
+
+// End closes the array and returns the builder of the object it's a member of, or nil
+// when there isn't one.
+func (a *ArrayBuilder) End() *ObjectBuilder {
+	a.bw.RawByte(']')
+	return a.parent
+}
+
+// EndElem closes the array and returns the builder of the array it's an element of, or
+// nil when there isn't one.
+func (a *ArrayBuilder) EndElem() *ArrayBuilder {
+	a.bw.RawByte(']')
+	return a.parentArr
+}
+
+func (a *ArrayBuilder) elem() {
+	if a.elems > 0 {
+		a.bw.RawByte(',')
+	}
+	a.elems++
+}
+
+// Str writes a string element.
+func (a *ArrayBuilder) Str(val string) *ArrayBuilder {
+	a.elem()
+	a.bw.String(val)
+	return a
+}
+
+// Int writes an int64 element.
+func (a *ArrayBuilder) Int(val int64) *ArrayBuilder {
+	a.elem()
+	a.bw.Int64(val)
+	return a
+}
+
+// Uint writes a uint64 element.
+func (a *ArrayBuilder) Uint(val uint64) *ArrayBuilder {
+	a.elem()
+	a.bw.Uint64(val)
+	return a
+}
+
+// Float writes a float64 element.
+func (a *ArrayBuilder) Float(val float64) *ArrayBuilder {
+	a.elem()
+	a.bw.Float64(val)
+	return a
+}
+
+// Bool writes a bool element.
+func (a *ArrayBuilder) Bool(val bool) *ArrayBuilder {
+	a.elem()
+	a.bw.Bool(val)
+	return a
+}
+
+// Time writes a time.Time element.
+func (a *ArrayBuilder) Time(val time.Time) *ArrayBuilder {
+	a.elem()
+	a.bw.Time(val)
+	return a
+}
+
+// Null writes a null element.
+func (a *ArrayBuilder) Null() *ArrayBuilder {
+	a.elem()
+	a.bw.Null()
+	return a
+}
+
+// Val writes an element with any value.
+func (a *ArrayBuilder) Val(val interface{}) *ArrayBuilder {
+	a.elem()
+	a.bw.Value(val)
+	return a
+}
+
+// Obj starts an object element and returns its builder. Its EndElem returns a.
+func (a *ArrayBuilder) Obj() *ObjectBuilder {
+	a.elem()
+	a.bw.RawByte('{')
+	return &ObjectBuilder{
+		bw:        a.bw,
+		parentArr: a,
+	}
+}
+
+// Arr starts an array element and returns its builder. Its EndElem returns a.
+func (a *ArrayBuilder) Arr() *ArrayBuilder {
+	a.elem()
+	a.bw.RawByte('[')
+	return &ArrayBuilder{
+		bw:        a.bw,
+		parentArr: a,
+	}
+}
@@ -0,0 +1,75 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestObjectBuilder(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BuildObject().
+		Str("a", "b").
+		Int("n", 1).
+		Obj("child").
+		Uint("u", 2).
+		Obj("empty").End().
+		Float("f", 0.5).
+		End().
+		Bool("ok", true).
+		Time("t", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).
+		Null("z").
+		Val("v", []interface{}{"x"}).
+		End()
+	bw.Flush()
+	want := `{"a":"b","n":1,"child":{"u":2,"empty":{},"f":0.5},"ok":true,"t":"2021-01-01T00:00:00Z","z":null,"v":["x"]}`
+	if out.String() != want {
+		t.Errorf("got %s\nwanted %s", out.String(), want)
+	}
+	if NewBufWriter(nil).BuildObject().End() != nil {
+		t.Error("expected End to return nil for a top-level object")
+	}
+}
+
+func TestAppendObject(t *testing.T) {
+	o := AppendObject([]byte("x"))
+	o.Str("a", "b").Obj("c").End().End()
+	buf, err := o.Bytes()
+	if err != nil || string(buf) != `x{"a":"b","c":{}}` {
+		t.Errorf("got %s, %v", buf, err)
+	}
+
+	o = AppendObject(nil)
+	o.Float("nan", math.NaN()).Str("after", "x").End()
+	_, err = o.Bytes()
+	if err == nil {
+		t.Error("expected an error for NaN")
+	}
+}
+
+func TestArrayBuilder(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BuildArray().
+		Int(1).
+		Str("two").
+		Obj().Bool("ok", true).Arr("xs").Uint(3).Float(0.5).End().EndElem().
+		Arr().Null().Arr().EndElem().EndElem().
+		Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).
+		Val(map[string]interface{}{}).
+		End()
+	bw.Flush()
+	want := `[1,"two",{"ok":true,"xs":[3,0.5]},[null,[]],"2021-01-01T00:00:00Z",{}]`
+	if out.String() != want {
+		t.Errorf("got %s\nwanted %s", out.String(), want)
+	}
+
+	a := AppendArray([]byte("x"))
+	a.Str("a").Arr().EndElem().End()
+	buf, err := a.Bytes()
+	if err != nil || string(buf) != `x["a",[]]` {
+		t.Errorf("got %s, %v", buf, err)
+	}
+}
@@ -0,0 +1,89 @@
+package jsonappender
+
+import "encoding/base64"
+
+// bytesChunkSize is how many input bytes BufWriter.Bytes and BytesHex encode at a time.
+// It's a multiple of 3 so base64 chunks don't need padding.
+const bytesChunkSize = 3 * 1024
+
+// Bytes appends val as a standard base64 string like encoding/json does for []byte.
+// A nil val is null.
+func Bytes(val []byte, buf []byte) []byte {
+	if val == nil {
+		return Null(buf)
+	}
+	n := base64.StdEncoding.EncodedLen(len(val))
+	buf = growBuf(buf, n+2)
+	buf = append(buf, '"')
+	start := len(buf)
+	buf = buf[:start+n]
+	base64.StdEncoding.Encode(buf[start:], val)
+	return append(buf, '"')
+}
+
+// Bytes writes val as a standard base64 string. A nil val is null.
+func (bw *BufWriter) Bytes(val []byte) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	if val == nil {
+		bw.Null()
+		return
+	}
+	bw.writeByte('"')
+	for i := 0; i < len(val); i += bytesChunkSize {
+		end := i + bytesChunkSize
+		if end > len(val) {
+			end = len(val)
+		}
+		n := base64.StdEncoding.EncodedLen(end - i)
+		bw.stringBuf = growBuf(bw.stringBuf[:0], n)[:n]
+		base64.StdEncoding.Encode(bw.stringBuf, val[i:end])
+		bw.write(bw.stringBuf)
+	}
+	bw.writeByte('"')
+}
+
+// BytesHex appends val as a lowercase hex string. A nil val is null.
+func BytesHex(val []byte, buf []byte) []byte {
+	if val == nil {
+		return Null(buf)
+	}
+	buf = growBuf(buf, 2*len(val)+2)
+	buf = append(buf, '"')
+	buf = appendHex(val, buf)
+	return append(buf, '"')
+}
+
+// BytesHex writes val as a lowercase hex string. A nil val is null.
+func (bw *BufWriter) BytesHex(val []byte) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	if val == nil {
+		bw.Null()
+		return
+	}
+	bw.writeByte('"')
+	for i := 0; i < len(val); i += bytesChunkSize {
+		end := i + bytesChunkSize
+		if end > len(val) {
+			end = len(val)
+		}
+		bw.stringBuf = appendHex(val[i:end], bw.stringBuf[:0])
+		bw.write(bw.stringBuf)
+	}
+	bw.writeByte('"')
+}
+
+// appendHex is encoding/hex's Encode without importing encoding/hex, which depends on
+// fmt.
+func appendHex(val []byte, buf []byte) []byte {
+	const hexDigits = "0123456789abcdef"
+	for _, b := range val {
+		buf = append(buf, hexDigits[b>>4], hexDigits[b&0xF])
+	}
+	return buf
+}
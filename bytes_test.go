@@ -0,0 +1,72 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestBytes(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val []byte, buf string) bool {
+			got := Bytes(val, []byte(buf))
+			if !matchesEncodingJSON(val, []byte(buf), got, nil) {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriterSize(&bb, 16)
+			bw.Bytes(val)
+			err := bw.Flush()
+			return matchesEncodingJSON(val, nil, bb.Bytes(), err)
+		}, gen.SliceOf(gen.UInt8()), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	long := make([]byte, 3*bytesChunkSize+2)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	var bb bytes.Buffer
+	bw := NewBufWriter(&bb)
+	bw.Bytes(long)
+	err := bw.Flush()
+	if !matchesEncodingJSON(long, nil, bb.Bytes(), err) {
+		t.Error("long value doesn't match encoding/json")
+	}
+	if got := Bytes(nil, nil); string(got) != "null" {
+		t.Errorf("got %s for nil", got)
+	}
+}
+
+func TestBytesHex(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/hex", prop.ForAll(
+		func(val []byte, buf string) bool {
+			want := buf + `"` + hex.EncodeToString(val) + `"`
+			if string(BytesHex(val, []byte(buf))) != want {
+				return false
+			}
+			var bb bytes.Buffer
+			bw := NewBufWriterSize(&bb, 16)
+			bw.BytesHex(val)
+			err := bw.Flush()
+			return err == nil && bb.String() == want[len(buf):]
+		}, gen.SliceOf(gen.UInt8()).SuchThat(func(v []byte) bool { return v != nil }), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	long := bytes.Repeat([]byte{0xab}, bytesChunkSize+1)
+	bw := NewBufWriter(nil)
+	bw.BytesHex(long)
+	if want := `"` + hex.EncodeToString(long) + `"`; string(bw.TakeBuffer()) != want {
+		t.Error("long value doesn't match encoding/hex")
+	}
+	if got := BytesHex(nil, nil); string(got) != "null" {
+		t.Errorf("got %s for nil", got)
+	}
+}
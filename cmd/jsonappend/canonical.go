@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/killa-beez/jsonappender/jcs"
+)
+
+// canonicalize writes each top-level value in the input as RFC 8785 canonical json
+// followed by a newline.
+func canonicalize(bw *jsonappender.BufWriter, data []byte) error {
+	sc := jsonappender.NewScanner(data)
+	var buf []byte
+	for {
+		var err error
+		buf, err = jcs.AppendNext(sc, buf[:0])
+		if err == io.EOF {
+			return bw.Error
+		}
+		if err != nil {
+			return err
+		}
+		bw.Raw(append(buf, '\n'))
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"io"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// formatter writes tokens from a Scanner with optional indentation. Each top-level
+// value is followed by a newline.
+type formatter struct {
+	indent string
+	// records separates top-level values instead of newlines when not nil.
+	records *jsonappender.RecordWriter
+	// stack holds whether each open container has members yet.
+	stack    []bool
+	afterKey bool
+}
+
+func (f *formatter) token(bw *jsonappender.BufWriter, tok jsonappender.Token) {
+	switch tok.Kind {
+	case jsonappender.TokenObjectEnd, jsonappender.TokenArrayEnd:
+		n := len(f.stack) - 1
+		if f.stack[n] {
+			f.newline(bw, n)
+		}
+		f.stack = f.stack[:n]
+		bw.Raw(tok.Raw)
+		if n == 0 {
+			f.endValue(bw)
+		}
+		return
+	}
+	switch n := len(f.stack); {
+	case f.afterKey:
+		f.afterKey = false
+	case n > 0:
+		if f.stack[n-1] {
+			bw.RawByte(',')
+		}
+		f.stack[n-1] = true
+		f.newline(bw, n)
+	case f.records != nil:
+		f.records.Next()
+	}
+	bw.Raw(tok.Raw)
+	switch {
+	case tok.Key:
+		bw.RawByte(':')
+		if f.indent != "" {
+			bw.RawByte(' ')
+		}
+		f.afterKey = true
+	case tok.Kind == jsonappender.TokenObjectStart, tok.Kind == jsonappender.TokenArrayStart:
+		f.stack = append(f.stack, false)
+	case len(f.stack) == 0:
+		f.endValue(bw)
+	}
+}
+
+// endValue ends a top-level value.
+func (f *formatter) endValue(bw *jsonappender.BufWriter) {
+	if f.records == nil {
+		bw.RawByte('\n')
+	}
+}
+
+func (f *formatter) newline(bw *jsonappender.BufWriter, depth int) {
+	if f.indent == "" {
+		return
+	}
+	bw.RawByte('\n')
+	for i := 0; i < depth; i++ {
+		bw.RawString(f.indent)
+	}
+}
+
+func format(bw *jsonappender.BufWriter, data []byte, indent string) error {
+	f := formatter{
+		indent: indent,
+	}
+	sc := jsonappender.NewScanner(data)
+	for {
+		tok, err := sc.Next()
+		if err == io.EOF {
+			return bw.Error
+		}
+		if err != nil {
+			return err
+		}
+		f.token(bw, tok)
+	}
+}
+
+func validate(data []byte) error {
+	sc := jsonappender.NewScanner(data)
+	for {
+		_, err := sc.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// splitter writes the elements of top-level arrays as separate records. Other
+// top-level values are written as records unchanged.
+type splitter struct {
+	f         formatter
+	separator string
+	trailing  bool
+}
+
+func (s *splitter) process(bw *jsonappender.BufWriter, data []byte) error {
+	if s.f.records == nil {
+		s.f.records = jsonappender.NewRecordWriter(bw, s.separator, s.trailing)
+	}
+	sc := jsonappender.NewScanner(data)
+	splitting := false
+	for {
+		tok, err := sc.Next()
+		if err == io.EOF {
+			return bw.Error
+		}
+		if err != nil {
+			return err
+		}
+		if tok.Kind == jsonappender.TokenArrayStart && sc.Depth() == 1 {
+			splitting = true
+			continue
+		}
+		if splitting && tok.Kind == jsonappender.TokenArrayEnd && sc.Depth() == 0 {
+			splitting = false
+			continue
+		}
+		s.f.token(bw, tok)
+	}
+}
+
+func (s *splitter) finish(bw *jsonappender.BufWriter) error {
+	if s.f.records == nil {
+		return bw.Error
+	}
+	return s.f.records.Finish()
+}
+
+// joiner writes every top-level value it sees as an element of one array.
+type joiner struct {
+	f       formatter
+	started bool
+}
+
+func (j *joiner) start(bw *jsonappender.BufWriter) {
+	if j.started {
+		return
+	}
+	j.started = true
+	bw.RawByte('[')
+	j.f.stack = append(j.f.stack, false)
+}
+
+func (j *joiner) process(bw *jsonappender.BufWriter, data []byte) error {
+	j.start(bw)
+	sc := jsonappender.NewScanner(data)
+	for {
+		tok, err := sc.Next()
+		if err == io.EOF {
+			return bw.Error
+		}
+		if err != nil {
+			return err
+		}
+		j.f.token(bw, tok)
+	}
+}
+
+func (j *joiner) finish(bw *jsonappender.BufWriter) error {
+	j.start(bw)
+	j.f.token(bw, jsonappender.Token{
+		Kind: jsonappender.TokenArrayEnd,
+		Raw:  []byte{']'},
+	})
+	return bw.Error
+}
@@ -0,0 +1,169 @@
+// Command jsonappend formats, minifies, validates and canonicalizes json using the
+// jsonappender scanner and writers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+const usage = `usage: jsonappend <command> [flags] [file...]
+
+Reads json from each file, or from stdin when no files are given, and writes the
+result to stdout. Input may contain any number of whitespace separated values.
+
+commands:
+  fmt            indent json
+  minify         remove insignificant whitespace
+  validate       check that the input is valid json
+  canonicalize   write RFC 8785 canonical json
+  ndjson split   write each element of top-level arrays as its own record
+  ndjson join    combine all top-level values into a single array
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type command struct {
+	flags *flag.FlagSet
+	// process handles the contents of one input.
+	process func(bw *jsonappender.BufWriter, data []byte) error
+	// finish is called after all inputs have been processed when not nil.
+	finish func(bw *jsonappender.BufWriter) error
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	name := args[0]
+	args = args[1:]
+	if name == "ndjson" {
+		if len(args) == 0 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		name += " " + args[0]
+		args = args[1:]
+	}
+	cmd := newCommand(name, stderr)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "jsonappend: unknown command %q\n\n%s", name, usage)
+		return 2
+	}
+	if cmd.flags.Parse(args) != nil {
+		return 2
+	}
+	bw := jsonappender.NewBufWriter(stdout)
+	inputs := cmd.flags.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	status := 0
+	for _, input := range inputs {
+		data, err := readInput(input, stdin)
+		if err == nil {
+			err = cmd.process(bw, data)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "jsonappend: %s: %v\n", input, err)
+			status = 1
+		}
+	}
+	if cmd.finish != nil && status == 0 {
+		err := cmd.finish(bw)
+		if err != nil {
+			fmt.Fprintf(stderr, "jsonappend: %v\n", err)
+			status = 1
+		}
+	}
+	err := bw.Flush()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonappend: %v\n", err)
+		return 1
+	}
+	return status
+}
+
+func newCommand(name string, stderr io.Writer) *command {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cmd := command{
+		flags: flags,
+	}
+	switch name {
+	case "fmt":
+		indent := flags.String("indent", "  ", "indentation `string`")
+		color := flags.Bool("color", false, "color the output for a terminal")
+		var out []byte
+		cmd.process = func(bw *jsonappender.BufWriter, data []byte) error {
+			if !*color {
+				return format(bw, data, *indent)
+			}
+			var err error
+			out, err = jsonappender.Colorize(data, *indent, jsonappender.DefaultColors, out[:0])
+			bw.Raw(out)
+			if err != nil {
+				return err
+			}
+			return bw.Error
+		}
+	case "minify":
+		cmd.process = func(bw *jsonappender.BufWriter, data []byte) error {
+			return format(bw, data, "")
+		}
+	case "validate":
+		cmd.process = func(_ *jsonappender.BufWriter, data []byte) error {
+			return validate(data)
+		}
+	case "canonicalize":
+		cmd.process = canonicalize
+	case "ndjson split":
+		separator := flags.String("separator", "lf", "record separator: lf, crlf, rs or a literal `string`")
+		trailing := flags.Bool("trailing", true, "write a separator after the last record")
+		s := splitter{}
+		cmd.process = func(bw *jsonappender.BufWriter, data []byte) error {
+			s.separator = recordSeparator(*separator)
+			s.trailing = *trailing
+			return s.process(bw, data)
+		}
+		cmd.finish = s.finish
+	case "ndjson join":
+		indent := flags.String("indent", "", "indentation `string`")
+		j := joiner{}
+		cmd.process = func(bw *jsonappender.BufWriter, data []byte) error {
+			j.f.indent = *indent
+			return j.process(bw, data)
+		}
+		cmd.finish = j.finish
+	default:
+		return nil
+	}
+	return &cmd
+}
+
+func recordSeparator(name string) string {
+	switch name {
+	case "lf":
+		return jsonappender.SeparatorLF
+	case "crlf":
+		return jsonappender.SeparatorCRLF
+	case "rs":
+		return jsonappender.SeparatorRS
+	}
+	return name
+}
+
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(name)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	const input = `{"b":[1,2.50,-0,1e21,"\u00e9\/"],"a":{"z":null,"y":true}} [3,4]`
+	for _, td := range []struct {
+		args   []string
+		input  string
+		want   string
+		status int
+	}{
+		{
+			args:  []string{"fmt", "-indent", " "},
+			input: input,
+			want:  "{\n \"b\": [\n  1,\n  2.50,\n  -0,\n  1e21,\n  \"\\u00e9\\/\"\n ],\n \"a\": {\n  \"z\": null,\n  \"y\": true\n }\n}\n[\n 3,\n 4\n]\n",
+		},
+		{
+			args:  []string{"fmt"},
+			input: `{} [ ]`,
+			want:  "{}\n[]\n",
+		},
+		{
+			args:  []string{"fmt", "-color", "-indent", ""},
+			input: `{"a":[1,"x",null]}`,
+			want:  "\x1b[1;39m{\x1b[0m\x1b[34;1m\"a\"\x1b[0m:\x1b[1;39m[\x1b[0m\x1b[0m1\x1b[0m,\x1b[32m\"x\"\x1b[0m,\x1b[1;30mnull\x1b[0m\x1b[1;39m]\x1b[0m\x1b[1;39m}\x1b[0m\n",
+		},
+		{
+			args:  []string{"minify"},
+			input: input,
+			want:  "{\"b\":[1,2.50,-0,1e21,\"\\u00e9\\/\"],\"a\":{\"z\":null,\"y\":true}}\n[3,4]\n",
+		},
+		{
+			args:  []string{"validate"},
+			input: input,
+		},
+		{
+			args:   []string{"validate"},
+			input:  `{"a":}`,
+			status: 1,
+		},
+		{
+			args:  []string{"canonicalize"},
+			input: input + ` {"\u20ac":1,"\r":2,"\ud83d\ude00":3,"\ufb33":4}`,
+			want:  "{\"a\":{\"y\":true,\"z\":null},\"b\":[1,2.5,0,1e+21,\"é/\"]}\n[3,4]\n{\"\\r\":2,\"€\":1,\"😀\":3,\"\ufb33\":4}\n",
+		},
+		{
+			args:   []string{"canonicalize"},
+			input:  `{"a":1,"a":2}`,
+			status: 1,
+		},
+		{
+			args:  []string{"ndjson", "split"},
+			input: " [1, {\"a\": [2]}]\n\"x\"",
+			want:  "1\n{\"a\":[2]}\n\"x\"\n",
+		},
+		{
+			args:  []string{"ndjson", "split", "-separator", "crlf", "-trailing=false"},
+			input: "[1, 2] 3",
+			want:  "1\r\n2\r\n3",
+		},
+		{
+			args:  []string{"ndjson", "split", "-separator", "rs"},
+			input: "[[1]]",
+			want:  "[1]\x1e",
+		},
+		{
+			args:  []string{"ndjson", "join"},
+			input: "1\n{\"a\": [2]}\n",
+			want:  "[1,{\"a\":[2]}]\n",
+		},
+		{
+			args:  []string{"ndjson", "join"},
+			input: "",
+			want:  "[]\n",
+		},
+		{
+			args:   []string{"bogus"},
+			status: 2,
+		},
+		{
+			args:   []string{"ndjson"},
+			status: 2,
+		},
+	} {
+		var stdout, stderr bytes.Buffer
+		status := run(td.args, strings.NewReader(td.input), &stdout, &stderr)
+		if status != td.status {
+			t.Errorf("%v: got status %d, wanted %d. stderr: %s", td.args, status, td.status, stderr.String())
+		}
+		if td.status == 0 && stdout.String() != td.want {
+			t.Errorf("%v: got %q, wanted %q", td.args, stdout.String(), td.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package jsonappender
+
+import "io"
+
+// Colors are the ANSI escape sequences Colorize writes before each kind of token. A
+// reset follows each colored token. Leave a field empty to write those tokens plain.
+type Colors struct {
+	Key    string
+	String string
+	Number string
+	// Literal is used for true, false and null.
+	Literal string
+	// Delim is used for braces and brackets.
+	Delim string
+}
+
+// DefaultColors are the colors of jq's default theme.
+var DefaultColors = Colors{
+	Key:     "\x1b[34;1m",
+	String:  "\x1b[32m",
+	Number:  "\x1b[0m",
+	Literal: "\x1b[1;30m",
+	Delim:   "\x1b[1;39m",
+}
+
+const colorReset = "\x1b[0m"
+
+// Colorize appends data, which may hold any number of whitespace separated values,
+// colored for a terminal. Values are indented with indent, or compacted when it's
+// empty. Each top-level value is followed by a newline.
+func Colorize(data []byte, indent string, colors Colors, buf []byte) ([]byte, error) {
+	return reformat(data, "", indent, colors, true, buf)
+}
+
+// reformat appends the values in data indented with prefix and indent, or compacted
+// when both are empty, and colored with colors. With multi data may hold any number of
+// values, each followed by a newline. Otherwise it has to be exactly one value.
+func reformat(data []byte, prefix, indent string, colors Colors, multi bool, buf []byte) ([]byte, error) {
+	// stack holds whether each open container has members yet
+	var stack []bool
+	afterKey := false
+	done := false
+	sc := NewScanner(data)
+	for {
+		tok, err := sc.Next()
+		if err == io.EOF {
+			if !multi && !done {
+				return buf, sc.syntaxError("expected a value")
+			}
+			return buf, nil
+		}
+		if err != nil {
+			return buf, err
+		}
+		if done && !multi {
+			return buf, &SyntaxError{
+				Offset: tok.Offset,
+				msg:    "unexpected data after value",
+			}
+		}
+		if tok.Kind == TokenObjectEnd || tok.Kind == TokenArrayEnd {
+			n := len(stack) - 1
+			if stack[n] {
+				buf = appendNewline(buf, prefix, indent, n)
+			}
+			stack = stack[:n]
+			buf = appendColored(buf, colors.Delim, tok.Raw)
+			if n == 0 {
+				buf = endTopLevel(buf, multi)
+				done = true
+			}
+			continue
+		}
+		switch n := len(stack); {
+		case afterKey:
+			afterKey = false
+		case n > 0:
+			if stack[n-1] {
+				buf = append(buf, ',')
+			}
+			stack[n-1] = true
+			buf = appendNewline(buf, prefix, indent, n)
+		}
+		var color string
+		switch tok.Kind {
+		case TokenObjectStart, TokenArrayStart:
+			color = colors.Delim
+		case TokenString:
+			color = colors.String
+			if tok.Key {
+				color = colors.Key
+			}
+		case TokenNumber:
+			color = colors.Number
+		default:
+			color = colors.Literal
+		}
+		buf = appendColored(buf, color, tok.Raw)
+		switch {
+		case tok.Key:
+			buf = append(buf, ':')
+			if prefix != "" || indent != "" {
+				buf = append(buf, ' ')
+			}
+			afterKey = true
+		case tok.Kind == TokenObjectStart, tok.Kind == TokenArrayStart:
+			stack = append(stack, false)
+		case len(stack) == 0:
+			buf = endTopLevel(buf, multi)
+			done = true
+		}
+	}
+}
+
+func endTopLevel(buf []byte, multi bool) []byte {
+	if multi {
+		return append(buf, '\n')
+	}
+	return buf
+}
+
+func appendColored(buf []byte, color string, raw []byte) []byte {
+	if color == "" {
+		return append(buf, raw...)
+	}
+	buf = append(buf, color...)
+	buf = append(buf, raw...)
+	return append(buf, colorReset...)
+}
+
+func appendNewline(buf []byte, prefix, indent string, depth int) []byte {
+	if prefix == "" && indent == "" {
+		return buf
+	}
+	buf = append(buf, '\n')
+	buf = append(buf, prefix...)
+	for i := 0; i < depth; i++ {
+		buf = append(buf, indent...)
+	}
+	return buf
+}
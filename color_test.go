@@ -0,0 +1,29 @@
+package jsonappender
+
+import (
+	"testing"
+)
+
+func TestColorize(t *testing.T) {
+	colors := Colors{Key: "<k>", String: "<s>", Number: "<n>", Literal: "<l>"}
+	got, err := Colorize([]byte(`{"a": [1, "x", true], "b": {}} null`), "  ", colors, []byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "x{\n" +
+		"  <k>\"a\"\x1b[0m: [\n" +
+		"    <n>1\x1b[0m,\n" +
+		"    <s>\"x\"\x1b[0m,\n" +
+		"    <l>true\x1b[0m\n" +
+		"  ],\n" +
+		"  <k>\"b\"\x1b[0m: {}\n" +
+		"}\n" +
+		"<l>null\x1b[0m\n"
+	if string(got) != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	if _, err := Colorize([]byte(`[1,]`), "", DefaultColors, nil); err == nil {
+		t.Error("expected a syntax error")
+	}
+}
@@ -0,0 +1,175 @@
+// Package datadog builds payloads for the Datadog logs intake API. A payload is a json
+// array of log objects, and Batch keeps it within the intake's limits.
+package datadog
+
+import (
+	"errors"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Limits of the logs intake API. Sizes are of the uncompressed payload.
+const (
+	MaxPayloadBytes = 5000000
+	MaxLogBytes     = 1000000
+	MaxEntries      = 1000
+)
+
+var (
+	// ErrBatchFull is returned by Batch.Add when the log doesn't fit. Send the batch,
+	// Reset it and add the log again.
+	ErrBatchFull = errors.New("datadog: batch is full")
+	// ErrLogTooLarge is returned by Batch.Add for a log that is larger than MaxLogBytes
+	// on its own, or that doesn't fit into an empty batch.
+	ErrLogTooLarge = errors.New("datadog: log is too large")
+)
+
+// Field is an attribute of a log. Value can be anything jsonappender.Value accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Log is one log entry. Empty fields are omitted.
+type Log struct {
+	Message  string
+	Source   string // ddsource
+	Tags     string // ddtags, comma separated key:value pairs
+	Hostname string
+	Service  string
+	Status   string
+	// Attributes are written as additional members of the log object after the
+	// reserved ones.
+	Attributes []Field
+}
+
+// AppendJSON implements jsonappender.JSONAppender.
+func (l *Log) AppendJSON(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	comma := false
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "message", value: l.Message},
+		{name: "ddsource", value: l.Source},
+		{name: "ddtags", value: l.Tags},
+		{name: "hostname", value: l.Hostname},
+		{name: "service", value: l.Service},
+		{name: "status", value: l.Status},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = jsonappender.FieldName(f.name, buf)
+		buf = jsonappender.String(f.value, buf)
+	}
+	var err error
+	for _, f := range l.Attributes {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = jsonappender.FieldName(f.Name, buf)
+		buf, err = jsonappender.Value(f.Value, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// Batch accumulates logs into one payload. The zero value uses the intake's limits.
+//
+// A Batch is not safe for concurrent use.
+type Batch struct {
+	// MaxBytes and MaxEntries lower the limits when set. Values above the intake's
+	// limits are ignored.
+	MaxBytes   int
+	MaxEntries int
+
+	buf []byte
+	n   int
+}
+
+// Add appends l to the batch. It returns ErrBatchFull and leaves the batch unchanged
+// when l would take it over a limit, or ErrLogTooLarge when l is over the size limit
+// even in an empty batch.
+func (b *Batch) Add(l *Log) error {
+	if b.n >= b.maxEntries() {
+		return ErrBatchFull
+	}
+	if len(b.buf) == 0 {
+		b.buf = append(b.buf, '[')
+	}
+	start := len(b.buf)
+	if b.n > 0 {
+		b.buf = append(b.buf, ',')
+	}
+	logStart := len(b.buf)
+	var err error
+	b.buf, err = l.AppendJSON(b.buf)
+	if err != nil {
+		b.buf = b.buf[:start]
+		return err
+	}
+	if len(b.buf)-logStart > MaxLogBytes {
+		b.buf = b.buf[:start]
+		return ErrLogTooLarge
+	}
+	// +1 for the closing bracket
+	if len(b.buf)+1 > b.maxBytes() {
+		b.buf = b.buf[:start]
+		if b.n == 0 {
+			return ErrLogTooLarge
+		}
+		return ErrBatchFull
+	}
+	b.n++
+	return nil
+}
+
+// Len returns the number of logs in the batch.
+func (b *Batch) Len() int {
+	return b.n
+}
+
+// Size returns the size of the payload Bytes would return.
+func (b *Batch) Size() int {
+	if b.n == 0 {
+		return 2
+	}
+	return len(b.buf) + 1
+}
+
+// Bytes returns the payload. It is only valid until the next call to Add or Reset.
+func (b *Batch) Bytes() []byte {
+	if b.n == 0 {
+		return []byte("[]")
+	}
+	return append(b.buf, ']')
+}
+
+// Reset empties the batch while keeping its buffer.
+func (b *Batch) Reset() {
+	b.buf = b.buf[:0]
+	b.n = 0
+}
+
+func (b *Batch) maxBytes() int {
+	if b.MaxBytes > 0 && b.MaxBytes < MaxPayloadBytes {
+		return b.MaxBytes
+	}
+	return MaxPayloadBytes
+}
+
+func (b *Batch) maxEntries() int {
+	if b.MaxEntries > 0 && b.MaxEntries < MaxEntries {
+		return b.MaxEntries
+	}
+	return MaxEntries
+}
@@ -0,0 +1,77 @@
+package datadog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	l := &Log{
+		Message:    "started",
+		Source:     "go",
+		Tags:       "env:prod,team:x",
+		Service:    "api",
+		Attributes: []Field{{Name: "duration", Value: 1.5}, {Name: "user", Value: map[string]interface{}{"id": 1}}},
+	}
+	got, err := l.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"message":"started","ddsource":"go","ddtags":"env:prod,team:x","service":"api","duration":1.5,"user":{"id":1}}`
+	if string(got) != want {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	var b Batch
+	if string(b.Bytes()) != "[]" || b.Size() != 2 {
+		t.Errorf("got %s", b.Bytes())
+	}
+	b.MaxEntries = 2
+	for _, msg := range []string{"a", "b"} {
+		if err := b.Add(&Log{Message: msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Add(&Log{Message: "c"}); err != ErrBatchFull {
+		t.Errorf("got error %v", err)
+	}
+	want := `[{"message":"a"},{"message":"b"}]`
+	if string(b.Bytes()) != want || b.Size() != len(want) || b.Len() != 2 {
+		t.Errorf("got %s", b.Bytes())
+	}
+
+	b.Reset()
+	b.MaxEntries = 0
+	b.MaxBytes = 30
+	if err := b.Add(&Log{Message: "aaaa"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(&Log{Message: "bbbb"}); err != ErrBatchFull {
+		t.Errorf("got error %v", err)
+	}
+	if want := `[{"message":"aaaa"}]`; string(b.Bytes()) != want {
+		t.Errorf("got %s", b.Bytes())
+	}
+
+	b.Reset()
+	if err := b.Add(&Log{Message: strings.Repeat("x", 30)}); err != ErrLogTooLarge {
+		t.Errorf("got error %v for a log over MaxBytes in an empty batch", err)
+	}
+	if b.Len() != 0 || string(b.Bytes()) != "[]" {
+		t.Errorf("expected an empty batch, got %s", b.Bytes())
+	}
+
+	b.Reset()
+	b.MaxBytes = 0
+	if err := b.Add(&Log{Message: strings.Repeat("x", MaxLogBytes)}); err != ErrLogTooLarge {
+		t.Errorf("got error %v", err)
+	}
+	if err := b.Add(&Log{Attributes: []Field{{Name: "bad", Value: make(chan int)}}}); err == nil {
+		t.Error("expected an error")
+	}
+	if b.Len() != 0 || string(b.Bytes()) != "[]" {
+		t.Errorf("expected an empty batch, got %s", b.Bytes())
+	}
+}
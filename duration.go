@@ -0,0 +1,67 @@
+package jsonappender
+
+import (
+	"strconv"
+	"time"
+)
+
+// DurationMode selects how Duration writes a time.Duration.
+type DurationMode int
+
+const (
+	// DurationNanoseconds writes an integer number of nanoseconds like encoding/json.
+	DurationNanoseconds DurationMode = iota
+	// DurationString writes a string in the format of time.Duration's String method,
+	// like "1h30m0s".
+	DurationString
+	// DurationSeconds writes a number of seconds with up to nine decimals, without
+	// the rounding of converting to a float64.
+	DurationSeconds
+)
+
+// Duration appends d in the representation selected by mode.
+func Duration(d time.Duration, mode DurationMode, buf []byte) []byte {
+	switch mode {
+	case DurationString:
+		return String(d.String(), buf)
+	case DurationSeconds:
+		return appendSeconds(d, buf)
+	default:
+		return strconv.AppendInt(buf, int64(d), 10)
+	}
+}
+
+// Duration writes d in the representation selected by mode.
+func (bw *BufWriter) Duration(d time.Duration, mode DurationMode) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Duration(d, mode, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+func appendSeconds(d time.Duration, buf []byte) []byte {
+	// converting to uint64 before negating keeps the minimum duration from overflowing
+	n := uint64(d)
+	if d < 0 {
+		buf = append(buf, '-')
+		n = -n
+	}
+	buf = strconv.AppendUint(buf, n/1e9, 10)
+	frac := n % 1e9
+	if frac == 0 {
+		return buf
+	}
+	var digits [9]byte
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = byte('0' + frac%10)
+		frac /= 10
+	}
+	end := len(digits)
+	for digits[end-1] == '0' {
+		end--
+	}
+	buf = append(buf, '.')
+	return append(buf, digits[:end]...)
+}
@@ -0,0 +1,62 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestDuration(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("nanoseconds same as encoding/json", prop.ForAll(
+		func(val int64, buf string) bool {
+			d := time.Duration(val)
+			return matchesEncodingJSON(d, []byte(buf), Duration(d, DurationNanoseconds, []byte(buf)), nil)
+		}, gen.Int64(), gen.AnyString(),
+	))
+	properties.Property("string same as encoding/json of String()", prop.ForAll(
+		func(val int64, buf string) bool {
+			d := time.Duration(val)
+			return matchesEncodingJSON(d.String(), []byte(buf), Duration(d, DurationString, []byte(buf)), nil)
+		}, gen.Int64(), gen.AnyString(),
+	))
+	properties.Property("seconds parse back to the same duration", prop.ForAll(
+		func(val int64) bool {
+			d := time.Duration(val)
+			got := string(Duration(d, DurationSeconds, nil))
+			back, err := time.ParseDuration(got + "s")
+			return err == nil && back == d
+		}, gen.Int64Range(-math.MaxInt64, math.MaxInt64),
+	))
+	properties.TestingRun(t)
+
+	for d, want := range map[time.Duration]string{
+		0:                       `0`,
+		90 * time.Minute:        `5400`,
+		1500 * time.Millisecond: `1.5`,
+		-time.Nanosecond:        `-0.000000001`,
+		math.MinInt64:           `-9223372036.854775808`,
+	} {
+		if got := Duration(d, DurationSeconds, nil); string(got) != want {
+			t.Errorf("%v: got %s, wanted %s", d, got, want)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Duration(time.Second, DurationNanoseconds)
+	bw.RawByte(',')
+	bw.Duration(time.Second, DurationString)
+	bw.RawByte(',')
+	bw.Duration(time.Second, DurationSeconds)
+	bw.Flush()
+	if want := strconv.Itoa(1e9) + `,"1s",1`; out.String() != want {
+		t.Errorf("got %q", out.String())
+	}
+}
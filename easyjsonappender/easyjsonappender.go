@@ -0,0 +1,40 @@
+// Package easyjsonappender lets easyjson marshalers be used wherever jsonappender expects
+// a JSONAppender. It is its own module so that jsonappender doesn't depend on easyjson.
+package easyjsonappender
+
+import (
+	"github.com/killa-beez/jsonappender"
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Appender returns a JSONAppender that encodes m with MarshalEasyJSON. The output is
+// written to easyjson's pooled buffers and copied straight into the destination.
+func Appender(m easyjson.Marshaler) jsonappender.JSONAppender {
+	return marshalerAppender{m: m}
+}
+
+type marshalerAppender struct {
+	m easyjson.Marshaler
+}
+
+func (ma marshalerAppender) AppendJSON(buf []byte) ([]byte, error) {
+	var w jwriter.Writer
+	ma.m.MarshalEasyJSON(&w)
+	if w.Error != nil {
+		return buf, w.Error
+	}
+	sw := sliceWriter{buf: buf}
+	_, err := w.DumpTo(&sw)
+	return sw.buf, err
+}
+
+// sliceWriter is an io.Writer that appends to buf.
+type sliceWriter struct {
+	buf []byte
+}
+
+func (sw *sliceWriter) Write(p []byte) (int, error) {
+	sw.buf = append(sw.buf, p...)
+	return len(p), nil
+}
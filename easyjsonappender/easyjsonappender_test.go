@@ -0,0 +1,55 @@
+package easyjsonappender
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+type point struct {
+	X, Y int
+	Name string
+	err  error
+}
+
+func (p point) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString(`{"x":`)
+	w.Int(p.X)
+	w.RawString(`,"y":`)
+	w.Int(p.Y)
+	w.RawString(`,"name":`)
+	w.String(p.Name)
+	w.RawByte('}')
+	if p.err != nil {
+		w.Error = p.err
+	}
+}
+
+func TestAppender(t *testing.T) {
+	got, err := jsonappender.Value(Appender(point{X: 1, Y: 2, Name: "a"}), []byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `x{"x":1,"y":2,"name":"a"}` {
+		t.Errorf("got %s", got)
+	}
+
+	// big enough to need more than one easyjson buffer chunk
+	name := strings.Repeat("abc", 10000)
+	got, err = Appender(point{Name: name}).AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"x":0,"y":0,"name":"`+name+`"}` {
+		t.Errorf("got wrong output for long name")
+	}
+
+	wantErr := errors.New("fail")
+	_, err = Appender(point{err: wantErr}).AppendJSON(nil)
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package jsonappender
+
+import (
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is the character encoding of a BufWriter's output.
+type Encoding int
+
+// Encodings for SetEncoding. Every encoding other than UTF8 starts the output with a
+// byte order mark.
+const (
+	UTF8 Encoding = iota
+	UTF8BOM
+	UTF16LE
+	UTF16BE
+)
+
+// SetEncoding makes the BufWriter transcode its output to enc as it's flushed. Call it
+// before writing anything. The byte order mark is written again after each Reset. An
+// in-memory BufWriter, one without a writer, always holds UTF-8.
+func (bw *BufWriter) SetEncoding(enc Encoding) {
+	if bw.transcoder != nil {
+		bw.w = bw.transcoder.w
+		bw.transcoder = nil
+	}
+	if enc == UTF8 {
+		return
+	}
+	bw.transcoder = &transcoder{
+		enc: enc,
+	}
+	bw.setWriter(bw.w)
+}
+
+// setWriter sets the writer output is flushed to, putting the transcoder in front of
+// it when there is one.
+func (bw *BufWriter) setWriter(w io.Writer) {
+	bw.w = w
+	if bw.transcoder != nil && w != nil {
+		bw.transcoder.reset(w)
+		bw.w = bw.transcoder
+	}
+}
+
+// transcoder converts UTF-8 to another encoding on its way to w. Runes split between
+// writes are held back until the rest arrives.
+type transcoder struct {
+	w        io.Writer
+	enc      Encoding
+	wroteBOM bool
+	pending  [utf8.UTFMax]byte
+	npending int
+	in       []byte
+	out      []byte
+}
+
+func (t *transcoder) reset(w io.Writer) {
+	t.w = w
+	t.wroteBOM = false
+	t.npending = 0
+}
+
+// Write reports len(p) when the transcoded output was written in full because the
+// number of output bytes doesn't correspond to input bytes.
+func (t *transcoder) Write(p []byte) (int, error) {
+	out := t.out[:0]
+	if !t.wroteBOM {
+		t.wroteBOM = true
+		switch t.enc {
+		case UTF8BOM:
+			out = append(out, 0xEF, 0xBB, 0xBF)
+		case UTF16LE:
+			out = append(out, 0xFF, 0xFE)
+		case UTF16BE:
+			out = append(out, 0xFE, 0xFF)
+		}
+	}
+	n := len(p)
+	if t.enc == UTF8BOM {
+		out = append(out, p...)
+		p = nil
+	}
+	if t.npending > 0 {
+		// finish the rune left over from the previous write
+		t.in = append(append(t.in[:0], t.pending[:t.npending]...), p...)
+		p = t.in
+		t.npending = 0
+	}
+	for len(p) > 0 {
+		if p[0] < utf8.RuneSelf {
+			out = t.appendRune(rune(p[0]), out)
+			p = p[1:]
+			continue
+		}
+		if !utf8.FullRune(p) {
+			t.npending = copy(t.pending[:], p)
+			break
+		}
+		r, size := utf8.DecodeRune(p)
+		out = t.appendRune(r, out)
+		p = p[size:]
+	}
+	t.out = out
+	return n, t.flushOut(out)
+}
+
+func (t *transcoder) flushOut(out []byte) error {
+	if len(out) == 0 {
+		return nil
+	}
+	written, err := t.w.Write(out)
+	if written < len(out) && err == nil {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+func (t *transcoder) appendRune(r rune, out []byte) []byte {
+	if r >= 0x10000 {
+		r1, r2 := utf16.EncodeRune(r)
+		return t.appendUnit(uint16(r2), t.appendUnit(uint16(r1), out))
+	}
+	return t.appendUnit(uint16(r), out)
+}
+
+func (t *transcoder) appendUnit(u uint16, out []byte) []byte {
+	if t.enc == UTF16BE {
+		return append(out, byte(u>>8), byte(u))
+	}
+	return append(out, byte(u), byte(u>>8))
+}
@@ -0,0 +1,81 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func decodeUTF16(b []byte, order binary.ByteOrder) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestBufWriter_SetEncoding(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("UTF-16 round trips", prop.ForAll(
+		func(s string, size int) bool {
+			var want, got bytes.Buffer
+			plain := NewBufWriter(&want)
+			plain.String(s)
+			if plain.Flush() != nil {
+				return false
+			}
+			bw := NewBufWriterSize(&got, size)
+			bw.SetEncoding(UTF16LE)
+			bw.String(s)
+			if bw.Flush() != nil {
+				return false
+			}
+			out := got.Bytes()
+			return len(out) >= 2 && out[0] == 0xFF && out[1] == 0xFE &&
+				decodeUTF16(out[2:], binary.LittleEndian) == want.String()
+		},
+		gen.AnyString(),
+		gen.IntRange(1, 8),
+	))
+	properties.TestingRun(t)
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetEncoding(UTF16BE)
+	bw.String("é😀")
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\xfe\xff\x00\x22\x00\xe9\xd8\x3d\xde\x00\x00\x22"; out.String() != want {
+		t.Errorf("got % x", out.Bytes())
+	}
+
+	out.Reset()
+	bw.SetEncoding(UTF8BOM)
+	bw.Reset(&out)
+	bw.RawString("[]")
+	bw.Reset(&out)
+	bw.RawString("{}")
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\xef\xbb\xbf{}"; out.String() != want {
+		t.Errorf("got %q", out.String())
+	}
+
+	out.Reset()
+	bw.SetEncoding(UTF8)
+	bw.Reset(&out)
+	bw.RawString("{}")
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "{}" {
+		t.Errorf("got %q", out.String())
+	}
+}
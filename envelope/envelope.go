@@ -0,0 +1,127 @@
+// Package envelope wraps payloads in the common {"data":...,"meta":{...},"errors":[...]}
+// response shape.
+package envelope
+
+import "github.com/killa-beez/jsonappender"
+
+// Envelope is a response document. The payload is appended directly into the output
+// buffer when the envelope is, so it never needs to be marshaled separately.
+type Envelope struct {
+	// Data is the payload. A nil Data is written as "data":null unless there are Errors.
+	Data jsonappender.JSONAppender
+	// Meta is written in order as the members of "meta". It is omitted when empty.
+	Meta []Field
+	// Errors is omitted when empty.
+	Errors []Error
+}
+
+// Field is one member of the meta object. Value can be anything jsonappender.Value
+// accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Error is one entry in the errors array. Empty members are omitted.
+type Error struct {
+	Status string
+	Code   string
+	Title  string
+	Detail string
+}
+
+// Data returns an Envelope for a successful response.
+func Data(data jsonappender.JSONAppender, meta ...Field) *Envelope {
+	return &Envelope{
+		Data: data,
+		Meta: meta,
+	}
+}
+
+// Errors returns an Envelope for a failed response.
+func Errors(errs ...Error) *Envelope {
+	return &Envelope{
+		Errors: errs,
+	}
+}
+
+// AppendJSON implements jsonappender.JSONAppender.
+func (e *Envelope) AppendJSON(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	comma := false
+	var err error
+	if e.Data != nil || len(e.Errors) == 0 {
+		buf = append(buf, `"data":`...)
+		if e.Data == nil {
+			buf = jsonappender.Null(buf)
+		} else {
+			buf, err = e.Data.AppendJSON(buf)
+			if err != nil {
+				return buf, err
+			}
+		}
+		comma = true
+	}
+	if len(e.Meta) > 0 {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = append(buf, `"meta":{`...)
+		for i, f := range e.Meta {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = jsonappender.FieldName(f.Name, buf)
+			buf, err = jsonappender.Value(f.Value, buf)
+			if err != nil {
+				return buf, err
+			}
+		}
+		buf = append(buf, '}')
+	}
+	if len(e.Errors) > 0 {
+		if comma {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `"errors":[`...)
+		for i := range e.Errors {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = e.Errors[i].appendJSON(buf)
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, '}'), nil
+}
+
+// AppendJSON implements jsonappender.JSONAppender.
+func (e Error) AppendJSON(buf []byte) ([]byte, error) {
+	return e.appendJSON(buf), nil
+}
+
+func (e *Error) appendJSON(buf []byte) []byte {
+	buf = append(buf, '{')
+	comma := false
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "status", value: e.Status},
+		{name: "code", value: e.Code},
+		{name: "title", value: e.Title},
+		{name: "detail", value: e.Detail},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = jsonappender.FieldName(f.name, buf)
+		buf = jsonappender.String(f.value, buf)
+	}
+	return append(buf, '}')
+}
@@ -0,0 +1,72 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestEnvelope(t *testing.T) {
+	payload := jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, `[1,2]`...), nil
+	})
+	for _, td := range []struct {
+		env  *Envelope
+		want string
+	}{
+		{
+			env:  &Envelope{},
+			want: `{"data":null}`,
+		},
+		{
+			env:  Data(payload),
+			want: `{"data":[1,2]}`,
+		},
+		{
+			env:  Data(payload, Field{Name: "page", Value: 2}, Field{Name: "next", Value: "/x?a=1&b=2"}),
+			want: `{"data":[1,2],"meta":{"page":2,"next":"/x?a=1\u0026b=2"}}`,
+		},
+		{
+			env:  Errors(Error{Status: "404", Title: "not found"}, Error{Code: "gone", Detail: `"x" was deleted`}),
+			want: `{"errors":[{"status":"404","title":"not found"},{"code":"gone","detail":"\"x\" was deleted"}]}`,
+		},
+		{
+			env: &Envelope{
+				Data:   payload,
+				Meta:   []Field{{Name: "partial", Value: true}},
+				Errors: []Error{{Code: "timeout"}},
+			},
+			want: `{"data":[1,2],"meta":{"partial":true},"errors":[{"code":"timeout"}]}`,
+		},
+	} {
+		got, err := td.env.AppendJSON([]byte("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "x"+td.want {
+			t.Errorf("got %s, wanted x%s", got, td.want)
+		}
+	}
+}
+
+func TestEnvelope_BufWriter(t *testing.T) {
+	var out bytes.Buffer
+	bw := jsonappender.NewBufWriter(&out)
+	bw.Value(Errors(Error{Code: "a"}))
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"errors":[{"code":"a"}]}`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+
+	wantErr := errors.New("fail")
+	_, err := Data(jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return buf, wantErr
+	})).AppendJSON(nil)
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package jsonappender
+
+// Err appends err's message as a string value, or null for a nil err. An err that is a
+// JSONAppender appends its own json instead, falling back to the message when that
+// fails. Like fmt, a nil pointer whose methods panic is null too, except with
+// jsonappender_noreflect.
+func Err(err error, buf []byte) []byte {
+	if err == nil {
+		return Null(buf)
+	}
+	b, ok := appendErr(err, buf)
+	if !ok {
+		return Null(buf)
+	}
+	return b
+}
+
+// appendErr appends err like Err. ok is false when err is a nil pointer and one of
+// its methods panicked.
+func appendErr(err error, buf []byte) (b []byte, ok bool) {
+	defer func() { recoverNilPointer(err, recover()) }()
+	if a, isAppender := err.(JSONAppender); isAppender {
+		if b, aErr := a.AppendJSON(buf); aErr == nil {
+			return b, true
+		}
+	}
+	return String(err.Error(), buf), true
+}
+
+// recoverNilPointer panics again with r, a recovered value, unless val is a nil
+// pointer. Methods called on nil pointers often dereference them.
+func recoverNilPointer(val, r interface{}) {
+	if r != nil && !isNilPointer(val) {
+		panic(r)
+	}
+}
+
+// Err writes err's message as a string value, or null for a nil err. An err that is a
+// JSONAppender writes its own json instead.
+func (bw *BufWriter) Err(err error) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Err(err, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
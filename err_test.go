@@ -0,0 +1,51 @@
+package jsonappender
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type appenderError struct {
+	code int64
+	fail bool
+}
+
+func (e *appenderError) Error() string {
+	return "appender error"
+}
+
+func (e *appenderError) AppendJSON(buf []byte) ([]byte, error) {
+	if e.fail {
+		return append(buf, `{"bro`...), errors.New("failed")
+	}
+	buf = append(buf, `{"code":`...)
+	buf = Int64(e.code, buf)
+	return append(buf, '}'), nil
+}
+
+func TestErr(t *testing.T) {
+	for _, td := range []struct {
+		err  error
+		want string
+	}{
+		{want: `xnull`},
+		{err: errors.New(`bad "<input>"`), want: `x"bad \"\u003cinput\u003e\""`},
+		{err: &appenderError{code: 3}, want: `x{"code":3}`},
+		{err: &appenderError{fail: true}, want: `x"appender error"`},
+	} {
+		if got := Err(td.err, []byte("x")); string(got) != td.want {
+			t.Errorf("%v: got %s, wanted %s", td.err, got, td.want)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Err(nil)
+	bw.RawByte(',')
+	bw.Err(errors.New("oops"))
+	bw.Flush()
+	if out.String() != `null,"oops"` {
+		t.Errorf("got %q", out.String())
+	}
+}
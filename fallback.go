@@ -0,0 +1,13 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict
+// +build !jsonappender_noreflect,!jsonappender_strict
+
+package jsonappender
+
+// unsupportedTypeReason ends UnsupportedTypeError's message. Value doesn't return
+// the error without a build tag, but callers can still construct one.
+const unsupportedTypeReason = ""
+
+// appendFallback appends values that Value has no dedicated appender for.
+func appendFallback(val interface{}, buf []byte) ([]byte, error) {
+	return appendReflect(val, buf)
+}
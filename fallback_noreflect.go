@@ -0,0 +1,15 @@
+//go:build jsonappender_noreflect
+// +build jsonappender_noreflect
+
+package jsonappender
+
+// unsupportedTypeReason ends UnsupportedTypeError's message.
+const unsupportedTypeReason = " without reflection"
+
+// appendFallback refuses values that Value has no dedicated appender for because
+// there's no json.Marshal to fall back to.
+func appendFallback(val interface{}, buf []byte) ([]byte, error) {
+	return buf, &UnsupportedTypeError{
+		Value: val,
+	}
+}
@@ -0,0 +1,15 @@
+//go:build jsonappender_strict && !jsonappender_noreflect
+// +build jsonappender_strict,!jsonappender_noreflect
+
+package jsonappender
+
+// unsupportedTypeReason ends UnsupportedTypeError's message.
+const unsupportedTypeReason = " in strict mode"
+
+// appendFallback refuses values that Value has no dedicated appender for instead of
+// encoding them with json.Marshal.
+func appendFallback(val interface{}, buf []byte) ([]byte, error) {
+	return buf, &UnsupportedTypeError{
+		Value: val,
+	}
+}
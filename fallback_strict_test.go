@@ -0,0 +1,32 @@
+//go:build jsonappender_noreflect || jsonappender_strict
+// +build jsonappender_noreflect jsonappender_strict
+
+package jsonappender
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValue_strict(t *testing.T) {
+	val := struct{ A int }{A: 1}
+	_, err := Value(val, nil)
+	var unsupported *UnsupportedTypeError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedTypeError, got %v", err)
+	}
+	if unsupported.Value != val {
+		t.Errorf("got Value %v", unsupported.Value)
+	}
+	_, err = Value([]string{"a"}, nil)
+	if want := "jsonappender: unsupported type []string" + unsupportedTypeReason; err == nil || err.Error() != want {
+		t.Errorf("got error %v, wanted %s", err, want)
+	}
+	got, err := Value([]interface{}{"a", 1, map[string]interface{}{"b": true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `["a",1,{"b":true}]` {
+		t.Errorf("got %s", got)
+	}
+}
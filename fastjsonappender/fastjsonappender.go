@@ -0,0 +1,19 @@
+// Package fastjsonappender lets fastjson values be used wherever jsonappender expects a
+// JSONAppender. It is its own module so that jsonappender doesn't depend on fastjson.
+package fastjsonappender
+
+import (
+	"github.com/killa-beez/jsonappender"
+	"github.com/valyala/fastjson"
+)
+
+// Value returns a JSONAppender that writes v directly into the destination. A nil
+// v is written as null.
+func Value(v *fastjson.Value) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		return v.MarshalTo(buf), nil
+	})
+}
@@ -0,0 +1,26 @@
+package fastjsonappender
+
+import (
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/valyala/fastjson"
+)
+
+func TestValue(t *testing.T) {
+	v, err := fastjson.Parse(`{"a":[1,2,{"b":"c"}],"d":true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := jsonappender.Array([]interface{}{
+		Value(v),
+		Value(v.Get("a", "2")),
+		Value(v.Get("missing")),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `[{"a":[1,2,{"b":"c"}],"d":true},{"b":"c"},null]` {
+		t.Errorf("got %s", got)
+	}
+}
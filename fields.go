@@ -0,0 +1,129 @@
+package jsonappender
+
+import "time"
+
+// The Field methods write a whole object member: a comma when one is needed, the
+// escaped name, the colon and the value. A comma is written unless the member is the
+// first in its object, so a run of them between RawByte('{') and RawByte('}') needs no
+// comma bookkeeping. They look at the last byte written to tell, so they also work
+// after members written by hand.
+
+// memberComma writes a comma unless the last thing written opened an object or was a
+// comma.
+func (bw *BufWriter) memberComma() {
+	switch bw.last {
+	case 0, '{', ',':
+		return
+	}
+	bw.writeByte(',')
+}
+
+// StringField writes the member name with a string value.
+func (bw *BufWriter) StringField(name, val string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.String(val)
+}
+
+// Int64Field writes the member name with an int64 value.
+func (bw *BufWriter) Int64Field(name string, val int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Int64(val)
+}
+
+// Uint64Field writes the member name with a uint64 value.
+func (bw *BufWriter) Uint64Field(name string, val uint64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Uint64(val)
+}
+
+// Float64Field writes the member name with a float64 value.
+func (bw *BufWriter) Float64Field(name string, val float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Float64(val)
+}
+
+// BoolField writes the member name with a bool value.
+func (bw *BufWriter) BoolField(name string, val bool) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Bool(val)
+}
+
+// TimeField writes the member name with a time.Time value.
+func (bw *BufWriter) TimeField(name string, val time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Time(val)
+}
+
+// NullField writes the member name with null.
+func (bw *BufWriter) NullField(name string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Null()
+}
+
+// ObjectField writes the member name with an object value.
+func (bw *BufWriter) ObjectField(name string, mp map[string]interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Object(mp)
+}
+
+// ArrayField writes the member name with an array value.
+func (bw *BufWriter) ArrayField(name string, slice []interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Array(slice)
+}
+
+// ValueField writes the member name with any value.
+func (bw *BufWriter) ValueField(name string, val interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.FieldName(name)
+	bw.Value(val)
+}
+
+// KeyField writes the member k with any value.
+func (bw *BufWriter) KeyField(k Key, val interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.memberComma()
+	bw.Key(k)
+	bw.Value(val)
+}
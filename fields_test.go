@@ -0,0 +1,34 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.RawByte('{')
+	bw.StringField("s", "x")
+	bw.Int64Field("i", -1)
+	bw.Uint64Field("u", 2)
+	bw.Float64Field("f", 0.5)
+	bw.BoolField("b", false)
+	bw.TimeField("t", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	bw.NullField("n")
+	bw.ObjectField("o", map[string]interface{}{})
+	bw.RawString(`,"a":`)
+	bw.RawByte('{')
+	bw.ArrayField("a", []interface{}{})
+	bw.RawByte('}')
+	bw.ValueField("v", "y")
+	bw.KeyField(MustKey("k"), 3.0)
+	bw.RawByte('}')
+	bw.Flush()
+	want := `{"s":"x","i":-1,"u":2,"f":0.5,"b":false,"t":"2021-01-01T00:00:00Z","n":null,` +
+		`"o":{},"a":{"a":[]},"v":"y","k":3}`
+	if out.String() != want {
+		t.Errorf("got %s\nwanted %s", out.String(), want)
+	}
+}
@@ -0,0 +1,167 @@
+package jsonappender
+
+import (
+	"math"
+	"strconv"
+)
+
+// FloatOptions changes how floats are formatted. The zero value formats them like
+// encoding/json.
+type FloatOptions struct {
+	// DecimalPoint writes whole values with a decimal point, 2.0 instead of 2, for
+	// parsers that tell floats from integers by their text. Values written with an
+	// exponent already read as floats and are left alone.
+	DecimalPoint bool
+	// Precision, when more than 0, is the number of digits written after the decimal
+	// point. Values of 1e21 and up are still written with an exponent unless Fixed is
+	// set.
+	Precision int
+	// Fixed never writes an exponent, however large or small the value. Without a
+	// Precision it writes as many digits as needed to identify the value, so 1e21 is
+	// "1000000000000000000000" and 1e-7 is "0.0000001". With one, values smaller than
+	// the precision can show become 0.
+	Fixed bool
+	// NonFinite is what to do with NaN and infinities.
+	NonFinite NonFinitePolicy
+}
+
+// NonFinitePolicy is what float appenders do with NaN and infinities, which json
+// numbers can't represent.
+type NonFinitePolicy int
+
+const (
+	// NonFiniteError fails like encoding/json.
+	NonFiniteError NonFinitePolicy = iota
+	// NonFiniteNull writes null.
+	NonFiniteNull
+	// NonFiniteQuoted writes the strings "NaN", "Infinity" and "-Infinity", the
+	// spelling JavaScript and many json parsers accept.
+	NonFiniteQuoted
+)
+
+// Float64WithPolicy is like Float64 but handles NaN and infinities according to policy.
+func Float64WithPolicy(f float64, policy NonFinitePolicy, buf []byte) ([]byte, error) {
+	return appendFloatWithOptions(f, 64, FloatOptions{NonFinite: policy}, buf)
+}
+
+// NonFiniteNames are the strings Float64Named writes for NaN and infinities, for
+// formats that spell them their own way.
+type NonFiniteNames struct {
+	NaN    string
+	PosInf string
+	NegInf string
+}
+
+// Float64Named is like Float64 but writes NaN and infinities as the json strings in
+// names instead of failing.
+func Float64Named(f float64, names NonFiniteNames, buf []byte) []byte {
+	switch {
+	case math.IsNaN(f):
+		return String(names.NaN, buf)
+	case math.IsInf(f, 1):
+		return String(names.PosInf, buf)
+	case math.IsInf(f, -1):
+		return String(names.NegInf, buf)
+	}
+	buf, _ = appendFloat(f, 64, buf) //nolint:errcheck // f is finite
+	return buf
+}
+
+// appendNonFinite appends f, which is NaN or infinite, according to policy. ok is false
+// for NonFiniteError.
+func appendNonFinite(f float64, policy NonFinitePolicy, buf []byte) ([]byte, bool) {
+	switch policy {
+	case NonFiniteNull:
+		return Null(buf), true
+	case NonFiniteQuoted:
+		switch {
+		case math.IsNaN(f):
+			return append(buf, `"NaN"`...), true
+		case f > 0:
+			return append(buf, `"Infinity"`...), true
+		default:
+			return append(buf, `"-Infinity"`...), true
+		}
+	}
+	return buf, false
+}
+
+// Float64WithOptions is like Float64 but formats f according to opts.
+func Float64WithOptions(f float64, opts FloatOptions, buf []byte) ([]byte, error) {
+	return appendFloatWithOptions(f, 64, opts, buf)
+}
+
+// Float64Prec appends f with prec digits after the decimal point, or with the fewest
+// digits that identify it like Float64 when prec is negative. Values of 1e21 and up are
+// still written with an exponent. It fails for NaN and infinities.
+func Float64Prec(f float64, prec int, buf []byte) ([]byte, error) {
+	if prec >= 0 && fixedPrecOK(f) {
+		return strconv.AppendFloat(buf, f, 'f', prec, 64), nil
+	}
+	return appendFloat(f, 64, buf)
+}
+
+// Float64Prec writes f with prec digits after the decimal point. The BufWriter's
+// FloatOptions don't apply.
+func (bw *BufWriter) Float64Prec(f float64, prec int) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = Float64Prec(f, prec, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// fixedPrecOK reports whether f can be written with a fixed number of decimals.
+func fixedPrecOK(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f) && math.Abs(f) < 1e21
+}
+
+func appendFloatWithOptions(f float64, bits int, opts FloatOptions, buf []byte) ([]byte, error) {
+	if opts.NonFinite != NonFiniteError && (math.IsInf(f, 0) || math.IsNaN(f)) {
+		if b, ok := appendNonFinite(f, opts.NonFinite, buf); ok {
+			return b, nil
+		}
+	}
+	if opts.Fixed && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		prec := opts.Precision
+		if prec <= 0 {
+			prec = -1
+		}
+		start := len(buf)
+		buf = strconv.AppendFloat(buf, f, 'f', prec, bits)
+		if opts.DecimalPoint {
+			buf = appendDecimalPoint(buf, start)
+		}
+		return buf, nil
+	}
+	if opts.Precision > 0 && fixedPrecOK(f) {
+		return strconv.AppendFloat(buf, f, 'f', opts.Precision, bits), nil
+	}
+	start := len(buf)
+	buf, err := appendFloat(f, bits, buf)
+	if err != nil || !opts.DecimalPoint {
+		return buf, err
+	}
+	return appendDecimalPoint(buf, start), nil
+}
+
+// appendDecimalPoint adds ".0" to the number at buf[start:] unless it has a decimal
+// point or an exponent.
+func appendDecimalPoint(buf []byte, start int) []byte {
+	for _, c := range buf[start:] {
+		if c == '.' || c == 'e' {
+			return buf
+		}
+	}
+	return append(buf, '.', '0')
+}
+
+// SetFloatOptions sets the options Float64, Float32, Float64Array and Float64Matrix
+// format with.
+func (bw *BufWriter) SetFloatOptions(opts FloatOptions) {
+	bw.floatOpts = opts
+}
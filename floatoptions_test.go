@@ -0,0 +1,156 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestFloat64WithOptions(t *testing.T) {
+	opts := FloatOptions{DecimalPoint: true}
+	for _, td := range []struct {
+		f    float64
+		want string
+	}{
+		{f: 2, want: "2.0"},
+		{f: -0.0, want: "0.0"},
+		{f: -3, want: "-3.0"},
+		{f: 2.5, want: "2.5"},
+		{f: 1e21, want: "1e+21"},
+		{f: 1e20, want: "100000000000000000000.0"},
+		{f: 1e-7, want: "1e-7"},
+	} {
+		got, err := Float64WithOptions(td.f, opts, []byte("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "x"+td.want {
+			t.Errorf("%v: got %s, wanted x%s", td.f, got, td.want)
+		}
+	}
+
+	got, err := Float64WithOptions(1.0/3, FloatOptions{Precision: 3, DecimalPoint: true}, nil)
+	if err != nil || string(got) != "0.333" {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = Float64WithOptions(1e22, FloatOptions{Precision: 3}, nil)
+	if err != nil || string(got) != "1e+22" {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Float64(2)
+	bw.SetFloatOptions(opts)
+	bw.RawByte(',')
+	bw.Float64(2)
+	bw.Flush()
+	if out.String() != "2,2.0" {
+		t.Errorf("got %q, wanted %q", out.String(), "2,2.0")
+	}
+}
+
+func TestFloat64Prec(t *testing.T) {
+	for _, td := range []struct {
+		f    float64
+		prec int
+		want string
+	}{
+		{f: 12.345, prec: 2, want: "12.35"},
+		{f: 2, prec: 0, want: "2"},
+		{f: 2, prec: 3, want: "2.000"},
+		{f: 51.5007292, prec: 6, want: "51.500729"},
+		{f: 0.1, prec: -1, want: "0.1"},
+		{f: 1e21, prec: 2, want: "1e+21"},
+	} {
+		got, err := Float64Prec(td.f, td.prec, []byte("x"))
+		if err != nil || string(got) != "x"+td.want {
+			t.Errorf("%v, %d: got %s, %v, wanted x%s", td.f, td.prec, got, err, td.want)
+		}
+	}
+	if _, err := Float64Prec(math.Inf(1), 2, nil); err == nil {
+		t.Error("expected an error for +Inf")
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetFloatOptions(FloatOptions{Precision: 5})
+	bw.Float64Prec(1.0/3, 2)
+	bw.Flush()
+	if out.String() != "0.33" {
+		t.Errorf("got %q", out.String())
+	}
+}
+
+func TestFloat64WithPolicy(t *testing.T) {
+	for _, td := range []struct {
+		f      float64
+		policy NonFinitePolicy
+		want   string
+	}{
+		{f: math.NaN(), policy: NonFiniteNull, want: "null"},
+		{f: math.Inf(-1), policy: NonFiniteNull, want: "null"},
+		{f: math.NaN(), policy: NonFiniteQuoted, want: `"NaN"`},
+		{f: math.Inf(1), policy: NonFiniteQuoted, want: `"Infinity"`},
+		{f: math.Inf(-1), policy: NonFiniteQuoted, want: `"-Infinity"`},
+		{f: 1.5, policy: NonFiniteQuoted, want: "1.5"},
+	} {
+		got, err := Float64WithPolicy(td.f, td.policy, []byte("x"))
+		if err != nil || string(got) != "x"+td.want {
+			t.Errorf("%v: got %s, %v, wanted x%s", td.f, got, err, td.want)
+		}
+	}
+	if _, err := Float64WithPolicy(math.NaN(), NonFiniteError, nil); err == nil {
+		t.Error("expected an error for NaN")
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetFloatOptions(FloatOptions{NonFinite: NonFiniteNull})
+	bw.Float64(math.NaN())
+	bw.RawByte(',')
+	bw.Float32(float32(math.Inf(1)))
+	bw.RawByte(',')
+	bw.Float64Array([]float64{1, math.Inf(-1)})
+	bw.Flush()
+	if bw.Error != nil {
+		t.Fatal(bw.Error)
+	}
+	if want := "null,null,[1,null]"; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
+
+func TestFloat64WithOptionsFixed(t *testing.T) {
+	for _, td := range []struct {
+		f    float64
+		opts FloatOptions
+		want string
+	}{
+		{f: 1e21, opts: FloatOptions{Fixed: true}, want: "1000000000000000000000"},
+		{f: 1e-7, opts: FloatOptions{Fixed: true}, want: "0.0000001"},
+		{f: 1e21, opts: FloatOptions{Fixed: true, DecimalPoint: true}, want: "1000000000000000000000.0"},
+		{f: 1e22, opts: FloatOptions{Fixed: true, Precision: 2}, want: "10000000000000000000000.00"},
+		{f: 1e-7, opts: FloatOptions{Fixed: true, Precision: 3}, want: "0.000"},
+		{f: 2.5, opts: FloatOptions{Fixed: true}, want: "2.5"},
+	} {
+		got, err := Float64WithOptions(td.f, td.opts, nil)
+		if err != nil || string(got) != td.want {
+			t.Errorf("%v: got %s, %v, wanted %s", td.f, got, err, td.want)
+		}
+	}
+	if _, err := Float64WithOptions(math.NaN(), FloatOptions{Fixed: true}, nil); err == nil {
+		t.Error("expected an error for NaN")
+	}
+}
+
+func TestFloat64Named(t *testing.T) {
+	names := NonFiniteNames{NaN: "NaN", PosInf: "+Inf", NegInf: "-Inf"}
+	var got []byte
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e21, -0.5} {
+		got = append(Float64Named(f, names, got), ',')
+	}
+	if want := `"NaN","+Inf","-Inf",1e+21,-0.5,`; string(got) != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+}
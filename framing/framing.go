@@ -0,0 +1,191 @@
+// Package framing reads and writes files of json documents that each start with their
+// length and a checksum. Any document can be read given its offset, corruption is
+// detected instead of yielding bad json, and a reader can pick up where it left off
+// after a crash cut the last document short.
+//
+// Each frame is an 8 byte header, the document's length and the CRC-32C of the
+// document as big-endian uint32s, followed by the document.
+package framing
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"strconv"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// HeaderSize is the size of a frame's header.
+const HeaderSize = 8
+
+// DefaultMaxSize is the largest document a Reader accepts unless told otherwise.
+const DefaultMaxSize = 64 << 20
+
+var table = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrTooLarge is returned for a document bigger than a uint32 can describe.
+var ErrTooLarge = errors.New("framing: document too large")
+
+// CorruptFrameError is returned for a frame whose checksum doesn't match or whose
+// length is over the Reader's MaxSize.
+type CorruptFrameError struct {
+	Offset int64
+}
+
+func (e *CorruptFrameError) Error() string {
+	return "framing: corrupt frame at offset " + strconv.FormatInt(e.Offset, 10)
+}
+
+// Writer writes frames to an io.Writer.
+type Writer struct {
+	w      io.Writer
+	offset int64
+	buf    []byte
+}
+
+// NewWriter returns a Writer that writes to w. offset is where w is positioned in the
+// file, 0 for a new file or the file's size when appending to one.
+func NewWriter(w io.Writer, offset int64) *Writer {
+	return &Writer{
+		w:      w,
+		offset: offset,
+	}
+}
+
+// Write writes doc as a frame and returns the frame's offset.
+func (w *Writer) Write(doc []byte) (int64, error) {
+	w.buf = append(w.buf[:0], make([]byte, HeaderSize)...)
+	w.buf = append(w.buf, doc...)
+	return w.writeBuf()
+}
+
+// WriteAppender writes the output of a as a frame and returns the frame's offset.
+// Nothing is written when a fails.
+func (w *Writer) WriteAppender(a jsonappender.JSONAppender) (int64, error) {
+	buf, err := a.AppendJSON(append(w.buf[:0], make([]byte, HeaderSize)...))
+	if err != nil {
+		return 0, err
+	}
+	w.buf = buf
+	return w.writeBuf()
+}
+
+// writeBuf fills in the header in front of the document in w.buf and writes it.
+func (w *Writer) writeBuf() (int64, error) {
+	doc := w.buf[HeaderSize:]
+	if uint64(len(doc)) > 1<<32-1 {
+		return 0, ErrTooLarge
+	}
+	binary.BigEndian.PutUint32(w.buf, uint32(len(doc)))
+	binary.BigEndian.PutUint32(w.buf[4:], crc32.Checksum(doc, table))
+	offset := w.offset
+	n, err := w.w.Write(w.buf)
+	w.offset += int64(n)
+	if err == nil && n < len(w.buf) {
+		err = io.ErrShortWrite
+	}
+	return offset, err
+}
+
+// Offset returns the offset the next frame will be written at.
+func (w *Writer) Offset() int64 {
+	return w.offset
+}
+
+// Reader reads frames in order.
+type Reader struct {
+	// MaxSize is the largest document accepted. It defaults to DefaultMaxSize.
+	MaxSize int
+
+	r      io.Reader
+	offset int64
+	header [HeaderSize]byte
+	buf    []byte
+}
+
+// NewReader returns a Reader that reads from r positioned at offset.
+func NewReader(r io.Reader, offset int64) *Reader {
+	return &Reader{
+		r:      r,
+		offset: offset,
+	}
+}
+
+// Next returns the next document, which is only valid until the next call. It returns
+// io.EOF at the end of the input and io.ErrUnexpectedEOF when the last frame was cut
+// short. After an error Offset is still the start of the frame that failed, so a
+// writer can truncate the file there and carry on.
+func (r *Reader) Next() ([]byte, error) {
+	_, err := io.ReadFull(r.r, r.header[:])
+	if err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(r.header[:])
+	maxSize := r.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if uint64(size) > uint64(maxSize) {
+		return nil, &CorruptFrameError{Offset: r.offset}
+	}
+	if cap(r.buf) < int(size) {
+		r.buf = make([]byte, size)
+	}
+	r.buf = r.buf[:size]
+	_, err = io.ReadFull(r.r, r.buf)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(r.buf, table) != binary.BigEndian.Uint32(r.header[4:]) {
+		return nil, &CorruptFrameError{Offset: r.offset}
+	}
+	r.offset += HeaderSize + int64(size)
+	return r.buf, nil
+}
+
+// Offset returns the offset of the next frame.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// ReadAt reads the document in the frame at offset, appending it to buf. maxSize
+// limits the document's size like Reader.MaxSize.
+func ReadAt(ra io.ReaderAt, offset int64, maxSize int, buf []byte) ([]byte, error) {
+	var header [HeaderSize]byte
+	_, err := ra.ReadAt(header[:], offset)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if uint64(size) > uint64(maxSize) {
+		return buf, &CorruptFrameError{Offset: offset}
+	}
+	start := len(buf)
+	buf = append(buf, make([]byte, size)...)
+	n, err := ra.ReadAt(buf[start:], offset+HeaderSize)
+	if n == int(size) {
+		// ReaderAt may return io.EOF with the last bytes of the file
+		err = nil
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf[:start], err
+	}
+	if crc32.Checksum(buf[start:], table) != binary.BigEndian.Uint32(header[4:]) {
+		return buf[:start], &CorruptFrameError{Offset: offset}
+	}
+	return buf, nil
+}
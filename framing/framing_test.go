@@ -0,0 +1,106 @@
+package framing
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestWriterReader(t *testing.T) {
+	var file bytes.Buffer
+	w := NewWriter(&file, 0)
+	docs := []string{`{"a":1}`, `[]`, `"x"`}
+	var offsets []int64
+	for _, doc := range docs {
+		off, err := w.Write([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		offsets = append(offsets, off)
+	}
+	off, err := w.WriteAppender(jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return jsonappender.Int64(42, buf), nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs = append(docs, "42")
+	offsets = append(offsets, off)
+	if _, err := w.WriteAppender(jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, "partial"...), errors.New("failed")
+	})); err == nil {
+		t.Error("expected the appender's error")
+	}
+	if w.Offset() != int64(file.Len()) {
+		t.Errorf("got offset %d, wanted %d", w.Offset(), file.Len())
+	}
+
+	r := NewReader(bytes.NewReader(file.Bytes()), 0)
+	for i, want := range docs {
+		if r.Offset() != offsets[i] {
+			t.Errorf("doc %d: got offset %d, wanted %d", i, r.Offset(), offsets[i])
+		}
+		got, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("doc %d: got %s, wanted %s", i, got, want)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("got %v, wanted io.EOF", err)
+	}
+
+	ra := bytes.NewReader(file.Bytes())
+	got, err := ReadAt(ra, offsets[3], 0, []byte("x"))
+	if err != nil || string(got) != "x42" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestReader_damaged(t *testing.T) {
+	var file bytes.Buffer
+	w := NewWriter(&file, 0)
+	for _, doc := range []string{`{"a":1}`, `{"b":2}`} {
+		if _, err := w.Write([]byte(doc)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	second := int64(HeaderSize + len(`{"a":1}`))
+
+	truncated := file.Bytes()[:file.Len()-2]
+	r := NewReader(bytes.NewReader(truncated), 0)
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v, wanted io.ErrUnexpectedEOF", err)
+	}
+	if r.Offset() != second {
+		t.Errorf("got offset %d, wanted %d", r.Offset(), second)
+	}
+
+	corrupt := append([]byte(nil), file.Bytes()...)
+	corrupt[second+HeaderSize+2] = 'c'
+	r = NewReader(bytes.NewReader(corrupt), 0)
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	var cfe *CorruptFrameError
+	if _, err := r.Next(); !errors.As(err, &cfe) || cfe.Offset != second {
+		t.Errorf("got %v, wanted a CorruptFrameError at %d", err, second)
+	}
+	if _, err := ReadAt(bytes.NewReader(corrupt), second, 0, nil); !errors.As(err, &cfe) {
+		t.Errorf("got %v, wanted a CorruptFrameError", err)
+	}
+
+	r = NewReader(bytes.NewReader(file.Bytes()), 0)
+	r.MaxSize = 3
+	if _, err := r.Next(); !errors.As(err, &cfe) || cfe.Offset != 0 {
+		t.Errorf("got %v, wanted a CorruptFrameError at 0", err)
+	}
+}
@@ -0,0 +1,225 @@
+// Package gcplogging builds structured log entries that Google Cloud Logging parses
+// from a container's stdout. Write one entry per line.
+package gcplogging
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Severity is the severity of an entry.
+type Severity string
+
+// Severities Cloud Logging understands.
+const (
+	Default   Severity = "DEFAULT"
+	Debug     Severity = "DEBUG"
+	Info      Severity = "INFO"
+	Notice    Severity = "NOTICE"
+	Warning   Severity = "WARNING"
+	Error     Severity = "ERROR"
+	Critical  Severity = "CRITICAL"
+	Alert     Severity = "ALERT"
+	Emergency Severity = "EMERGENCY"
+)
+
+// Field is an additional member of the entry's json payload. Value can be anything
+// jsonappender.Value accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Label is an entry label.
+type Label struct {
+	Key   string
+	Value string
+}
+
+// SourceLocation is where in the code the entry was logged.
+type SourceLocation struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// HTTPRequest describes the request an entry is about. Empty fields are omitted.
+type HTTPRequest struct {
+	RequestMethod string
+	RequestURL    string
+	RequestSize   int64
+	Status        int
+	ResponseSize  int64
+	UserAgent     string
+	RemoteIP      string
+	ServerIP      string
+	Referer       string
+	Latency       time.Duration
+	Protocol      string
+	CacheHit      bool
+}
+
+// Entry is one log entry. Empty fields are omitted.
+type Entry struct {
+	Severity Severity
+	Message  string
+	Time     time.Time
+	Labels   []Label
+	// Trace is the trace's resource name. TraceName builds one.
+	Trace          string
+	SpanID         string
+	TraceSampled   bool
+	SourceLocation *SourceLocation
+	HTTPRequest    *HTTPRequest
+	// Fields are written in order after the fields above.
+	Fields []Field
+}
+
+// TraceName returns the resource name Cloud Logging expects in Entry.Trace.
+func TraceName(projectID, traceID string) string {
+	return "projects/" + projectID + "/traces/" + traceID
+}
+
+// AppendJSON implements jsonappender.JSONAppender.
+func (e *Entry) AppendJSON(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	comma := false
+	member := func(name string) {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = jsonappender.FieldName(name, buf)
+	}
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "severity", value: string(e.Severity)},
+		{name: "message", value: e.Message},
+		{name: "logging.googleapis.com/trace", value: e.Trace},
+		{name: "logging.googleapis.com/spanId", value: e.SpanID},
+	} {
+		if f.value == "" {
+			continue
+		}
+		member(f.name)
+		buf = jsonappender.String(f.value, buf)
+	}
+	if e.TraceSampled {
+		member("logging.googleapis.com/trace_sampled")
+		buf = jsonappender.Bool(true, buf)
+	}
+	if !e.Time.IsZero() {
+		member("time")
+		tm, err := jsonappender.Time(e.Time, buf)
+		if err != nil {
+			return buf, err
+		}
+		buf = tm
+	}
+	if len(e.Labels) > 0 {
+		member("logging.googleapis.com/labels")
+		buf = append(buf, '{')
+		for i, l := range e.Labels {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = jsonappender.FieldName(l.Key, buf)
+			buf = jsonappender.String(l.Value, buf)
+		}
+		buf = append(buf, '}')
+	}
+	if e.SourceLocation != nil {
+		member("logging.googleapis.com/sourceLocation")
+		buf = appendSourceLocation(e.SourceLocation, buf)
+	}
+	if e.HTTPRequest != nil {
+		member("httpRequest")
+		buf = appendHTTPRequest(e.HTTPRequest, buf)
+	}
+	var err error
+	for _, f := range e.Fields {
+		member(f.Name)
+		buf, err = jsonappender.Value(f.Value, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+func appendSourceLocation(loc *SourceLocation, buf []byte) []byte {
+	buf = append(buf, `{"file":`...)
+	buf = jsonappender.String(loc.File, buf)
+	// line is an int64 in the API, so it's a string in json
+	buf = append(buf, `,"line":"`...)
+	buf = jsonappender.Int64(int64(loc.Line), buf)
+	buf = append(buf, '"')
+	if loc.Function != "" {
+		buf = append(buf, `,"function":`...)
+		buf = jsonappender.String(loc.Function, buf)
+	}
+	return append(buf, '}')
+}
+
+func appendHTTPRequest(r *HTTPRequest, buf []byte) []byte {
+	buf = append(buf, '{')
+	comma := false
+	member := func(name string) {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = jsonappender.FieldName(name, buf)
+	}
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "requestMethod", value: r.RequestMethod},
+		{name: "requestUrl", value: r.RequestURL},
+		{name: "userAgent", value: r.UserAgent},
+		{name: "remoteIp", value: r.RemoteIP},
+		{name: "serverIp", value: r.ServerIP},
+		{name: "referer", value: r.Referer},
+		{name: "protocol", value: r.Protocol},
+	} {
+		if f.value == "" {
+			continue
+		}
+		member(f.name)
+		buf = jsonappender.String(f.value, buf)
+	}
+	// sizes are int64 in the API, so they're strings in json
+	if r.RequestSize != 0 {
+		member("requestSize")
+		buf = append(buf, '"')
+		buf = jsonappender.Int64(r.RequestSize, buf)
+		buf = append(buf, '"')
+	}
+	if r.ResponseSize != 0 {
+		member("responseSize")
+		buf = append(buf, '"')
+		buf = jsonappender.Int64(r.ResponseSize, buf)
+		buf = append(buf, '"')
+	}
+	if r.Status != 0 {
+		member("status")
+		buf = jsonappender.Int64(int64(r.Status), buf)
+	}
+	if r.Latency != 0 {
+		// a protobuf Duration in json is seconds with an "s" suffix
+		member("latency")
+		buf = append(buf, '"')
+		buf = strconv.AppendFloat(buf, r.Latency.Seconds(), 'f', -1, 64)
+		buf = append(buf, 's', '"')
+	}
+	if r.CacheHit {
+		member("cacheHit")
+		buf = jsonappender.Bool(true, buf)
+	}
+	return append(buf, '}')
+}
@@ -0,0 +1,50 @@
+package gcplogging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEntry(t *testing.T) {
+	e := &Entry{
+		Severity:       Warning,
+		Message:        "slow request",
+		Time:           time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Labels:         []Label{{Key: "pod", Value: "web-1"}},
+		Trace:          TraceName("my-project", "06796866738c859f2f19b7cfb3214824"),
+		SpanID:         "000000000000004a",
+		TraceSampled:   true,
+		SourceLocation: &SourceLocation{File: "main.go", Line: 42, Function: "main.handle"},
+		HTTPRequest: &HTTPRequest{
+			RequestMethod: "GET",
+			RequestURL:    "/a?b=c",
+			Status:        200,
+			ResponseSize:  1024,
+			Latency:       1500 * time.Millisecond,
+		},
+		Fields: []Field{{Name: "attempt", Value: 2}},
+	}
+	got, err := e.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"severity":"WARNING","message":"slow request",` +
+		`"logging.googleapis.com/trace":"projects/my-project/traces/06796866738c859f2f19b7cfb3214824",` +
+		`"logging.googleapis.com/spanId":"000000000000004a","logging.googleapis.com/trace_sampled":true,` +
+		`"time":"2020-01-02T03:04:05Z","logging.googleapis.com/labels":{"pod":"web-1"},` +
+		`"logging.googleapis.com/sourceLocation":{"file":"main.go","line":"42","function":"main.handle"},` +
+		`"httpRequest":{"requestMethod":"GET","requestUrl":"/a?b=c","responseSize":"1024","status":200,"latency":"1.5s"},` +
+		`"attempt":2}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !json.Valid(got) {
+		t.Error("invalid json")
+	}
+
+	got, err = (&Entry{}).AppendJSON(got[:0])
+	if err != nil || string(got) != "{}" {
+		t.Errorf("got %s %v", got, err)
+	}
+}
@@ -0,0 +1,40 @@
+// Package gjsonappender lets gjson results be used wherever jsonappender expects a
+// JSONAppender. It is its own module so that jsonappender doesn't depend on gjson.
+package gjsonappender
+
+import (
+	"errors"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/tidwall/gjson"
+)
+
+// Result returns a JSONAppender that writes r. The raw json is copied as-is when r
+// holds valid json. Otherwise r is encoded from its type and value so results built
+// without raw json still come out right. A result that doesn't exist is written as
+// null.
+func Result(r gjson.Result) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return AppendResult(r, buf)
+	})
+}
+
+// AppendResult appends r the same way as Result.
+func AppendResult(r gjson.Result, buf []byte) ([]byte, error) {
+	if r.Raw != "" && gjson.Valid(r.Raw) {
+		return append(buf, r.Raw...), nil
+	}
+	switch r.Type {
+	case gjson.Null:
+		return append(buf, "null"...), nil
+	case gjson.False:
+		return jsonappender.Bool(false, buf), nil
+	case gjson.True:
+		return jsonappender.Bool(true, buf), nil
+	case gjson.Number:
+		return jsonappender.Float64(r.Num, buf)
+	case gjson.String:
+		return jsonappender.String(r.Str, buf), nil
+	}
+	return buf, errors.New("gjsonappender: result is not valid json")
+}
@@ -0,0 +1,35 @@
+package gjsonappender
+
+import (
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/tidwall/gjson"
+)
+
+func TestResult(t *testing.T) {
+	doc := `{"a":[1,2.50,{"b":"cé"}],"d":true,"e":null}`
+	got, err := jsonappender.Array([]interface{}{
+		Result(gjson.Get(doc, "a")),
+		Result(gjson.Get(doc, "a.1")),
+		Result(gjson.Get(doc, "a.2.b")),
+		Result(gjson.Get(doc, "d")),
+		Result(gjson.Get(doc, "e")),
+		Result(gjson.Get(doc, "missing")),
+		Result(gjson.Get(doc, "a.#")),
+		Result(gjson.Result{Type: gjson.String, Str: "<x>"}),
+		Result(gjson.Result{Type: gjson.Number, Num: 3}),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[[1,2.50,{"b":"cé"}],2.50,"cé",true,null,null,3,"\u003cx\u003e",3]`
+	if string(got) != want {
+		t.Errorf("got %s", got)
+	}
+
+	_, err = AppendResult(gjson.Result{Type: gjson.JSON, Raw: `{"a"`}, nil)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/killa-beez/jsonappender/gjsonappender
+
+go 1.15
+
+require (
+	github.com/killa-beez/jsonappender v0.0.0-00010101000000-000000000000
+	github.com/tidwall/gjson v1.17.3
+)
+
+replace github.com/killa-beez/jsonappender => ../
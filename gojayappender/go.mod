@@ -0,0 +1,10 @@
+module github.com/killa-beez/jsonappender/gojayappender
+
+go 1.15
+
+require (
+	github.com/francoispqt/gojay v1.2.13
+	github.com/killa-beez/jsonappender v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/killa-beez/jsonappender => ../
@@ -0,0 +1,47 @@
+// Package gojayappender lets gojay marshalers be used wherever jsonappender expects a
+// JSONAppender. It is its own module so that jsonappender doesn't depend on gojay.
+package gojayappender
+
+import (
+	"github.com/francoispqt/gojay"
+	"github.com/killa-beez/jsonappender"
+)
+
+// Object returns a JSONAppender that encodes v with a pooled gojay.Encoder. The
+// encoder's buffer is copied straight into the destination.
+func Object(v gojay.MarshalerJSONObject) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		sw := sliceWriter{buf: buf}
+		enc := gojay.BorrowEncoder(&sw)
+		defer enc.Release()
+		err := enc.EncodeObject(v)
+		if err != nil {
+			return buf, err
+		}
+		return sw.buf, nil
+	})
+}
+
+// Array is like Object for gojay.MarshalerJSONArray.
+func Array(v gojay.MarshalerJSONArray) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		sw := sliceWriter{buf: buf}
+		enc := gojay.BorrowEncoder(&sw)
+		defer enc.Release()
+		err := enc.EncodeArray(v)
+		if err != nil {
+			return buf, err
+		}
+		return sw.buf, nil
+	})
+}
+
+// sliceWriter is an io.Writer that appends to buf.
+type sliceWriter struct {
+	buf []byte
+}
+
+func (sw *sliceWriter) Write(p []byte) (int, error) {
+	sw.buf = append(sw.buf, p...)
+	return len(p), nil
+}
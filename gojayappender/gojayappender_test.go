@@ -0,0 +1,57 @@
+package gojayappender
+
+import (
+	"testing"
+
+	"github.com/francoispqt/gojay"
+	"github.com/killa-beez/jsonappender"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func (u *user) MarshalJSONObject(enc *gojay.Encoder) {
+	enc.IntKey("id", u.ID)
+	enc.StringKey("name", u.Name)
+}
+
+func (u *user) IsNil() bool {
+	return u == nil
+}
+
+type users []*user
+
+func (u users) MarshalJSONArray(enc *gojay.Encoder) {
+	for _, e := range u {
+		enc.Object(e)
+	}
+}
+
+func (u users) IsNil() bool {
+	return u == nil
+}
+
+func TestObject(t *testing.T) {
+	got, err := jsonappender.Value(Object(&user{ID: 1, Name: "a"}), []byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `x{"id":1,"name":"a"}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestArray(t *testing.T) {
+	got, err := jsonappender.Array([]interface{}{
+		Array(users{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}),
+		Array(users{}),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `[[{"id":1,"name":"a"},{"id":2,"name":"b"}],[]]` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,293 @@
+// Package har writes HTTP Archive (HAR) 1.2 files. Entries are streamed to the output
+// as they're written, so a whole session never has to be held in memory.
+package har
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// ErrClosed is returned when writing to a closed Writer.
+var ErrClosed = errors.New("har: writer is closed")
+
+// Creator names the application that created the archive.
+type Creator struct {
+	Name    string
+	Version string
+}
+
+// NameValue is a header, cookie or query string parameter.
+type NameValue struct {
+	Name  string
+	Value string
+}
+
+// PostData is a request body.
+type PostData struct {
+	MimeType string
+	Text     string
+}
+
+// Request is the request of an entry. Use -1 for sizes that are unknown.
+type Request struct {
+	Method      string
+	URL         string
+	HTTPVersion string
+	Cookies     []NameValue
+	Headers     []NameValue
+	QueryString []NameValue
+	PostData    *PostData
+	HeadersSize int64
+	BodySize    int64
+}
+
+// Content is a response body. Text and Encoding are omitted when empty.
+type Content struct {
+	Size     int64
+	MimeType string
+	Text     string
+	Encoding string
+}
+
+// Response is the response of an entry. Use -1 for sizes that are unknown.
+type Response struct {
+	Status      int
+	StatusText  string
+	HTTPVersion string
+	Cookies     []NameValue
+	Headers     []NameValue
+	Content     Content
+	RedirectURL string
+	HeadersSize int64
+	BodySize    int64
+}
+
+// Timings are the phases of an entry. A negative duration is written as -1, which HAR
+// uses for phases that don't apply.
+type Timings struct {
+	Blocked time.Duration
+	DNS     time.Duration
+	Connect time.Duration
+	Send    time.Duration
+	Wait    time.Duration
+	Receive time.Duration
+	SSL     time.Duration
+}
+
+// Total returns the sum of the timings that apply, not counting SSL which HAR includes
+// in Connect.
+func (t *Timings) Total() time.Duration {
+	var total time.Duration
+	for _, d := range [...]time.Duration{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if d > 0 {
+			total += d
+		}
+	}
+	return total
+}
+
+// Entry is one request and response. The entry's total "time" is Timings.Total().
+type Entry struct {
+	Started         time.Time
+	Request         Request
+	Response        Response
+	Timings         Timings
+	ServerIPAddress string
+	Connection      string
+}
+
+// Writer writes a HAR document. Call Close to finish it.
+//
+// A Writer is not safe for concurrent use.
+type Writer struct {
+	bw      *jsonappender.BufWriter
+	buf     []byte
+	entries int
+	closed  bool
+}
+
+// NewWriter writes the start of a HAR document to w and returns a Writer for its
+// entries.
+func NewWriter(w io.Writer, creator Creator) *Writer {
+	hw := &Writer{
+		bw: jsonappender.NewBufWriter(w),
+	}
+	hw.bw.RawString(`{"log":{"version":"1.2","creator":{"name":`)
+	hw.bw.String(creator.Name)
+	hw.bw.RawString(`,"version":`)
+	hw.bw.String(creator.Version)
+	hw.bw.RawString(`},"entries":[`)
+	return hw
+}
+
+// WriteEntry writes e. Entries are buffered; call Flush to push them to the underlying
+// writer.
+func (w *Writer) WriteEntry(e *Entry) error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.buf = w.buf[:0]
+	if w.entries > 0 {
+		w.buf = append(w.buf, ',')
+	}
+	var err error
+	w.buf, err = AppendEntry(e, w.buf)
+	if err != nil {
+		return err
+	}
+	w.bw.Raw(w.buf)
+	w.entries++
+	return w.bw.Error
+}
+
+// Flush writes buffered entries to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.bw.Flush()
+}
+
+// Close finishes the document and flushes it. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	w.bw.RawString("]}}")
+	return w.bw.Flush()
+}
+
+// AppendEntry appends e as a HAR entry object.
+func AppendEntry(e *Entry, buf []byte) ([]byte, error) {
+	buf = append(buf, `{"startedDateTime":`...)
+	tm, err := jsonappender.Time(e.Started, buf)
+	if err != nil {
+		return buf, err
+	}
+	buf = tm
+	buf = append(buf, `,"time":`...)
+	buf = appendMillis(e.Timings.Total(), buf)
+	buf = append(buf, `,"request":`...)
+	buf = appendRequest(&e.Request, buf)
+	buf = append(buf, `,"response":`...)
+	buf = appendResponse(&e.Response, buf)
+	buf = append(buf, `,"cache":{},"timings":`...)
+	buf = appendTimings(&e.Timings, buf)
+	if e.ServerIPAddress != "" {
+		buf = append(buf, `,"serverIPAddress":`...)
+		buf = jsonappender.String(e.ServerIPAddress, buf)
+	}
+	if e.Connection != "" {
+		buf = append(buf, `,"connection":`...)
+		buf = jsonappender.String(e.Connection, buf)
+	}
+	return append(buf, '}'), nil
+}
+
+func appendRequest(r *Request, buf []byte) []byte {
+	buf = append(buf, `{"method":`...)
+	buf = jsonappender.String(r.Method, buf)
+	buf = append(buf, `,"url":`...)
+	buf = jsonappender.String(r.URL, buf)
+	buf = append(buf, `,"httpVersion":`...)
+	buf = jsonappender.String(r.HTTPVersion, buf)
+	buf = append(buf, `,"cookies":`...)
+	buf = appendNameValues(r.Cookies, buf)
+	buf = append(buf, `,"headers":`...)
+	buf = appendNameValues(r.Headers, buf)
+	buf = append(buf, `,"queryString":`...)
+	buf = appendNameValues(r.QueryString, buf)
+	if r.PostData != nil {
+		buf = append(buf, `,"postData":{"mimeType":`...)
+		buf = jsonappender.String(r.PostData.MimeType, buf)
+		buf = append(buf, `,"text":`...)
+		buf = jsonappender.String(r.PostData.Text, buf)
+		buf = append(buf, '}')
+	}
+	buf = append(buf, `,"headersSize":`...)
+	buf = jsonappender.Int64(r.HeadersSize, buf)
+	buf = append(buf, `,"bodySize":`...)
+	buf = jsonappender.Int64(r.BodySize, buf)
+	return append(buf, '}')
+}
+
+func appendResponse(r *Response, buf []byte) []byte {
+	buf = append(buf, `{"status":`...)
+	buf = jsonappender.Int64(int64(r.Status), buf)
+	buf = append(buf, `,"statusText":`...)
+	buf = jsonappender.String(r.StatusText, buf)
+	buf = append(buf, `,"httpVersion":`...)
+	buf = jsonappender.String(r.HTTPVersion, buf)
+	buf = append(buf, `,"cookies":`...)
+	buf = appendNameValues(r.Cookies, buf)
+	buf = append(buf, `,"headers":`...)
+	buf = appendNameValues(r.Headers, buf)
+	buf = append(buf, `,"content":{"size":`...)
+	buf = jsonappender.Int64(r.Content.Size, buf)
+	buf = append(buf, `,"mimeType":`...)
+	buf = jsonappender.String(r.Content.MimeType, buf)
+	if r.Content.Text != "" {
+		buf = append(buf, `,"text":`...)
+		buf = jsonappender.String(r.Content.Text, buf)
+	}
+	if r.Content.Encoding != "" {
+		buf = append(buf, `,"encoding":`...)
+		buf = jsonappender.String(r.Content.Encoding, buf)
+	}
+	buf = append(buf, `},"redirectURL":`...)
+	buf = jsonappender.String(r.RedirectURL, buf)
+	buf = append(buf, `,"headersSize":`...)
+	buf = jsonappender.Int64(r.HeadersSize, buf)
+	buf = append(buf, `,"bodySize":`...)
+	buf = jsonappender.Int64(r.BodySize, buf)
+	return append(buf, '}')
+}
+
+func appendTimings(t *Timings, buf []byte) []byte {
+	buf = append(buf, '{')
+	for i, f := range [...]struct {
+		name  string
+		value time.Duration
+	}{
+		{name: "blocked", value: t.Blocked},
+		{name: "dns", value: t.DNS},
+		{name: "connect", value: t.Connect},
+		{name: "send", value: t.Send},
+		{name: "wait", value: t.Wait},
+		{name: "receive", value: t.Receive},
+		{name: "ssl", value: t.SSL},
+	} {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = jsonappender.FieldName(f.name, buf)
+		if f.value < 0 {
+			buf = append(buf, '-', '1')
+			continue
+		}
+		buf = appendMillis(f.value, buf)
+	}
+	return append(buf, '}')
+}
+
+func appendNameValues(nvs []NameValue, buf []byte) []byte {
+	buf = append(buf, '[')
+	for i, nv := range nvs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"name":`...)
+		buf = jsonappender.String(nv.Name, buf)
+		buf = append(buf, `,"value":`...)
+		buf = jsonappender.String(nv.Value, buf)
+		buf = append(buf, '}')
+	}
+	return append(buf, ']')
+}
+
+// appendMillis appends d in milliseconds. Durations are always finite, so NaN and
+// infinities need no names.
+func appendMillis(d time.Duration, buf []byte) []byte {
+	return jsonappender.Float64Named(float64(d)/float64(time.Millisecond), jsonappender.NonFiniteNames{}, buf)
+}
@@ -0,0 +1,113 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out, Creator{Name: "proxy", Version: "1.0"})
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"log":{"version":"1.2","creator":{"name":"proxy","version":"1.0"},"entries":[]}}`; out.String() != want {
+		t.Errorf("got %s", out.String())
+	}
+	if err := w.WriteEntry(&Entry{}); err != ErrClosed {
+		t.Errorf("got error %v", err)
+	}
+
+	out.Reset()
+	w = NewWriter(&out, Creator{Name: "proxy"})
+	entry := &Entry{
+		Started: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Request: Request{
+			Method:      "POST",
+			URL:         "http://example.com/?q=1",
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []NameValue{{Name: "Host", Value: "example.com"}},
+			QueryString: []NameValue{{Name: "q", Value: "1"}},
+			PostData:    &PostData{MimeType: "text/plain", Text: "hi"},
+			HeadersSize: -1,
+			BodySize:    2,
+		},
+		Response: Response{
+			Status:      200,
+			StatusText:  "OK",
+			HTTPVersion: "HTTP/1.1",
+			Content:     Content{Size: 2, MimeType: "application/json", Text: "{}"},
+			HeadersSize: -1,
+			BodySize:    2,
+		},
+		Timings: Timings{
+			Blocked: -1,
+			DNS:     -1,
+			Connect: 2 * time.Millisecond,
+			Send:    500 * time.Microsecond,
+			Wait:    10 * time.Millisecond,
+			Receive: time.Millisecond,
+			SSL:     -1,
+		},
+		ServerIPAddress: "10.0.0.1",
+	}
+	for i := 0; i < 2; i++ {
+		err = w.WriteEntry(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Log struct {
+			Entries []struct {
+				Time    float64
+				Timings map[string]float64
+				Request struct {
+					PostData struct {
+						Text string
+					}
+				}
+				Response struct {
+					Content struct {
+						Text string
+					}
+				}
+			}
+		}
+	}
+	err = json.Unmarshal(out.Bytes(), &doc)
+	if err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	if len(doc.Log.Entries) != 2 {
+		t.Fatalf("got %d entries", len(doc.Log.Entries))
+	}
+	e := doc.Log.Entries[1]
+	if e.Time != 13.5 || e.Timings["dns"] != -1 || e.Timings["send"] != 0.5 {
+		t.Errorf("got time %v and timings %v", e.Time, e.Timings)
+	}
+	if e.Request.PostData.Text != "hi" || e.Response.Content.Text != "{}" {
+		t.Errorf("got %s", out.String())
+	}
+}
+
+func TestAppendEntry(t *testing.T) {
+	got, err := AppendEntry(&Entry{Started: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"startedDateTime":"2020-01-02T03:04:05Z","time":0,` +
+		`"request":{"method":"","url":"","httpVersion":"","cookies":[],"headers":[],"queryString":[],"headersSize":0,"bodySize":0},` +
+		`"response":{"status":0,"statusText":"","httpVersion":"","cookies":[],"headers":[],"content":{"size":0,"mimeType":""},"redirectURL":"","headersSize":0,"bodySize":0},` +
+		`"cache":{},"timings":{"blocked":0,"dns":0,"connect":0,"send":0,"wait":0,"receive":0,"ssl":0}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
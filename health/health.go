@@ -0,0 +1,197 @@
+// Package health builds health check documents in the application/health+json format
+// described by draft-inadarei-api-health-check.
+package health
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// ContentType is the media type of the documents StatusBuilder builds.
+const ContentType = "application/health+json"
+
+// ErrCheckReopened is the error from AppendJSON when a check was added after checks
+// with another Name followed checks with its Name. Checks with the same Name have to
+// be added one after the other.
+var ErrCheckReopened = errors.New("health: checks with the same name weren't added together")
+
+// Status is the status of a service or one of its checks.
+type Status string
+
+// Statuses in order of increasing severity.
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+)
+
+func (s Status) severity() int {
+	switch s {
+	case Pass, "":
+		return 0
+	case Warn:
+		return 1
+	}
+	return 2
+}
+
+// Check is the result of one health check. An empty Unit omits the observation, a zero
+// Latency omits "latencyMs", and a zero Time omits "time".
+type Check struct {
+	// Name is the check's key in "checks", conventionally "component:measurement".
+	Name    string
+	Status  Status
+	Time    time.Time
+	Latency time.Duration
+	Value   float64
+	Unit    string
+	Output  string
+}
+
+// StatusBuilder builds a health document from checks. The overall status is the most
+// severe status of its checks. A StatusBuilder keeps its buffers across Reset, so once
+// warmed up building a document doesn't allocate.
+//
+// A StatusBuilder is not safe for concurrent use.
+type StatusBuilder struct {
+	// These describe the service and are omitted when empty.
+	Version     string
+	ReleaseID   string
+	ServiceID   string
+	Description string
+
+	checks   []byte
+	status   Status
+	lastName string
+	// closed are the names whose arrays are done.
+	closed  []string
+	started bool
+	err     error
+}
+
+// Reset removes all checks. The service fields are kept.
+func (b *StatusBuilder) Reset() {
+	b.checks = b.checks[:0]
+	b.status = Pass
+	b.lastName = ""
+	b.closed = b.closed[:0]
+	b.started = false
+	b.err = nil
+}
+
+// Check adds the result of a check. Checks with the same Name are grouped into one
+// array and must be added one after the other, or AppendJSON fails with
+// ErrCheckReopened.
+func (b *StatusBuilder) Check(c Check) {
+	if b.err != nil {
+		return
+	}
+	if c.Status.severity() > b.status.severity() {
+		b.status = c.Status
+	}
+	switch {
+	case !b.started:
+		b.started = true
+		b.checks = jsonappender.FieldName(c.Name, b.checks)
+		b.checks = append(b.checks, '[')
+	case c.Name != b.lastName:
+		b.closed = append(b.closed, b.lastName)
+		for _, name := range b.closed {
+			if name == c.Name {
+				b.err = ErrCheckReopened
+				return
+			}
+		}
+		b.checks = append(b.checks, ']', ',')
+		b.checks = jsonappender.FieldName(c.Name, b.checks)
+		b.checks = append(b.checks, '[')
+	default:
+		b.checks = append(b.checks, ',')
+	}
+	b.lastName = c.Name
+
+	b.checks = append(b.checks, `{"status":`...)
+	b.checks = jsonappender.String(string(c.Status), b.checks)
+	if !c.Time.IsZero() {
+		b.checks = append(b.checks, `,"time":`...)
+		buf, err := jsonappender.Time(c.Time, b.checks)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.checks = buf
+	}
+	if c.Latency != 0 {
+		b.checks = append(b.checks, `,"latencyMs":`...)
+		b.checks, b.err = jsonappender.Float64(float64(c.Latency)/float64(time.Millisecond), b.checks)
+		if b.err != nil {
+			return
+		}
+	}
+	if c.Unit != "" {
+		b.checks = append(b.checks, `,"observedValue":`...)
+		b.checks, b.err = jsonappender.Float64(c.Value, b.checks)
+		if b.err != nil {
+			return
+		}
+		b.checks = append(b.checks, `,"observedUnit":`...)
+		b.checks = jsonappender.String(c.Unit, b.checks)
+	}
+	if c.Output != "" {
+		b.checks = append(b.checks, `,"output":`...)
+		b.checks = jsonappender.String(c.Output, b.checks)
+	}
+	b.checks = append(b.checks, '}')
+}
+
+// Status returns the overall status.
+func (b *StatusBuilder) Status() Status {
+	if b.status == "" {
+		return Pass
+	}
+	return b.status
+}
+
+// HTTPStatus returns the response code to serve the document with: 503 when the
+// overall status is Fail and 200 otherwise.
+func (b *StatusBuilder) HTTPStatus() int {
+	if b.Status() == Fail {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// AppendJSON implements jsonappender.JSONAppender. It returns the first error from
+// Check.
+func (b *StatusBuilder) AppendJSON(buf []byte) ([]byte, error) {
+	if b.err != nil {
+		return buf, b.err
+	}
+	buf = append(buf, `{"status":`...)
+	buf = jsonappender.String(string(b.Status()), buf)
+	for _, f := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "version", value: b.Version},
+		{name: "releaseId", value: b.ReleaseID},
+		{name: "serviceId", value: b.ServiceID},
+		{name: "description", value: b.Description},
+	} {
+		if f.value == "" {
+			continue
+		}
+		buf = append(buf, ',')
+		buf = jsonappender.FieldName(f.name, buf)
+		buf = jsonappender.String(f.value, buf)
+	}
+	if b.started {
+		buf = append(buf, `,"checks":{`...)
+		buf = append(buf, b.checks...)
+		buf = append(buf, ']', '}')
+	}
+	return append(buf, '}'), nil
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatusBuilder(t *testing.T) {
+	var b StatusBuilder
+	b.Version = "1"
+	b.ServiceID = "svc"
+	b.Reset()
+	got, err := b.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"status":"pass","version":"1","serviceId":"svc"}`; string(got) != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b.Check(Check{Name: "db:responseTime", Status: Pass, Time: at, Latency: 1500 * time.Microsecond})
+	b.Check(Check{Name: "db:responseTime", Status: Warn, Value: 0.5, Unit: "percent", Output: "slow"})
+	b.Check(Check{Name: "uptime", Status: Pass, Value: 12, Unit: "s"})
+	got, err = b.AppendJSON(got[:0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"status":"warn","version":"1","serviceId":"svc","checks":{` +
+		`"db:responseTime":[{"status":"pass","time":"2020-01-02T03:04:05Z","latencyMs":1.5},` +
+		`{"status":"warn","observedValue":0.5,"observedUnit":"percent","output":"slow"}],` +
+		`"uptime":[{"status":"pass","observedValue":12,"observedUnit":"s"}]}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwanted %s", got, want)
+	}
+	if !json.Valid(got) {
+		t.Error("invalid json")
+	}
+	if b.HTTPStatus() != http.StatusOK {
+		t.Errorf("got HTTP status %d", b.HTTPStatus())
+	}
+
+	b.Check(Check{Name: "cache", Status: Fail})
+	if b.Status() != Fail || b.HTTPStatus() != http.StatusServiceUnavailable {
+		t.Errorf("got status %s", b.Status())
+	}
+
+	b.Reset()
+	b.Check(Check{Name: "x", Status: Pass, Value: math.NaN(), Unit: "s"})
+	if _, err := b.AppendJSON(nil); err == nil {
+		t.Error("expected error")
+	}
+
+	b.Reset()
+	b.Check(Check{Name: "a", Status: Pass})
+	b.Check(Check{Name: "b", Status: Pass})
+	b.Check(Check{Name: "a", Status: Pass})
+	if _, err := b.AppendJSON(nil); err != ErrCheckReopened {
+		t.Errorf("got %v", err)
+	}
+	b.Reset()
+	b.Check(Check{Name: "a", Status: Pass})
+	if _, err := b.AppendJSON(nil); err != nil {
+		t.Errorf("got %v after Reset", err)
+	}
+}
+
+func TestStatusBuilder_allocs(t *testing.T) {
+	var b StatusBuilder
+	at := time.Now()
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Reset()
+		b.Check(Check{Name: "db", Status: Pass, Time: at, Latency: time.Millisecond})
+		b.Check(Check{Name: "disk", Status: Warn, Value: 91, Unit: "percent"})
+		var err error
+		buf, err = b.AppendJSON(buf[:0])
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,115 @@
+package jsonappender
+
+import (
+	"sync"
+	"time"
+)
+
+// idleFlush flushes output that has been buffered for too long from a timer. mu guards
+// the BufWriter's buffer and writer while it's enabled because the timer runs on its
+// own goroutine.
+type idleFlush struct {
+	mu    sync.Mutex
+	d     time.Duration
+	timer *time.Timer
+	armed bool
+	// err is an error from a timer flush waiting to be moved to the BufWriter's Error.
+	err error
+	// callbacks are watermark callbacks waiting for mu to be unlocked, so they can use
+	// the BufWriter.
+	callbacks []func()
+}
+
+// SetIdleFlush makes the BufWriter flush automatically once output has been buffered
+// for d without a Flush, so output written at a low rate doesn't sit in the buffer.
+// A d of 0 or less turns it off.
+//
+// The automatic flush happens on another goroutine. The BufWriter takes care of
+// locking, but watermark callbacks may be called from that goroutine, and an error
+// from an automatic flush shows up in Error on the next write or Flush. The BufWriter
+// itself still isn't safe for concurrent use. Call SetIdleFlush when nothing else is
+// using it.
+func (bw *BufWriter) SetIdleFlush(d time.Duration) {
+	if bw.idle != nil {
+		bw.idle.mu.Lock()
+		bw.idle.d = 0
+		if bw.idle.timer != nil {
+			bw.idle.timer.Stop()
+		}
+		bw.takeIdleErr()
+		bw.idle.mu.Unlock()
+		bw.idle = nil
+	}
+	if d <= 0 {
+		return
+	}
+	bw.idle = &idleFlush{
+		d: d,
+	}
+	bw.idle.mu.Lock()
+	bw.armIdle()
+	bw.idle.mu.Unlock()
+}
+
+// lockIdle and unlockIdle guard access to the buffer while idle flushing is enabled.
+func (bw *BufWriter) lockIdle() {
+	if bw.idle == nil {
+		return
+	}
+	bw.idle.mu.Lock()
+	bw.takeIdleErr()
+}
+
+func (bw *BufWriter) unlockIdle() {
+	idle := bw.idle
+	if idle == nil {
+		return
+	}
+	bw.armIdle()
+	idle.unlock()
+}
+
+// unlock unlocks mu and then runs the watermark callbacks that were waiting for it.
+func (idle *idleFlush) unlock() {
+	callbacks := idle.callbacks
+	idle.callbacks = nil
+	idle.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+func (bw *BufWriter) takeIdleErr() {
+	if bw.idle.err != nil && bw.Error == nil {
+		bw.Error = bw.idle.err
+	}
+	bw.idle.err = nil
+}
+
+// armIdle starts the timer when there is buffered output and it isn't running yet.
+func (bw *BufWriter) armIdle() {
+	idle := bw.idle
+	if idle.armed || len(bw.buf) == 0 || bw.w == nil {
+		return
+	}
+	idle.armed = true
+	if idle.timer == nil {
+		idle.timer = time.AfterFunc(idle.d, func() {
+			bw.idleFlush(idle)
+		})
+		return
+	}
+	idle.timer.Reset(idle.d)
+}
+
+func (bw *BufWriter) idleFlush(idle *idleFlush) {
+	idle.mu.Lock()
+	defer idle.unlock()
+	idle.armed = false
+	if idle.d == 0 || idle.err != nil {
+		return
+	}
+	// Error belongs to the writing goroutine, so the error waits in idle.err.
+	idle.err = bw.flushBuf()
+	bw.checkWatermarks()
+}
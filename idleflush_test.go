@@ -0,0 +1,97 @@
+package jsonappender
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncWriter signals each write on writes.
+type syncWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	err    error
+	writes chan struct{}
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	defer func() { w.writes <- struct{}{} }()
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestBufWriter_SetIdleFlush(t *testing.T) {
+	w := &syncWriter{writes: make(chan struct{}, 10)}
+	bw := NewBufWriter(w)
+	bw.SetIdleFlush(time.Millisecond)
+	bw.RawString(`{"a":`)
+	bw.Int64(1)
+	bw.RawByte('}')
+	select {
+	case <-w.writes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an automatic flush")
+	}
+	if w.String() != `{"a":1}` || bw.Buffered() != 0 {
+		t.Errorf("got %q with %d buffered", w.String(), bw.Buffered())
+	}
+
+	w.mu.Lock()
+	w.err = errors.New("fail")
+	w.mu.Unlock()
+	bw.RawByte('x')
+	<-w.writes
+	bw.RawByte('y')
+	if bw.Error == nil || bw.Error.Error() != "fail" {
+		t.Errorf("expected the automatic flush error, got %v", bw.Error)
+	}
+
+	bw.SetIdleFlush(0)
+	bw.Reset(w)
+	bw.RawByte('z')
+	select {
+	case <-w.writes:
+		t.Error("unexpected flush")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSetIdleFlush_watermarkCallbacks(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriterSize(&out, 64)
+	bw.SetIdleFlush(time.Hour)
+	var high, low []int
+	bw.SetWatermarks(Watermarks{
+		Low:    0,
+		High:   8,
+		OnHigh: func() { high = append(high, bw.Buffered()) },
+		OnLow:  func() { low = append(low, bw.Buffered()) },
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bw.RawString(`"0123456789"`)
+		bw.Flush() //nolint:errcheck // checked below
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callbacks deadlocked")
+	}
+	if len(high) != 1 || high[0] != 12 || len(low) != 1 || low[0] != 0 || bw.Error != nil {
+		t.Errorf("got high %v, low %v, %v", high, low, bw.Error)
+	}
+	bw.SetIdleFlush(0)
+}
@@ -0,0 +1,150 @@
+package jsonappender
+
+// indenter reindents everything written, so values written in one piece like Object
+// and Raw are indented as well as the ones written with BeginObject and friends.
+type indenter struct {
+	prefix string
+	indent string
+	state  indentState
+	// busy is set while indenter writes through the BufWriter itself.
+	busy    bool
+	scratch []byte
+}
+
+// indentState is saved by BeginTentative so Discard can go back to it.
+type indentState struct {
+	depth    int
+	inString bool
+	escaped  bool
+	// open is set after an opening brace or bracket until it's known whether the
+	// container is empty, which keeps empty containers on one line.
+	open bool
+}
+
+// SetIndent makes the BufWriter indent its output like json.Encoder.SetIndent: each
+// member and element goes on a new line that starts with prefix followed by one indent
+// per level of nesting. Whitespace written inside objects and arrays is replaced, and
+// whitespace between top-level values is kept. Call it with two empty strings to go
+// back to compact output. Like SetMetadata it makes writing slower.
+func (bw *BufWriter) SetIndent(prefix, indent string) {
+	if prefix == "" && indent == "" {
+		bw.indent = nil
+		return
+	}
+	bw.indent = &indenter{
+		prefix: prefix,
+		indent: indent,
+	}
+}
+
+func (bw *BufWriter) writeIndentedString(s string) {
+	in := bw.indent
+	in.scratch = in.appendIndented(in.scratch[:0], []byte(s))
+	bw.writeIndentedScratch()
+}
+
+func (bw *BufWriter) writeIndentedByte(b byte) {
+	in := bw.indent
+	p := [1]byte{b}
+	in.scratch = in.appendIndented(in.scratch[:0], p[:])
+	bw.writeIndentedScratch()
+}
+
+func (bw *BufWriter) writeIndented(p []byte) {
+	in := bw.indent
+	in.scratch = in.appendIndented(in.scratch[:0], p)
+	bw.writeIndentedScratch()
+}
+
+func (bw *BufWriter) writeIndentedScratch() {
+	in := bw.indent
+	in.busy = true
+	bw.write(in.scratch)
+	in.busy = false
+}
+
+// appendIndented appends p to buf with the whitespace outside of strings replaced by
+// newlines and indentation.
+func (in *indenter) appendIndented(buf, p []byte) []byte {
+	st := &in.state
+	for _, c := range p {
+		if st.inString {
+			switch {
+			case st.escaped:
+				st.escaped = false
+			case c == '\\':
+				st.escaped = true
+			case c == '"':
+				st.inString = false
+			}
+			buf = append(buf, c)
+			continue
+		}
+		if isSpace(c) {
+			if st.depth == 0 {
+				buf = append(buf, c)
+			}
+			continue
+		}
+		if st.open {
+			st.open = false
+			if c != '}' && c != ']' {
+				buf = in.appendNewline(buf)
+			}
+		} else if c == '}' || c == ']' {
+			if st.depth > 0 {
+				st.depth--
+			}
+			buf = in.appendNewline(buf)
+			buf = append(buf, c)
+			continue
+		}
+		buf = append(buf, c)
+		switch c {
+		case '"':
+			st.inString = true
+		case '{', '[':
+			st.depth++
+			st.open = true
+		case '}', ']':
+			// closing an empty container
+			if st.depth > 0 {
+				st.depth--
+			}
+		case ',':
+			buf = in.appendNewline(buf)
+		case ':':
+			buf = append(buf, ' ')
+		}
+	}
+	return buf
+}
+
+func (in *indenter) appendNewline(buf []byte) []byte {
+	buf = append(buf, '\n')
+	buf = append(buf, in.prefix...)
+	for i := 0; i < in.state.depth; i++ {
+		buf = append(buf, in.indent...)
+	}
+	return buf
+}
+
+// Indent appends src, which has to be exactly one json value, indented like json.Indent:
+// each member and element goes on a new line that starts with prefix followed by one
+// indent per level of nesting. Whitespace around the value isn't copied. When src isn't
+// valid it returns buf unchanged and a *SyntaxError.
+func Indent(src, buf []byte, prefix, indent string) ([]byte, error) {
+	start := len(buf)
+	buf, err := reformat(src, prefix, indent, Colors{}, false, buf)
+	if err != nil {
+		return buf[:start], err
+	}
+	return buf, nil
+}
+
+// Compact appends src, which has to be exactly one json value, without insignificant
+// whitespace, like json.Compact. When src isn't valid it returns buf unchanged and a
+// *SyntaxError.
+func Compact(src, buf []byte) ([]byte, error) {
+	return Indent(src, buf, "", "")
+}
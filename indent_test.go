@@ -0,0 +1,92 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSetIndent(t *testing.T) {
+	bw := &BufWriter{}
+	bw.SetIndent(">", "  ")
+	bw.BeginObject()
+	bw.StringField("a", "x, y: {z}")
+	bw.FieldName("b")
+	bw.BeginArray()
+	bw.Int64(1)
+	bw.RawValidated([]byte(`{ "c" : [ ] , "d":{}}`))
+	bw.EndArray()
+	bw.EndObject()
+	bw.RawByte('\n')
+	bw.Int64Array(nil)
+	want := "{\n>  \"a\": \"x, y: {z}\",\n>  \"b\": [\n>    1,\n>    {\n>      \"c\": [],\n" +
+		">      \"d\": {}\n>    }\n>  ]\n>}\n[]"
+	if got := string(bw.TakeBuffer()); got != want {
+		t.Errorf("got %s\nwanted %s", got, want)
+	}
+}
+
+func TestSetIndentMatchesEncodingJSON(t *testing.T) {
+	doc := []byte(`{"a":[1,2,{"b":null,"c":[]}],"d":"e\"}","f":{}}`)
+	var want bytes.Buffer
+	if err := json.Indent(&want, doc, "", "\t"); err != nil {
+		t.Fatal(err)
+	}
+	bw := &BufWriter{}
+	bw.SetIndent("", "\t")
+	for _, c := range doc {
+		bw.RawByte(c)
+	}
+	if got := string(bw.TakeBuffer()); got != want.String() {
+		t.Errorf("got %s\nwanted %s", got, want.String())
+	}
+}
+
+func TestSetIndentDiscard(t *testing.T) {
+	bw := &BufWriter{}
+	bw.SetIndent("", " ")
+	bw.BeginArray()
+	bw.BeginTentative()
+	bw.BeginObject()
+	bw.Discard()
+	bw.Int64(1)
+	bw.EndArray()
+	if got := string(bw.TakeBuffer()); got != "[\n 1\n]" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestIndent(t *testing.T) {
+	for _, src := range []string{
+		`{"a":[1,2,{"b":null,"c":[]}],"d":"e\"}","f":{}}`,
+		` [ "x" , true ] `,
+		`1`,
+	} {
+		var want bytes.Buffer
+		if err := json.Indent(&want, bytes.TrimSpace([]byte(src)), "#", "  "); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Indent([]byte(src), []byte("x"), "#", "  ")
+		if err != nil || string(got) != "x"+want.String() {
+			t.Errorf("got %s, %v\nwanted x%s", got, err, want.String())
+		}
+	}
+	for _, src := range []string{``, ` `, `[1,]`, `1 2`, `{}}`} {
+		got, err := Indent([]byte(src), []byte("x"), "", "\t")
+		if _, ok := err.(*SyntaxError); !ok || string(got) != "x" {
+			t.Errorf("%q: got %s, %v", src, got, err)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	src := "{\n  \"a\" : [ 1, \"b c\" ],\n\t\"d\": {} }\n"
+	got, err := Compact([]byte(src), []byte("x"))
+	if err != nil || string(got) != `x{"a":[1,"b c"],"d":{}}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = Compact([]byte(`{"a" 1}`), []byte("x"))
+	if _, ok := err.(*SyntaxError); !ok || string(got) != "x" {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
@@ -0,0 +1,91 @@
+package jsonappender
+
+// Int writes an int value
+func (bw *BufWriter) Int(val int) {
+	bw.Int64(int64(val))
+}
+
+// Int append an int value
+func Int(val int, buf []byte) []byte {
+	return Int64(int64(val), buf)
+}
+
+// Int8 writes an int8 value
+func (bw *BufWriter) Int8(val int8) {
+	bw.Int64(int64(val))
+}
+
+// Int8 append an int8 value
+func Int8(val int8, buf []byte) []byte {
+	return Int64(int64(val), buf)
+}
+
+// Int16 writes an int16 value
+func (bw *BufWriter) Int16(val int16) {
+	bw.Int64(int64(val))
+}
+
+// Int16 append an int16 value
+func Int16(val int16, buf []byte) []byte {
+	return Int64(int64(val), buf)
+}
+
+// Int32 writes an int32 value
+func (bw *BufWriter) Int32(val int32) {
+	bw.Int64(int64(val))
+}
+
+// Int32 append an int32 value
+func Int32(val int32, buf []byte) []byte {
+	return Int64(int64(val), buf)
+}
+
+// Uint writes a uint value
+func (bw *BufWriter) Uint(val uint) {
+	bw.Uint64(uint64(val))
+}
+
+// Uint append a uint value
+func Uint(val uint, buf []byte) []byte {
+	return Uint64(uint64(val), buf)
+}
+
+// Uint8 writes a uint8 value
+func (bw *BufWriter) Uint8(val uint8) {
+	bw.Uint64(uint64(val))
+}
+
+// Uint8 append a uint8 value
+func Uint8(val uint8, buf []byte) []byte {
+	return Uint64(uint64(val), buf)
+}
+
+// Uint16 writes a uint16 value
+func (bw *BufWriter) Uint16(val uint16) {
+	bw.Uint64(uint64(val))
+}
+
+// Uint16 append a uint16 value
+func Uint16(val uint16, buf []byte) []byte {
+	return Uint64(uint64(val), buf)
+}
+
+// Uint32 writes a uint32 value
+func (bw *BufWriter) Uint32(val uint32) {
+	bw.Uint64(uint64(val))
+}
+
+// Uint32 append a uint32 value
+func Uint32(val uint32, buf []byte) []byte {
+	return Uint64(uint64(val), buf)
+}
+
+// Uintptr writes a uintptr value
+func (bw *BufWriter) Uintptr(val uintptr) {
+	bw.Uint64(uint64(val))
+}
+
+// Uintptr append a uintptr value
+func Uintptr(val uintptr, buf []byte) []byte {
+	return Uint64(uint64(val), buf)
+}
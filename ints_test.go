@@ -0,0 +1,96 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestSignedInts(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("Int same as encoding/json", prop.ForAll(
+		func(val int, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Int(val, []byte(buf)), nil)
+		}, gen.Int(), gen.AnyString(),
+	))
+	properties.Property("Int8 same as encoding/json", prop.ForAll(
+		func(val int8, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Int8(val, []byte(buf)), nil)
+		}, gen.Int8(), gen.AnyString(),
+	))
+	properties.Property("Int16 same as encoding/json", prop.ForAll(
+		func(val int16, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Int16(val, []byte(buf)), nil)
+		}, gen.Int16(), gen.AnyString(),
+	))
+	properties.Property("Int32 same as encoding/json", prop.ForAll(
+		func(val int32, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Int32(val, []byte(buf)), nil)
+		}, gen.Int32(), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Int(-1)
+	bw.RawByte(',')
+	bw.Int8(-128)
+	bw.RawByte(',')
+	bw.Int16(32767)
+	bw.RawByte(',')
+	bw.Int32(-2147483648)
+	bw.Flush()
+	if want := "-1,-128,32767,-2147483648"; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
+
+func TestUnsignedInts(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("Uint same as encoding/json", prop.ForAll(
+		func(val uint, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Uint(val, []byte(buf)), nil)
+		}, gen.UInt(), gen.AnyString(),
+	))
+	properties.Property("Uint8 same as encoding/json", prop.ForAll(
+		func(val uint8, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Uint8(val, []byte(buf)), nil)
+		}, gen.UInt8(), gen.AnyString(),
+	))
+	properties.Property("Uint16 same as encoding/json", prop.ForAll(
+		func(val uint16, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Uint16(val, []byte(buf)), nil)
+		}, gen.UInt16(), gen.AnyString(),
+	))
+	properties.Property("Uint32 same as encoding/json", prop.ForAll(
+		func(val uint32, buf string) bool {
+			return matchesEncodingJSON(val, []byte(buf), Uint32(val, []byte(buf)), nil)
+		}, gen.UInt32(), gen.AnyString(),
+	))
+	properties.Property("Uintptr same as encoding/json", prop.ForAll(
+		func(val uint64, buf string) bool {
+			v := uintptr(val)
+			return matchesEncodingJSON(v, []byte(buf), Uintptr(v, []byte(buf)), nil)
+		}, gen.UInt64(), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Uint(1)
+	bw.RawByte(',')
+	bw.Uint8(255)
+	bw.RawByte(',')
+	bw.Uint16(65535)
+	bw.RawByte(',')
+	bw.Uint32(4294967295)
+	bw.RawByte(',')
+	bw.Uintptr(0)
+	bw.Flush()
+	if want := "1,255,65535,4294967295,0"; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
@@ -0,0 +1,108 @@
+package jsonappender
+
+import (
+	"errors"
+	"net"
+	"strconv"
+)
+
+// IP appends ip as a string value in the same form as ip.String(), or "" for an empty
+// ip like encoding/json. It fails when ip isn't 4 or 16 bytes long.
+func IP(ip net.IP, buf []byte) ([]byte, error) {
+	if len(ip) == 0 {
+		return append(buf, '"', '"'), nil
+	}
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return buf, errors.New("jsonappender: invalid IP address length " + strconv.Itoa(len(ip)))
+	}
+	buf = append(buf, '"')
+	buf = appendIP(ip, buf)
+	return append(buf, '"'), nil
+}
+
+// IP writes ip as a string value.
+func (bw *BufWriter) IP(ip net.IP) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = IP(ip, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// IPNet appends n as a string value in CIDR notation, the same as n.String(). A nil n
+// is null.
+func IPNet(n *net.IPNet, buf []byte) []byte {
+	if n == nil {
+		return Null(buf)
+	}
+	ones, bits := n.Mask.Size()
+	ip4 := n.IP.To4()
+	switch {
+	case bits == 8*net.IPv4len && ip4 != nil:
+		buf = append(buf, '"')
+		buf = appendIP(ip4, buf)
+	case bits == 8*net.IPv6len && ip4 == nil && len(n.IP) == net.IPv6len:
+		buf = append(buf, '"')
+		buf = appendIP(n.IP, buf)
+	default:
+		// non-canonical masks and mismatched lengths are rare enough to leave to net
+		return String(n.String(), buf)
+	}
+	buf = append(buf, '/')
+	buf = strconv.AppendInt(buf, int64(ones), 10)
+	return append(buf, '"')
+}
+
+// IPNet writes n as a string value in CIDR notation. A nil n is null.
+func (bw *BufWriter) IPNet(n *net.IPNet) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = IPNet(n, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// appendIP appends ip, which is 4 or 16 bytes long, without quotes. IPv4 addresses are
+// dotted decimal and IPv6 addresses are formatted as RFC 5952 recommends.
+func appendIP(ip net.IP, buf []byte) []byte {
+	if ip4 := ip.To4(); ip4 != nil {
+		for i, b := range ip4 {
+			if i > 0 {
+				buf = append(buf, '.')
+			}
+			buf = strconv.AppendUint(buf, uint64(b), 10)
+		}
+		return buf
+	}
+	// find the longest run of at least two zero groups to replace with ::
+	zeroStart, zeroEnd := -1, -1
+	for i := 0; i < net.IPv6len; i += 2 {
+		j := i
+		for j < net.IPv6len && ip[j] == 0 && ip[j+1] == 0 {
+			j += 2
+		}
+		if j > i+2 && j-i > zeroEnd-zeroStart {
+			zeroStart, zeroEnd = i, j
+		}
+		if j > i {
+			i = j - 2
+		}
+	}
+	for i := 0; i < net.IPv6len; i += 2 {
+		if i == zeroStart {
+			buf = append(buf, ':', ':')
+			i = zeroEnd - 2
+			continue
+		}
+		if i > 0 && i != zeroEnd {
+			buf = append(buf, ':')
+		}
+		buf = strconv.AppendUint(buf, uint64(ip[i])<<8|uint64(ip[i+1]), 16)
+	}
+	return buf
+}
@@ -0,0 +1,83 @@
+package jsonappender
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// genIP generates addresses with plenty of zero groups so :: placement gets exercised.
+func genIP(size int) gopter.Gen {
+	return gen.SliceOfN(size, gen.OneGenOf(gen.Const(byte(0)), gen.UInt8())).Map(func(b []byte) net.IP {
+		return net.IP(b)
+	})
+}
+
+func TestIP(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("IPv6 same as String", prop.ForAll(
+		func(ip net.IP, buf string) bool {
+			got, err := IP(ip, []byte(buf))
+			return err == nil && string(got) == buf+`"`+ip.String()+`"`
+		}, genIP(net.IPv6len), gen.AnyString(),
+	))
+	properties.Property("IPv4 same as encoding/json", prop.ForAll(
+		func(ip net.IP, buf string) bool {
+			got, err := IP(ip, []byte(buf))
+			return matchesEncodingJSON(ip, []byte(buf), got, err)
+		}, genIP(net.IPv4len), gen.AnyString(),
+	))
+	properties.Property("IPNet same as String", prop.ForAll(
+		func(ip net.IP, ones int) bool {
+			bits := 8 * len(ip)
+			if ones > bits {
+				ones = bits
+			}
+			n := &net.IPNet{IP: ip.Mask(net.CIDRMask(ones, bits)), Mask: net.CIDRMask(ones, bits)}
+			return string(IPNet(n, nil)) == `"`+n.String()+`"`
+		}, gen.OneGenOf(genIP(net.IPv4len), genIP(net.IPv6len)), gen.IntRange(0, 128),
+	))
+	properties.TestingRun(t)
+
+	for _, td := range []struct {
+		ip   net.IP
+		want string
+	}{
+		{ip: net.ParseIP("192.0.2.1"), want: `"192.0.2.1"`},
+		{ip: net.ParseIP("2001:db8::1"), want: `"2001:db8::1"`},
+		{ip: net.ParseIP("2001:db8:0:1:1:1:1:1"), want: `"2001:db8:0:1:1:1:1:1"`},
+		{ip: net.ParseIP("::"), want: `"::"`},
+		{ip: net.ParseIP("1:0:0:2:0:0:0:3"), want: `"1:0:0:2::3"`},
+		{want: `""`},
+	} {
+		got, err := IP(td.ip, nil)
+		if err != nil || string(got) != td.want {
+			t.Errorf("%v: got %s, %v, wanted %s", td.ip, got, err, td.want)
+		}
+	}
+	if _, err := IP(net.IP{1, 2, 3}, nil); err == nil {
+		t.Error("expected an error for a 3 byte IP")
+	}
+
+	_, cidr, err := net.ParseCIDR("10.1.0.0/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.IP(net.IPv4(127, 0, 0, 1))
+	bw.RawByte(',')
+	bw.IPNet(cidr)
+	bw.RawByte(',')
+	bw.IPNet(&net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.IPMask{255, 0, 255, 0}})
+	bw.RawByte(',')
+	bw.IPNet(nil)
+	bw.Flush()
+	if want := `"127.0.0.1","10.1.0.0/16","10.0.0.0/ff00ff00",null`; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
@@ -0,0 +1,292 @@
+// Package jcs appends json in the canonical form of RFC 8785, the JSON Canonicalization
+// Scheme: no whitespace, members sorted by the UTF-16 code units of their names,
+// numbers formatted like ECMAScript does and strings with only the escapes json requires.
+// Signatures computed over canonical json verify across implementations.
+package jcs
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Canonicalize appends src, which has to be exactly one json value, in canonical form.
+// Numbers are read as float64s like RFC 8785 requires. Invalid unicode in strings and
+// duplicate member names are errors. On error it returns buf unchanged.
+func Canonicalize(src, buf []byte) ([]byte, error) {
+	start := len(buf)
+	sc := jsonappender.NewScanner(src)
+	buf, err := AppendNext(sc, buf)
+	if err == io.EOF {
+		err = errors.New("jcs: no value")
+	}
+	if err == nil {
+		_, err = sc.Next()
+		if err == io.EOF {
+			return buf, nil
+		}
+		if err == nil {
+			err = errors.New("jcs: more than one value")
+		}
+	}
+	return buf[:start], err
+}
+
+// Append appends the output of a in canonical form.
+func Append(a jsonappender.JSONAppender, buf []byte) ([]byte, error) {
+	start := len(buf)
+	b, err := a.AppendJSON(buf)
+	if err != nil {
+		return b[:start], err
+	}
+	// canonicalize a's output in place past it and move the result back
+	out, err := Canonicalize(b[start:], b)
+	if err != nil {
+		return b[:start], err
+	}
+	n := copy(out[start:], out[len(b):])
+	return out[:start+n], nil
+}
+
+// Value appends val, encoded like jsonappender.Value, in canonical form.
+func Value(val interface{}, buf []byte) ([]byte, error) {
+	return Append(jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return jsonappender.Value(val, buf)
+	}), buf)
+}
+
+// AppendNext appends the next top-level value read by sc in canonical form, for input
+// that holds several values like ndjson. It returns io.EOF when there are no more.
+func AppendNext(sc *jsonappender.Scanner, buf []byte) ([]byte, error) {
+	tok, err := sc.Next()
+	if err != nil {
+		return buf, err
+	}
+	return canonicalValue(sc, tok, buf)
+}
+
+type canonicalMember struct {
+	key   string
+	value []byte
+}
+
+func canonicalValue(sc *jsonappender.Scanner, tok jsonappender.Token, buf []byte) ([]byte, error) {
+	switch tok.Kind {
+	case jsonappender.TokenString:
+		s, err := unquote(tok.Raw)
+		if err != nil {
+			return buf, err
+		}
+		return canonicalString(s, buf), nil
+	case jsonappender.TokenNumber:
+		f, err := strconv.ParseFloat(string(tok.Raw), 64)
+		if err != nil {
+			return buf, err
+		}
+		if f == 0 {
+			// normalizes -0
+			f = 0
+		}
+		return jsonappender.Float64(f, buf)
+	case jsonappender.TokenArrayStart:
+		return canonicalArray(sc, buf)
+	case jsonappender.TokenObjectStart:
+		return canonicalObject(sc, buf)
+	}
+	return append(buf, tok.Raw...), nil
+}
+
+func canonicalArray(sc *jsonappender.Scanner, buf []byte) ([]byte, error) {
+	buf = append(buf, '[')
+	for i := 0; ; i++ {
+		tok, err := sc.Next()
+		if err != nil {
+			return buf, err
+		}
+		if tok.Kind == jsonappender.TokenArrayEnd {
+			return append(buf, ']'), nil
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf, err = canonicalValue(sc, tok, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+}
+
+func canonicalObject(sc *jsonappender.Scanner, buf []byte) ([]byte, error) {
+	var members []canonicalMember
+	for {
+		tok, err := sc.Next()
+		if err != nil {
+			return buf, err
+		}
+		if tok.Kind == jsonappender.TokenObjectEnd {
+			break
+		}
+		key, err := unquote(tok.Raw)
+		if err != nil {
+			return buf, err
+		}
+		tok, err = sc.Next()
+		if err != nil {
+			return buf, err
+		}
+		value, err := canonicalValue(sc, tok, nil)
+		if err != nil {
+			return buf, err
+		}
+		members = append(members, canonicalMember{
+			key:   key,
+			value: value,
+		})
+	}
+	// RFC 8785 sorts keys by their UTF-16 code units.
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].key, members[j].key)
+	})
+	buf = append(buf, '{')
+	for i, m := range members {
+		if i > 0 {
+			if m.key == members[i-1].key {
+				return buf, errors.New("jcs: duplicate object key " + strconv.Quote(m.key))
+			}
+			buf = append(buf, ',')
+		}
+		buf = canonicalString(m.key, buf)
+		buf = append(buf, ':')
+		buf = append(buf, m.value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// lessUTF16 reports whether a sorts before b by UTF-16 code units. Code points only
+// sort differently from their code units when one is outside the Basic Multilingual
+// Plane and the other isn't, so only then does it compare the high surrogate.
+func lessUTF16(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		ra, sizeA := utf8.DecodeRuneInString(a[i:])
+		rb, sizeB := utf8.DecodeRuneInString(b[j:])
+		if ra != rb {
+			return firstCodeUnit(ra, rb) < firstCodeUnit(rb, ra)
+		}
+		i += sizeA
+		j += sizeB
+	}
+	return len(a)-i < len(b)-j
+}
+
+// firstCodeUnit returns r, or its high surrogate when r needs a surrogate pair and
+// other doesn't.
+func firstCodeUnit(r, other rune) rune {
+	if r > 0xFFFF && other <= 0xFFFF {
+		hi, _ := utf16.EncodeRune(r)
+		return hi
+	}
+	return r
+}
+
+// canonicalString appends s escaping only what RFC 8785 requires.
+func canonicalString(s string, buf []byte) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case b == '"', b == '\\':
+			buf = append(buf, '\\', b)
+		case b == '\b':
+			buf = append(buf, '\\', 'b')
+		case b == '\f':
+			buf = append(buf, '\\', 'f')
+		case b == '\n':
+			buf = append(buf, '\\', 'n')
+		case b == '\r':
+			buf = append(buf, '\\', 'r')
+		case b == '\t':
+			buf = append(buf, '\\', 't')
+		case b < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+		default:
+			buf = append(buf, b)
+		}
+	}
+	return append(buf, '"')
+}
+
+// unquote decodes a json string token. Invalid UTF-8 and unpaired surrogates are errors
+// because canonical json has to be valid unicode.
+func unquote(raw []byte) (string, error) {
+	quoted := raw
+	raw = raw[1 : len(raw)-1]
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(raw[i:])
+			if r == utf8.RuneError && size == 1 {
+				return "", errInvalidString(quoted)
+			}
+			out = append(out, raw[i:i+size]...)
+			i += size
+			continue
+		}
+		if c != '\\' {
+			out = append(out, c)
+			i++
+			continue
+		}
+		switch raw[i+1] {
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'u':
+			r := hexRune(raw[i+2 : i+6])
+			i += 6
+			if utf16.IsSurrogate(r) {
+				if len(raw) < i+6 || raw[i] != '\\' || raw[i+1] != 'u' {
+					return "", errInvalidString(quoted)
+				}
+				r = utf16.DecodeRune(r, hexRune(raw[i+2:i+6]))
+				if r == utf8.RuneError {
+					return "", errInvalidString(quoted)
+				}
+				i += 6
+			}
+			var rb [utf8.UTFMax]byte
+			out = append(out, rb[:utf8.EncodeRune(rb[:], r)]...)
+			continue
+		default:
+			out = append(out, raw[i+1])
+		}
+		i += 2
+	}
+	return string(out), nil
+}
+
+func errInvalidString(quoted []byte) error {
+	return errors.New("jcs: invalid unicode in string " + strconv.Quote(string(quoted)))
+}
+
+func hexRune(b []byte) rune {
+	n, err := strconv.ParseUint(string(b), 16, 16)
+	if err != nil {
+		return utf8.RuneError
+	}
+	return rune(n)
+}
@@ -0,0 +1,69 @@
+package jcs
+
+import (
+	"testing"
+	"unicode/utf16"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// the example from RFC 8785 section 3.2.2
+	src := `{"numbers":[333333333.33333329,1E30,4.50,2e-3,0.000000000000000000000000001],` +
+		`"string":"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/","literals":[null,true,false]}`
+	want := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"` + "€" + `$\u000f\nA'B\"\\\\\"/"}`
+	got, err := Canonicalize([]byte(src), []byte("x"))
+	if err != nil || string(got) != "x"+want {
+		t.Errorf("got %s, %v\nwanted x%s", got, err, want)
+	}
+
+	// sorted by UTF-16 code units, not code points
+	got, err = Canonicalize([]byte(`{"😀":1,"דּ":2, "-0": -0}`), nil)
+	if err != nil || string(got) != `{"-0":0,"`+"\U0001f600"+`":1,"`+"דּ"+`":2}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	for _, src := range []string{``, `1 2`, `{"a":1,"a":2}`, `"\ud800"`, `[1,]`} {
+		got, err := Canonicalize([]byte(src), []byte("x"))
+		if err == nil || string(got) != "x" {
+			t.Errorf("%q: got %s, %v", src, got, err)
+		}
+	}
+}
+
+func TestLessUTF16(t *testing.T) {
+	keys := []string{"", "a", "ab", "b", "\u00e9", "\ufb33", "\uffff", "\U00010000", "\U0001f600",
+		"\U0001f600a", "\U0001f601", "a\U0001f600", "a\ufb33"}
+	for _, a := range keys {
+		for _, b := range keys {
+			ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+			want := len(ua) < len(ub)
+			for i := 0; i < len(ua) && i < len(ub); i++ {
+				if ua[i] != ub[i] {
+					want = ua[i] < ub[i]
+					break
+				}
+			}
+			if got := lessUTF16(a, b); got != want {
+				t.Errorf("lessUTF16(%q, %q) = %v", a, b, got)
+			}
+		}
+	}
+	if n := testing.AllocsPerRun(10, func() { lessUTF16("a\U0001f600", "a\ufb33") }); n != 0 {
+		t.Errorf("got %v allocations", n)
+	}
+}
+
+func TestValue(t *testing.T) {
+	got, err := Value(map[string]interface{}{"b": 1.0, "a": []interface{}{"<"}}, []byte("x"))
+	if err != nil || string(got) != `x{"a":["<"],"b":1}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	_, err = Append(jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, `{"a"`...), nil
+	}), nil)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -1,9 +1,8 @@
 package jsonappender
 
 import (
-	"bufio"
-	"encoding/json"
-	"fmt"
+	"encoding"
+	"errors"
 	"io"
 	"math"
 	"strconv"
@@ -16,39 +15,397 @@ type JSONAppender interface {
 	AppendJSON(buf []byte) ([]byte, error)
 }
 
+// JSONSizer is implemented by values that can estimate the size of their json
+// encoding. Value, Array and BufWriter.Value grow the buffer by the estimate once,
+// before encoding, instead of as the encoding grows.
+type JSONSizer interface {
+	SizeJSONHint() int
+}
+
+// sizeHint returns val's estimate when it's a JSONSizer.
+func sizeHint(val interface{}) int {
+	if s, ok := val.(JSONSizer); ok {
+		return s.SizeJSONHint()
+	}
+	return 0
+}
+
+// AppendFunc is a function that satisfies JSONAppender
+type AppendFunc func(buf []byte) ([]byte, error)
+
+// AppendJSON calls fn(buf)
+func (fn AppendFunc) AppendJSON(buf []byte) ([]byte, error) {
+	return fn(buf)
+}
+
 // BufWriter write json to your writer in a buffered manner. Don't forget to Flush.
 // Errors are collected in Error so you don't have to check after each write.
+//
+// A BufWriter with a nil writer keeps everything in memory. Use TakeBuffer to get the
+// result. The zero value is an empty in-memory BufWriter.
 type BufWriter struct {
-	Error     error
-	writer    *bufio.Writer
-	stringBuf []byte
+	Error error
+	w     io.Writer
+	// buf holds output that hasn't been flushed. It's flushed before it grows past size
+	// unless w is nil.
+	buf        []byte
+	size       int
+	stringBuf  []byte
+	watermarks *watermarks
+	timeCache  TimeCache
+	transcoder *transcoder
+	idle       *idleFlush
+	pos        *Position
+	tentative  *tentative
+	floatOpts  FloatOptions
+	bigOpts    BigOptions
+	timeOpts   TimeOptions
+	stringOpts StringOptions
+	objectOpts ObjectOptions
+	keys       *keyCache
+	sortKeys   []string
+	meta       *metadata
+	indent     *indenter
+	// open holds '{' or '[' for each container started with BeginObject or BeginArray
+	// and not yet ended.
+	open []byte
+	// last is the last byte written other than whitespace, so the Field methods know
+	// whether a member needs a comma in front of it.
+	last           byte
+	quoteLargeInts bool
 }
 
+const defaultBufSize = 4096
+
 // NewBufWriter does what the name says
 func NewBufWriter(w io.Writer) *BufWriter {
+	return NewBufWriterSize(w, defaultBufSize)
+}
+
+// NewBufWriterSize is like NewBufWriter but with a buffer of at least size bytes.
+func NewBufWriterSize(w io.Writer, size int) *BufWriter {
+	if size <= 0 {
+		size = defaultBufSize
+	}
 	bw := BufWriter{
-		writer: bufio.NewWriter(w),
+		w:    w,
+		buf:  make([]byte, 0, size),
+		size: size,
 	}
 	return &bw
 }
 
-// Flush flushes the buffer
+// Flush flushes the buffer. When a container started with BeginObject or BeginArray
+// is still open, or the last thing written was a member name, it flushes anyway but
+// returns a *NestingError without setting Error, so ignore that when flushing partway
+// through a document.
 func (bw *BufWriter) Flush() error {
+	bw.lockIdle()
+	defer bw.unlockIdle()
+	if bw.Error != nil {
+		return bw.Error
+	}
+	bw.flush()
+	bw.checkWatermarks()
 	if bw.Error != nil {
 		return bw.Error
 	}
-	bw.Error = bw.writer.Flush()
-	return bw.Error
+	return bw.unfinished()
+}
+
+// Close is Flush for the end of the output. It also turns off SetIdleFlush. It doesn't
+// close the writer.
+func (bw *BufWriter) Close() error {
+	bw.SetIdleFlush(0)
+	return bw.Flush()
+}
+
+// flush writes buf to w. Like bufio.Writer, output that couldn't be written stays
+// buffered.
+func (bw *BufWriter) flush() {
+	bw.Error = bw.flushBuf()
+}
+
+func (bw *BufWriter) flushBuf() error {
+	if bw.w == nil || len(bw.buf) == 0 {
+		return nil
+	}
+	n, err := bw.w.Write(bw.buf)
+	if n < len(bw.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	if n > 0 {
+		bw.buf = bw.buf[:copy(bw.buf, bw.buf[n:])]
+	}
+	return err
 }
 
 // Reset resets BufWriter to start writing anew.
 func (bw *BufWriter) Reset(w io.Writer) {
+	bw.lockIdle()
+	defer bw.unlockIdle()
+	bw.Error = nil
+	bw.setWriter(w)
+	bw.buf = bw.buf[:0]
+	bw.tentative = nil
+	bw.open = bw.open[:0]
+	bw.last = 0
+	if bw.meta != nil {
+		bw.meta.state = metadataState{}
+	}
+	if bw.indent != nil {
+		bw.indent.state = indentState{}
+	}
+	if bw.pos != nil {
+		bw.pos.reset()
+	}
+	if bw.size == 0 {
+		bw.size = defaultBufSize
+	}
+	bw.checkWatermarks()
+}
+
+// ResetWithBuffer is like Reset but makes buf the BufWriter's buffer. Output is appended
+// to buf, and whatever buf already holds counts as buffered output. Use it with
+// TakeBuffer to hand buffers between the BufWriter and a pool or arena without
+// copying. With a non-nil w, buf is flushed whenever it's full, so give it the
+// capacity you want the buffer to have.
+func (bw *BufWriter) ResetWithBuffer(w io.Writer, buf []byte) {
+	bw.lockIdle()
+	defer bw.unlockIdle()
 	bw.Error = nil
-	if bw.writer == nil {
-		bw.writer = bufio.NewWriter(w)
+	bw.setWriter(w)
+	bw.buf = buf
+	bw.tentative = nil
+	bw.open = bw.open[:0]
+	bw.last = lastNonSpace(buf, 0)
+	if bw.meta != nil {
+		bw.meta.state = metadataState{}
+	}
+	if bw.indent != nil {
+		bw.indent.state = indentState{}
+	}
+	if bw.pos != nil {
+		bw.pos.reset()
+		bw.pos.advance(buf)
+	}
+	bw.size = cap(buf)
+	if bw.size == 0 {
+		bw.size = defaultBufSize
+	}
+	bw.checkWatermarks()
+}
+
+// TakeBuffer returns the buffered output and gives up the buffer so the caller owns
+// it. For an in-memory BufWriter that is the whole document. The BufWriter allocates a
+// new buffer if it's written to again.
+func (bw *BufWriter) TakeBuffer() []byte {
+	bw.lockIdle()
+	defer bw.unlockIdle()
+	buf := bw.buf
+	bw.buf = nil
+	bw.checkWatermarks()
+	return buf
+}
+
+// Grow makes room for n more bytes to be written without flushing, and for values of
+// up to n bytes to be formatted without reallocating. Use it with an expected document
+// size to avoid repeatedly growing buffers. Buffered output is flushed first when
+// there isn't already room.
+func (bw *BufWriter) Grow(n int) {
+	bw.lockIdle()
+	defer bw.unlockIdle()
+	if bw.Error != nil {
+		return
+	}
+	if cap(bw.stringBuf) < n {
+		bw.stringBuf = make([]byte, 0, n)
+	}
+	if cap(bw.buf)-len(bw.buf) >= n {
+		return
+	}
+	if bw.w != nil {
+		bw.flush()
+		bw.checkWatermarks()
+		if bw.Error != nil {
+			return
+		}
+		if bw.size < n {
+			bw.size = n
+		}
+		if cap(bw.buf)-len(bw.buf) >= n {
+			return
+		}
+	}
+	buf := make([]byte, len(bw.buf), len(bw.buf)+n)
+	copy(buf, bw.buf)
+	bw.buf = buf
+}
+
+// Buffered returns the number of bytes written but not yet flushed, not counting an
+// open tentative segment.
+func (bw *BufWriter) Buffered() int {
+	if bw.idle != nil {
+		bw.idle.mu.Lock()
+		defer bw.idle.mu.Unlock()
+	}
+	return len(bw.buf)
+}
+
+func (bw *BufWriter) write(p []byte) {
+	if bw.meta != nil && !bw.meta.busy {
+		bw.writeMetadata(p)
+		return
+	}
+	if bw.indent != nil && !bw.indent.busy {
+		bw.writeIndented(p)
+		return
+	}
+	if bw.pos != nil {
+		bw.pos.advance(p)
+	}
+	bw.last = lastNonSpace(p, bw.last)
+	if bw.tentative != nil && len(bw.tentative.marks) > 0 {
+		bw.tentative.buf = append(bw.tentative.buf, p...)
+		return
+	}
+	bw.writeOutput(p)
+}
+
+// lastNonSpace returns the last byte of p that isn't whitespace, or last when there
+// isn't one.
+func lastNonSpace(p []byte, last byte) byte {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !isSpace(p[i]) {
+			return p[i]
+		}
+	}
+	return last
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// writeOutput writes p past any tentative segment and position tracking.
+func (bw *BufWriter) writeOutput(p []byte) {
+	if bw.idle != nil {
+		bw.lockIdle()
+		bw.appendOutput(p)
+		bw.unlockIdle()
+		return
+	}
+	bw.appendOutput(p)
+}
+
+func (bw *BufWriter) appendOutput(p []byte) {
+	if bw.w != nil && len(bw.buf)+len(p) > bw.size {
+		bw.flush()
+		if bw.Error == nil && len(p) >= bw.size {
+			// too big to buffer
+			var n int
+			n, bw.Error = bw.w.Write(p)
+			if n < len(p) && bw.Error == nil {
+				bw.Error = io.ErrShortWrite
+			}
+			bw.checkWatermarks()
+			return
+		}
+	}
+	if bw.Error == nil {
+		bw.buf = append(bw.buf, p...)
+	}
+	bw.checkWatermarks()
+}
+
+func (bw *BufWriter) writeString(s string) {
+	if bw.meta != nil && !bw.meta.busy {
+		bw.writeMetadataString(s)
+		return
+	}
+	if bw.indent != nil && !bw.indent.busy {
+		bw.writeIndentedString(s)
+		return
+	}
+	if bw.pos != nil {
+		bw.pos.advanceString(s)
+	}
+	for i := len(s) - 1; i >= 0; i-- {
+		if !isSpace(s[i]) {
+			bw.last = s[i]
+			break
+		}
+	}
+	if bw.tentative != nil && len(bw.tentative.marks) > 0 {
+		bw.tentative.buf = append(bw.tentative.buf, s...)
+		return
+	}
+	if bw.idle != nil {
+		bw.lockIdle()
+		bw.appendOutputString(s)
+		bw.unlockIdle()
 		return
 	}
-	bw.writer.Reset(w)
+	bw.appendOutputString(s)
+}
+
+func (bw *BufWriter) appendOutputString(s string) {
+	if bw.w != nil && len(bw.buf)+len(s) > bw.size {
+		bw.flush()
+		if bw.Error == nil && len(s) >= bw.size {
+			// too big to buffer
+			var n int
+			n, bw.Error = io.WriteString(bw.w, s)
+			if n < len(s) && bw.Error == nil {
+				bw.Error = io.ErrShortWrite
+			}
+			bw.checkWatermarks()
+			return
+		}
+	}
+	if bw.Error == nil {
+		bw.buf = append(bw.buf, s...)
+	}
+	bw.checkWatermarks()
+}
+
+func (bw *BufWriter) writeByte(b byte) {
+	if bw.meta != nil && !bw.meta.busy {
+		bw.writeMetadataByte(b)
+		return
+	}
+	if bw.indent != nil && !bw.indent.busy {
+		bw.writeIndentedByte(b)
+		return
+	}
+	if bw.pos != nil {
+		bw.pos.Offset++
+		bw.pos.advanceByte(b)
+	}
+	if !isSpace(b) {
+		bw.last = b
+	}
+	if bw.tentative != nil && len(bw.tentative.marks) > 0 {
+		bw.tentative.buf = append(bw.tentative.buf, b)
+		return
+	}
+	if bw.idle != nil {
+		bw.lockIdle()
+		bw.appendOutputByte(b)
+		bw.unlockIdle()
+		return
+	}
+	bw.appendOutputByte(b)
+}
+
+func (bw *BufWriter) appendOutputByte(b byte) {
+	if bw.w != nil && len(bw.buf) >= bw.size {
+		bw.flush()
+	}
+	if bw.Error == nil {
+		bw.buf = append(bw.buf, b)
+	}
+	bw.checkWatermarks()
 }
 
 // Raw writes a raw value.
@@ -56,7 +413,21 @@ func (bw *BufWriter) Raw(val []byte) {
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(val)
+	bw.write(val)
+}
+
+// RawValidated is like Raw but first checks that val is exactly one valid json value.
+// When it isn't nothing is written and Error is set to a *SyntaxError.
+func (bw *BufWriter) RawValidated(val []byte) {
+	if bw.Error != nil {
+		return
+	}
+	bw.Error = validateValue(val)
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.write(val)
 }
 
 // RawString is like Raw but takes a string.
@@ -64,7 +435,7 @@ func (bw *BufWriter) RawString(val string) {
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.WriteString(val)
+	bw.writeString(val)
 }
 
 // RawByte writes one single byte.
@@ -72,7 +443,7 @@ func (bw *BufWriter) RawByte(val byte) {
 	if bw.Error != nil {
 		return
 	}
-	bw.Error = bw.writer.WriteByte(val)
+	bw.writeByte(val)
 }
 
 // Int64 writes an int64 value
@@ -80,12 +451,20 @@ func (bw *BufWriter) Int64(val int64) {
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.WriteString(strconv.FormatInt(val, 10))
+	bw.valueComma()
+	bw.stringBuf = appendInt64Quoted(val, bw.quoteLargeInts, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
 }
 
 // Int64 append an int64 value
 func Int64(val int64, buf []byte) []byte {
-	return append(buf, strconv.FormatInt(val, 10)...)
+	if val < 0 && val > -10000 {
+		return appendSmallUint(uint64(-val), append(buf, '-'))
+	}
+	if val >= 0 && val < 10000 {
+		return appendSmallUint(uint64(val), buf)
+	}
+	return strconv.AppendInt(buf, val, 10)
 }
 
 // Uint64 writes a uint64 value
@@ -93,22 +472,72 @@ func (bw *BufWriter) Uint64(val uint64) {
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.WriteString(strconv.FormatUint(val, 10))
+	bw.valueComma()
+	bw.stringBuf = appendUint64Quoted(val, bw.quoteLargeInts, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
 }
 
 // Uint64 append a uint64 value
 func Uint64(val uint64, buf []byte) []byte {
-	return append(buf, strconv.FormatUint(val, 10)...)
+	if val < 10000 {
+		return appendSmallUint(val, buf)
+	}
+	return strconv.AppendUint(buf, val, 10)
+}
+
+// twoDigits holds the decimal digits of 00 through 99.
+const twoDigits = "00010203040506070809" +
+	"10111213141516171819" +
+	"20212223242526272829" +
+	"30313233343536373839" +
+	"40414243444546474849" +
+	"50515253545556575859" +
+	"60616263646566676869" +
+	"70717273747576777879" +
+	"80818283848586878889" +
+	"90919293949596979899"
+
+// appendSmallUint appends val, which must be less than 10000, without going through strconv.
+func appendSmallUint(val uint64, buf []byte) []byte {
+	switch {
+	case val < 10:
+		return append(buf, byte('0'+val))
+	case val < 100:
+		return append(buf, twoDigits[val*2], twoDigits[val*2+1])
+	case val < 1000:
+		lo := val % 100 * 2
+		return append(buf, byte('0'+val/100), twoDigits[lo], twoDigits[lo+1])
+	}
+	hi, lo := val/100*2, val%100*2
+	return append(buf, twoDigits[hi], twoDigits[hi+1], twoDigits[lo], twoDigits[lo+1])
 }
 
-// FieldName writes a fieldname in the format: "name":
+// FieldName writes a fieldname in the format: "name": escaped with the BufWriter's
+// StringOptions
 func (bw *BufWriter) FieldName(name string) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf = String(name, bw.stringBuf[:0])
-	bw.stringBuf = append(bw.stringBuf, ':')
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.valueComma()
+	if bw.keys != nil {
+		if escaped := bw.keys.get(name); escaped != nil {
+			bw.write(escaped)
+			return
+		}
+	}
+	if bw.stringOpts != (StringOptions{}) {
+		bw.stringBuf, bw.Error = FieldNameWithOptions(name, bw.stringOpts, bw.stringBuf[:0])
+		if bw.Error != nil {
+			return
+		}
+	} else {
+		bw.stringBuf = String(name, bw.stringBuf[:0])
+		bw.stringBuf = append(bw.stringBuf, ':')
+	}
+	if bw.keys != nil {
+		bw.keys.add(name, bw.stringBuf)
+	}
+	bw.write(bw.stringBuf)
 }
 
 // FieldName append a fieldname in the format: "name":
@@ -122,11 +551,12 @@ func (bw *BufWriter) Bool(val bool) {
 	if bw.Error != nil {
 		return
 	}
+	bw.valueComma()
 	if val {
-		_, bw.Error = bw.writer.WriteString("true")
+		bw.writeString("true")
 		return
 	}
-	_, bw.Error = bw.writer.WriteString("false")
+	bw.writeString("false")
 }
 
 // Bool append a bool value
@@ -137,16 +567,31 @@ func Bool(val bool, buf []byte) []byte {
 	return append(buf, `false`...)
 }
 
-// Time writes a time.Time value
+// Null writes a null value
+func (bw *BufWriter) Null() {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeString("null")
+}
+
+// Null append a null value
+func Null(buf []byte) []byte {
+	return append(buf, "null"...)
+}
+
+// Time writes a time.Time value formatted with the BufWriter's TimeOptions
 func (bw *BufWriter) Time(t time.Time) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf, bw.Error = Time(t, bw.stringBuf[:0])
+	bw.valueComma()
+	bw.stringBuf, bw.Error = TimeWithOptions(t, bw.timeOpts, bw.stringBuf[:0])
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
 }
 
 // Time append a time.Time value
@@ -154,7 +599,7 @@ func Time(t time.Time, buf []byte) ([]byte, error) {
 	if y := t.Year(); y < 0 || y >= 10000 {
 		// RFC 3339 is clear that years are 4 digits exactly.
 		// See golang.org/issue/4556#c15 for more discussion.
-		return nil, fmt.Errorf("Time.MarshalJSON: year outside of range [0,9999]")
+		return nil, errors.New("Time.MarshalJSON: year outside of range [0,9999]")
 	}
 	buf = append(buf, '"')
 	buf = t.AppendFormat(buf, time.RFC3339Nano)
@@ -162,23 +607,54 @@ func Time(t time.Time, buf []byte) ([]byte, error) {
 	return buf, nil
 }
 
-// Float64 writes a float64 value
+// Float64 writes a float64 value formatted with the BufWriter's FloatOptions
 func (bw *BufWriter) Float64(f float64) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf, bw.Error = Float64(f, bw.stringBuf[:0])
+	bw.valueComma()
+	bw.stringBuf, bw.Error = Float64WithOptions(f, bw.floatOpts, bw.stringBuf[:0])
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
 }
 
 // Float64 append a float64 value
 func Float64(f float64, buf []byte) ([]byte, error) {
+	return appendFloat(f, 64, buf)
+}
+
+// Float32 writes a float32 value formatted with the BufWriter's FloatOptions
+func (bw *BufWriter) Float32(f float32) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = appendFloatWithOptions(float64(f), 32, bw.floatOpts, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// Float32 append a float32 value. It's formatted with the fewest digits that read back
+// as the same float32, like encoding/json does.
+func Float32(f float32, buf []byte) ([]byte, error) {
+	return appendFloat(float64(f), 32, buf)
+}
+
+//go:generate go run ryu_gen.go
+
+// appendFloat appends f formatted for a float of the given bit size.
+func appendFloat(f float64, bits int, buf []byte) ([]byte, error) {
 	if math.IsInf(f, 0) || math.IsNaN(f) {
-		return buf, fmt.Errorf("unsupported value: %s", strconv.FormatFloat(f, 'g', -1, 64))
+		return buf, errors.New("unsupported value: " + strconv.FormatFloat(f, 'g', -1, bits))
+	}
+	if useRyu && bits == 64 {
+		return appendRyu(f, buf), nil
 	}
+
 	// Convert as if by ES6 number to string conversion.
 	// This matches most other JSON generators.
 	// See golang.org/issue/6384 and golang.org/issue/14135.
@@ -187,11 +663,13 @@ func Float64(f float64, buf []byte) ([]byte, error) {
 
 	abs := math.Abs(f)
 	format := byte('f')
-	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
-		format = 'e'
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
 	}
 	start := len(buf)
-	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	buf = strconv.AppendFloat(buf, f, format, -1, bits)
 
 	if format == 'e' {
 		// clean up e-09 to e-9
@@ -209,42 +687,113 @@ func (bw *BufWriter) Value(val interface{}) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf, bw.Error = Value(val, bw.stringBuf[:0])
+	bw.valueComma()
+	bw.stringBuf, bw.Error = ValueWithOptions(val, bw.valueOptions(), bw.stringBuf[:0])
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
+}
+
+// UnsupportedTypeError is returned by Value for a value it has no dedicated appender
+// for when built with either the jsonappender_strict or jsonappender_noreflect tag.
+type UnsupportedTypeError struct {
+	Value interface{}
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	msg := "jsonappender: unsupported type"
+	if name := typeName(e.Value); name != "" {
+		msg += " " + name
+	}
+	return msg + unsupportedTypeReason
+}
+
+// jsonMarshaler has the same method set as json.Marshaler. It is declared here so that
+// Value doesn't depend on encoding/json when built with jsonappender_noreflect.
+type jsonMarshaler interface {
+	MarshalJSON() ([]byte, error)
 }
 
-// Value appends any json marshallable value
+// Value appends any json marshallable value. Types without a dedicated appender are
+// encoded with json.Marshal unless built with the jsonappender_strict or
+// jsonappender_noreflect tag, in which case Value returns an *UnsupportedTypeError.
 func Value(val interface{}, buf []byte) ([]byte, error) {
 	switch v := val.(type) {
+	case nil:
+		return Null(buf), nil
 	case string:
 		return String(v, buf), nil
 	case float64:
 		return Float64(v, buf)
+	case float32:
+		return Float32(v, buf)
 	case int64:
 		return Int64(v, buf), nil
 	case int:
 		return Int64(int64(v), buf), nil
+	case int32:
+		return Int64(int64(v), buf), nil
+	case int16:
+		return Int64(int64(v), buf), nil
+	case int8:
+		return Int64(int64(v), buf), nil
 	case uint64:
 		return Uint64(v, buf), nil
 	case uint:
 		return Uint64(uint64(v), buf), nil
+	case uint32:
+		return Uint64(uint64(v), buf), nil
+	case uint16:
+		return Uint64(uint64(v), buf), nil
+	case uint8:
+		return Uint64(uint64(v), buf), nil
+	case uintptr:
+		return Uint64(uint64(v), buf), nil
+	case bool:
+		return Bool(v, buf), nil
+	case []byte:
+		return Bytes(v, buf), nil
 	case time.Time:
 		return Time(v, buf)
 	case map[string]interface{}:
 		return Object(v, buf)
 	case []interface{}:
 		return Array(v, buf)
+	case map[string]string:
+		return StringMapSorted(v, buf), nil
+	case map[string]int64:
+		return Int64MapSorted(v, buf), nil
+	case map[string]float64:
+		return Float64MapSorted(v, buf)
+	case map[int]interface{}:
+		return IntKeyObject(v, buf)
+	case map[int64]interface{}:
+		return Int64KeyObject(v, buf)
+	case map[encoding.TextMarshaler]interface{}:
+		return TextKeyObject(v, buf)
 	case JSONAppender:
+		if n := sizeHint(v); n > 0 {
+			buf = growBuf(buf, n)
+		}
 		return v.AppendJSON(buf)
-	case json.Marshaler:
+	case jsonMarshaler:
 		bb, err := v.MarshalJSON()
 		return append(buf, bb...), err
 	}
-	bb, err := json.Marshal(val)
-	return append(buf, bb...), err
+	if b, ok, err := appendNumberValue(val, buf); ok {
+		return b, err
+	}
+	if b, ok, err := appendSQLValue(val, buf); ok {
+		return b, err
+	}
+	if b, ok := appendNetipValue(val, buf); ok {
+		return b, nil
+	}
+	if n := sizeHint(val); n > 0 {
+		buf = growBuf(buf, n)
+	}
+	return appendFallback(val, buf)
 }
 
 // Object writes an object value
@@ -252,11 +801,12 @@ func (bw *BufWriter) Object(mp map[string]interface{}) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf, bw.Error = Object(mp, bw.stringBuf[:0])
+	bw.valueComma()
+	bw.stringBuf, bw.Error = ObjectWithOptions(mp, bw.valueOptions(), bw.stringBuf[:0])
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
 }
 
 // Object appends an object value
@@ -284,15 +834,23 @@ func (bw *BufWriter) Array(slice []interface{}) {
 	if bw.Error != nil {
 		return
 	}
-	bw.stringBuf, bw.Error = Array(slice, bw.stringBuf[:0])
+	bw.valueComma()
+	bw.stringBuf, bw.Error = ValueWithOptions(slice, bw.valueOptions(), bw.stringBuf[:0])
 	if bw.Error != nil {
 		return
 	}
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
 }
 
 // Array appends an array value
 func Array(slice []interface{}, buf []byte) ([]byte, error) {
+	n := 2
+	for _, v := range slice {
+		if hint := sizeHint(v); hint > 0 {
+			n += hint + 1
+		}
+	}
+	buf = growBuf(buf, n)
 	var comma bool
 	buf = append(buf, '[')
 	var err error
@@ -309,13 +867,22 @@ func Array(slice []interface{}, buf []byte) ([]byte, error) {
 	return append(buf, ']'), nil
 }
 
-// String writes a string value
+// String writes a string value escaped with the BufWriter's StringOptions
 func (bw *BufWriter) String(val string) {
 	if bw.Error != nil {
 		return
 	}
+	bw.valueComma()
+	if bw.stringOpts != (StringOptions{}) {
+		bw.stringBuf, bw.Error = appendStringWithOptions(val, bw.stringOpts, bw.stringBuf[:0])
+		if bw.Error != nil {
+			return
+		}
+		bw.write(bw.stringBuf)
+		return
+	}
 	bw.stringBuf = String(val, bw.stringBuf[:0])
-	_, bw.Error = bw.writer.Write(bw.stringBuf)
+	bw.write(bw.stringBuf)
 }
 
 // String appends a string value
@@ -359,7 +926,7 @@ func String(s string, buf []byte) []byte {
 			if start < i {
 				buf = append(buf, s[start:i]...)
 			}
-			buf = append(buf, '\\', 'u', 'f', 'f', 'd')
+			buf = append(buf, '\\', 'u', 'f', 'f', 'f', 'd')
 			i += size
 			start = i
 			continue
@@ -376,6 +943,7 @@ func String(s string, buf []byte) []byte {
 				buf = append(buf, s[start:i]...)
 			}
 			buf = append(buf, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
 			start = i
 			continue
 		}
@@ -388,6 +956,35 @@ func String(s string, buf []byte) []byte {
 	return buf
 }
 
+// Rune writes r as a one character string value
+func (bw *BufWriter) Rune(r rune) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Rune(r, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Rune appends r as a one character string value. Invalid runes are written as the
+// replacement character U+FFFD.
+func Rune(r rune, buf []byte) []byte {
+	var b [utf8.UTFMax]byte
+	n := utf8.EncodeRune(b[:], r)
+	return String(string(b[:n]), buf)
+}
+
+// quoteAppended finishes a string value from an opening quote at buf[start] followed by
+// text that was appended unescaped, escaping the text if it needs it.
+func quoteAppended(buf []byte, start int) []byte {
+	for _, b := range buf[start+1:] {
+		if b >= utf8.RuneSelf || !htmlSafeSet[b] {
+			return String(string(buf[start+1:]), buf[:start])
+		}
+	}
+	return append(buf, '"')
+}
+
 // htmlSafeSet holds the value true if the ASCII character with the given
 // array position can be safely represented inside a JSON string, embedded
 // inside of HTML <script> tags, without any additional escaping.
@@ -3,6 +3,9 @@ package jsonappender
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"math"
 	"testing"
 	"time"
 
@@ -29,6 +32,27 @@ func TestFloat64(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+func TestFloat32(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val float32, buf string) bool {
+			got, err := Float32(val, []byte(buf))
+			return matchesEncodingJSON(val, []byte(buf), got, err)
+		}, gen.Float32(), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Float32(0.1)
+	bw.RawByte(',')
+	bw.Float32(1e-7)
+	bw.Flush()
+	if out.String() != "0.1,1e-7" {
+		t.Errorf("got %q", out.String())
+	}
+}
+
 func TestInt64(t *testing.T) {
 	properties := gopter.NewProperties(gopterParams())
 	properties.Property("same as encoding/json", prop.ForAll(
@@ -37,6 +61,12 @@ func TestInt64(t *testing.T) {
 			return matchesEncodingJSON(val, []byte(buf), got, nil)
 		}, gen.Int64(), gen.AnyString(),
 	))
+	properties.Property("small values same as encoding/json", prop.ForAll(
+		func(val int64) bool {
+			got := Int64(val, nil)
+			return matchesEncodingJSON(val, nil, got, nil)
+		}, gen.Int64Range(-20000, 20000),
+	))
 	properties.TestingRun(t)
 }
 
@@ -48,6 +78,12 @@ func TestUint64(t *testing.T) {
 			return matchesEncodingJSON(val, []byte(buf), got, nil)
 		}, gen.UInt64(), gen.AnyString(),
 	))
+	properties.Property("small values same as encoding/json", prop.ForAll(
+		func(val uint64) bool {
+			got := Uint64(val, nil)
+			return matchesEncodingJSON(val, nil, got, nil)
+		}, gen.UInt64Range(0, 20000),
+	))
 	properties.TestingRun(t)
 }
 
@@ -62,6 +98,23 @@ func TestBool(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+func TestNull(t *testing.T) {
+	if got := Null([]byte("x")); string(got) != "xnull" {
+		t.Errorf("got %s", got)
+	}
+	got, err := Value(nil, nil)
+	if err != nil || string(got) != "null" {
+		t.Errorf("got %s, %v", got, err)
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Null()
+	bw.Flush()
+	if out.String() != "null" {
+		t.Errorf("got %q", out.String())
+	}
+}
+
 func TestString(t *testing.T) {
 	properties := gopter.NewProperties(gopterParams())
 	properties.Property("same as encoding/json", prop.ForAll(
@@ -70,9 +123,46 @@ func TestString(t *testing.T) {
 			return matchesEncodingJSON(val, []byte(buf), got, nil)
 		}, gen.AnyString(), gen.AnyString(),
 	))
+	properties.Property("line and paragraph separators same as encoding/json", prop.ForAll(
+		func(val string) bool {
+			got := String(val, nil)
+			return matchesEncodingJSON(val, nil, got, nil)
+		}, gen.RegexMatch("[a\u2028\u2029<]{0,8}"),
+	))
 	properties.TestingRun(t)
 }
 
+func TestRune(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val rune, buf string) bool {
+			got := Rune(val, []byte(buf))
+			return matchesEncodingJSON(string(val), []byte(buf), got, nil)
+		}, gen.Rune(), gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	for r, want := range map[rune]string{
+		'<':      `"\u003c"`,
+		'\n':     `"\n"`,
+		0xD800:   "\"\uFFFD\"",
+		'\u2028': `"\u2028"`,
+		0x1F600:  "\"\U0001F600\"",
+		-1:       "\"\uFFFD\"",
+	} {
+		if got := Rune(r, nil); string(got) != want {
+			t.Errorf("%U: got %s, wanted %s", r, got, want)
+		}
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Rune('"')
+	bw.Flush()
+	if out.String() != `"\""` {
+		t.Errorf("got %q", out.String())
+	}
+}
+
 func TestTime(t *testing.T) {
 	properties := gopter.NewProperties(gopterParams())
 	properties.Property("same as encoding/json", prop.ForAll(
@@ -99,6 +189,201 @@ func TestFieldName(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+func TestAppendFunc(t *testing.T) {
+	var fn AppendFunc = func(buf []byte) ([]byte, error) {
+		return String("hi", buf), nil
+	}
+	got, err := Array([]interface{}{fn, 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `["hi",1]` {
+		t.Errorf("got %s", got)
+	}
+}
+
+// sizedAppender checks it was given the room it asked for.
+type sizedAppender struct {
+	size int
+	room *bool
+}
+
+func (a sizedAppender) SizeJSONHint() int {
+	return a.size
+}
+
+func (a sizedAppender) AppendJSON(buf []byte) ([]byte, error) {
+	*a.room = cap(buf)-len(buf) >= a.size
+	return append(buf, bytes.Repeat([]byte("1"), a.size)...), nil
+}
+
+func TestJSONSizer(t *testing.T) {
+	var room1, room2 bool
+	got, err := Array([]interface{}{sizedAppender{size: 500, room: &room1}, sizedAppender{size: 700, room: &room2}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !room1 || !room2 || len(got) != 1203 {
+		t.Errorf("expected room for both values, got %v %v and %d bytes", room1, room2, len(got))
+	}
+	if cap(got) > 2*len(got) {
+		t.Errorf("expected the buffer to be grown once, got capacity %d", cap(got))
+	}
+
+	var room bool
+	bw := NewBufWriter(nil)
+	bw.Value(sizedAppender{size: 5000, room: &room})
+	if !room || bw.Buffered() != 5000 {
+		t.Errorf("expected room for the value, got %v and %d bytes", room, bw.Buffered())
+	}
+}
+
+func TestBufWriter_Grow(t *testing.T) {
+	var buf bytes.Buffer
+	bw := NewBufWriterSize(&buf, 16)
+	bw.RawString("[1,")
+	bw.Grow(100)
+	if buf.String() != "[1," {
+		t.Errorf("expected a flush, got %q", buf.String())
+	}
+	if cap(bw.stringBuf) < 100 {
+		t.Errorf("expected stringBuf to grow")
+	}
+	for i := 0; i < 30; i++ {
+		bw.RawString("2,")
+	}
+	if buf.Len() != 3 || bw.Buffered() != 60 {
+		t.Errorf("expected 60 bytes buffered, got %d", bw.Buffered())
+	}
+	bw.Grow(40)
+	if buf.Len() != 3 {
+		t.Errorf("expected no flush")
+	}
+	bw.Grow(50)
+	if buf.Len() != 63 {
+		t.Errorf("expected a flush")
+	}
+}
+
+func TestBufWriter_TakeBuffer(t *testing.T) {
+	var bw BufWriter
+	bw.RawString(`{"a":`)
+	bw.Int64(1)
+	bw.RawByte('}')
+	if got := string(bw.TakeBuffer()); got != `{"a":1}` {
+		t.Errorf("got %s", got)
+	}
+	if bw.Buffered() != 0 {
+		t.Errorf("expected nothing buffered, got %d", bw.Buffered())
+	}
+
+	owned := make([]byte, 0, 64)
+	owned = append(owned, "x"...)
+	bw.ResetWithBuffer(nil, owned)
+	bw.String("y")
+	got := bw.TakeBuffer()
+	if string(got) != `x"y"` || &got[0] != &owned[:1][0] {
+		t.Errorf("expected output appended to the given buffer, got %s", got)
+	}
+
+	var out bytes.Buffer
+	bw.ResetWithBuffer(&out, make([]byte, 0, 4))
+	bw.RawString("[1,2")
+	bw.RawString(",3]")
+	if out.String() != "[1,2" || bw.Buffered() != 3 {
+		t.Errorf("expected the full buffer to be flushed, got %q", out.String())
+	}
+	if got := string(bw.TakeBuffer()); got != ",3]" {
+		t.Errorf("got %s", got)
+	}
+	bw.RawString("abcdefgh")
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[1,2abcdefgh" {
+		t.Errorf("got %q", out.String())
+	}
+}
+
+// shortWriter accepts at most n bytes per write.
+type shortWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		p = p[:w.n]
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestBufWriter_shortWrite(t *testing.T) {
+	w := &shortWriter{n: 2}
+	bw := NewBufWriter(w)
+	bw.RawString("abcde")
+	if err := bw.Flush(); err != io.ErrShortWrite {
+		t.Errorf("got error %v", err)
+	}
+	if w.String() != "ab" || bw.Buffered() != 3 {
+		t.Errorf("expected unwritten output to stay buffered, got %q and %d buffered", w.String(), bw.Buffered())
+	}
+}
+
+func TestRawValidated(t *testing.T) {
+	for _, td := range []struct {
+		raw   string
+		valid bool
+	}{
+		{raw: `{"a":[1,2,{"b":null}]}`, valid: true},
+		{raw: ` "x" `, valid: true},
+		{raw: `-1.5e3`, valid: true},
+		{raw: ``},
+		{raw: `   `},
+		{raw: `{"a":}`},
+		{raw: `[1,2`},
+		{raw: `1 2`},
+		{raw: `{} x`},
+		{raw: `"\x"`},
+		{raw: `nul`},
+	} {
+		var out bytes.Buffer
+		bw := NewBufWriter(&out)
+		bw.RawValidated([]byte(td.raw))
+		bw.Flush()
+		if td.valid != json.Valid([]byte(td.raw)) {
+			t.Fatalf("%q: bad test case", td.raw)
+		}
+		if td.valid {
+			if bw.Error != nil || out.String() != td.raw {
+				t.Errorf("%q: got %q, %v", td.raw, out.String(), bw.Error)
+			}
+			continue
+		}
+		var syntaxErr *SyntaxError
+		if !errors.As(bw.Error, &syntaxErr) || out.Len() != 0 {
+			t.Errorf("%q: got %q, %v", td.raw, out.String(), bw.Error)
+		}
+	}
+}
+
+func BenchmarkInt64(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = Int64(int64(i%20000-10000), buf[:0])
+	}
+}
+
+func BenchmarkFloat64(b *testing.B) {
+	vals := []float64{0, 1, -2.5, 0.1, 123.456, 1e-7, 6.02214076e23, math.Pi, math.MaxFloat64}
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		if bb, err := Float64(vals[i%len(vals)], buf[:0]); err == nil {
+			buf = bb
+		}
+	}
+}
+
 func matchesEncodingJSON(val interface{}, buf, got []byte, gotErr error) bool {
 	want, wantErr := encodingJSONAppend(val, buf)
 	if wantErr != nil {
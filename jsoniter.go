@@ -0,0 +1,45 @@
+package jsonappender
+
+// These methods are named after jsoniter's Stream methods so code written against it
+// can move to a BufWriter call for call. They're BeginObject and friends under other
+// names, so the commas WriteMore writes are optional: values and member names inside
+// get one anyway, and WriteMore doesn't write a second one.
+
+// ObjectStart is BeginObject, like jsoniter's WriteObjectStart.
+func (bw *BufWriter) ObjectStart() {
+	bw.BeginObject()
+}
+
+// ObjectEnd is EndObject, like jsoniter's WriteObjectEnd.
+func (bw *BufWriter) ObjectEnd() {
+	bw.EndObject()
+}
+
+// ArrayStart is BeginArray, like jsoniter's WriteArrayStart.
+func (bw *BufWriter) ArrayStart() {
+	bw.BeginArray()
+}
+
+// ArrayEnd is EndArray, like jsoniter's WriteArrayEnd.
+func (bw *BufWriter) ArrayEnd() {
+	bw.EndArray()
+}
+
+// WriteObjectField is FieldName, like jsoniter's WriteObjectField.
+func (bw *BufWriter) WriteObjectField(name string) {
+	bw.FieldName(name)
+}
+
+// WriteMore writes the comma between two members or elements, like jsoniter's
+// WriteMore. It writes nothing right after an opening brace or bracket or another
+// comma.
+func (bw *BufWriter) WriteMore() {
+	if bw.Error != nil {
+		return
+	}
+	switch bw.last {
+	case 0, '{', '[', ',':
+		return
+	}
+	bw.writeByte(',')
+}
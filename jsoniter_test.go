@@ -0,0 +1,24 @@
+package jsonappender
+
+import "testing"
+
+func TestJsoniterNames(t *testing.T) {
+	bw := &BufWriter{}
+	bw.ObjectStart()
+	bw.WriteObjectField("a")
+	bw.ArrayStart()
+	bw.WriteMore()
+	bw.Int64(1)
+	bw.WriteMore()
+	bw.Int64(2)
+	bw.Int64(3)
+	bw.ArrayEnd()
+	bw.WriteMore()
+	bw.WriteObjectField("b")
+	bw.Bool(true)
+	bw.ObjectEnd()
+	err := bw.Close()
+	if got := string(bw.TakeBuffer()); got != `{"a":[1,2,3],"b":true}` || err != nil {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
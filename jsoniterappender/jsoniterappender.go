@@ -0,0 +1,34 @@
+// Package jsoniterappender lets jsoniter values be used wherever jsonappender expects a
+// JSONAppender. It is its own module so that jsonappender doesn't depend on jsoniter.
+package jsoniterappender
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/killa-beez/jsonappender"
+)
+
+// Any returns a JSONAppender that writes a directly into the destination using a
+// pooled jsoniter.Stream.
+func Any(a jsoniter.Any) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		if a == nil {
+			return append(buf, "null"...), nil
+		}
+		err := a.LastError()
+		if err != nil {
+			return buf, err
+		}
+		stream := jsoniter.ConfigDefault.BorrowStream(nil)
+		pooled := stream.Buffer()
+		stream.SetBuffer(buf)
+		a.WriteTo(stream)
+		out, err := stream.Buffer(), stream.Error
+		// don't give our buffer to the pool
+		stream.SetBuffer(pooled[:0])
+		jsoniter.ConfigDefault.ReturnStream(stream)
+		if err != nil {
+			return buf, err
+		}
+		return out, nil
+	})
+}
@@ -0,0 +1,29 @@
+package jsoniterappender
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestAny(t *testing.T) {
+	doc := []byte(`{"a":[1,2,{"b":"c"}],"d":true}`)
+	got, err := jsonappender.Array([]interface{}{
+		Any(jsoniter.Get(doc)),
+		Any(jsoniter.Get(doc, "a", 2)),
+		Any(jsoniter.Wrap("x")),
+		Any(nil),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `[{"a":[1,2,{"b":"c"}],"d":true},{"b":"c"},"x",null]` {
+		t.Errorf("got %s", got)
+	}
+
+	_, err = Any(jsoniter.Get(doc, "missing")).AppendJSON(nil)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,111 @@
+package jsonappender
+
+import "unicode/utf8"
+
+// InvalidCallbackError is returned by AppendJSONP for a callback name that isn't a
+// plain javascript identifier or dotted path of identifiers.
+type InvalidCallbackError struct {
+	Callback string
+}
+
+func (e *InvalidCallbackError) Error() string {
+	return "jsonappender: invalid JSONP callback name"
+}
+
+// ValidCallback reports whether name is safe to use as a JSONP callback. It accepts
+// identifiers made of ASCII letters, digits, '_' and '$', optionally joined by dots,
+// like "cb" or "jQuery_123.done".
+func ValidCallback(name string) bool {
+	if name == "" || len(name) > 128 {
+		return false
+	}
+	start := true
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '.':
+			if start {
+				return false
+			}
+			start = true
+			continue
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '$':
+		case c >= '0' && c <= '9':
+			if start {
+				return false
+			}
+		default:
+			return false
+		}
+		start = false
+	}
+	return !start
+}
+
+// AppendJSONP appends doc wrapped in a call to callback. The output starts with an
+// empty comment, which keeps the response from being sniffed as another content type,
+// and doc is made safe for script contexts like AppendScriptSafe does. Nothing is
+// appended when callback isn't valid.
+func AppendJSONP(callback string, doc JSONAppender, buf []byte) ([]byte, error) {
+	if !ValidCallback(callback) {
+		return buf, &InvalidCallbackError{Callback: callback}
+	}
+	start := len(buf)
+	buf = append(buf, "/**/"...)
+	buf = append(buf, callback...)
+	buf = append(buf, '(')
+	buf, err := AppendScriptSafe(doc, buf)
+	if err != nil {
+		return buf[:start], err
+	}
+	return append(buf, ')', ';'), nil
+}
+
+// AppendScriptSafe appends doc so it can be embedded directly in an HTML <script>
+// element. '<', '>' and '&' are escaped, which prevents a "</script>" or "<!--"
+// anywhere in the document, and so are U+2028 and U+2029, which older javascript
+// engines don't allow in string literals. These can only appear inside json strings,
+// so escaping them doesn't change the document's value. Values written by this
+// package are already escaped this way; AppendScriptSafe takes care of anything
+// appended raw.
+func AppendScriptSafe(doc JSONAppender, buf []byte) ([]byte, error) {
+	start := len(buf)
+	buf, err := doc.AppendJSON(buf)
+	if err != nil {
+		return buf, err
+	}
+	for i := start; i < len(buf); i++ {
+		c := buf[i]
+		if c == '<' || c == '>' || c == '&' || c == 0xE2 {
+			return escapeScriptUnsafe(buf, i), nil
+		}
+	}
+	return buf, nil
+}
+
+// escapeScriptUnsafe rewrites buf[i:] with unsafe characters escaped.
+func escapeScriptUnsafe(buf []byte, i int) []byte {
+	const hex = "0123456789abcdef"
+	rest := append([]byte(nil), buf[i:]...)
+	buf = buf[:i]
+	for j := 0; j < len(rest); {
+		c := rest[j]
+		switch c {
+		case '<', '>', '&':
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			j++
+			continue
+		}
+		if c == 0xE2 {
+			r, size := utf8.DecodeRune(rest[j:])
+			if r == '\u2028' || r == '\u2029' {
+				buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+				j += size
+				continue
+			}
+		}
+		buf = append(buf, c)
+		j++
+	}
+	return buf
+}
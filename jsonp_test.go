@@ -0,0 +1,53 @@
+package jsonappender
+
+import "testing"
+
+func TestValidCallback(t *testing.T) {
+	for name, want := range map[string]bool{
+		"cb":                      true,
+		"jQuery_123.done":         true,
+		"$":                       true,
+		"a.b.c":                   true,
+		"":                        false,
+		"1cb":                     false,
+		"a..b":                    false,
+		".a":                      false,
+		"a.":                      false,
+		"a.1":                     false,
+		"alert(1)":                false,
+		"cb;evil":                 false,
+		"a[0]":                    false,
+		"café":                    false,
+		string(make([]byte, 129)): false,
+	} {
+		if ValidCallback(name) != want {
+			t.Errorf("%q: expected %v", name, want)
+		}
+	}
+}
+
+func TestAppendJSONP(t *testing.T) {
+	doc := AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, `{"html":"</script><!--&","ls":"a`+"\u2028\u2029"+`b","é":1}`...), nil
+	})
+	got, err := AppendJSONP("cb.done", doc, []byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `x/**/cb.done({"html":"\u003c/script\u003e\u003c!--\u0026","ls":"a\u2028\u2029b","é":1});`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	got, err = AppendJSONP("alert(1)//", doc, []byte("x"))
+	if _, ok := err.(*InvalidCallbackError); !ok || string(got) != "x" {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	got, err = AppendScriptSafe(AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, `[1,"…"]`...), nil
+	}), nil)
+	if err != nil || string(got) != `[1,"…"]` {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package kafkavalue encodes Kafka record values with reused buffers. Producers hold a
+// value until its batch is acknowledged, so buffers are kept per partition and handed
+// back with Release once the producer is done with them.
+package kafkavalue
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+const (
+	// maxFree is the most buffers kept for each partition.
+	maxFree = 256
+	// maxPooledSize keeps one huge value from pinning its buffer forever.
+	maxPooledSize = 1 << 20
+)
+
+// SchemaRegistryPrefix returns the Confluent Schema Registry framing for schemaID, a
+// zero magic byte followed by the big-endian id.
+func SchemaRegistryPrefix(schemaID uint32) []byte {
+	prefix := make([]byte, 5)
+	binary.BigEndian.PutUint32(prefix[1:], schemaID)
+	return prefix
+}
+
+// Encoder encodes values as json after an optional prefix. It's safe for concurrent
+// use.
+type Encoder struct {
+	prefix []byte
+	pools  []pool
+}
+
+type pool struct {
+	mu   sync.Mutex
+	free [][]byte
+}
+
+// NewEncoder returns an Encoder that starts every value with prefix, which may be nil.
+// It keeps buffers for partitions numbered 0 to partitions-1. Other partitions, like -1
+// for one the producer picks later, share them.
+func NewEncoder(partitions int, prefix []byte) *Encoder {
+	if partitions < 1 {
+		partitions = 1
+	}
+	return &Encoder{
+		prefix: prefix,
+		pools:  make([]pool, partitions),
+	}
+}
+
+func (e *Encoder) pool(partition int32) *pool {
+	return &e.pools[uint32(partition)%uint32(len(e.pools))]
+}
+
+// Encode returns the value for a record for partition. v can be anything
+// jsonappender.Value accepts, including a JSONAppender. Pass the value to Release when
+// the producer is done with it.
+func (e *Encoder) Encode(partition int32, v interface{}) ([]byte, error) {
+	p := e.pool(partition)
+	var buf []byte
+	p.mu.Lock()
+	if n := len(p.free); n > 0 {
+		buf = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+	}
+	p.mu.Unlock()
+	buf = append(buf[:0], e.prefix...)
+	value, err := jsonappender.Value(v, buf)
+	if err != nil {
+		e.Release(partition, value)
+		return nil, err
+	}
+	return value, nil
+}
+
+// Release gives value's buffer back for reuse by partition. value must not be used
+// afterwards.
+func (e *Encoder) Release(partition int32, value []byte) {
+	if cap(value) == 0 || cap(value) > maxPooledSize {
+		return
+	}
+	p := e.pool(partition)
+	p.mu.Lock()
+	if len(p.free) < maxFree {
+		p.free = append(p.free, value[:0])
+	}
+	p.mu.Unlock()
+}
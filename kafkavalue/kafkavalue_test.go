@@ -0,0 +1,37 @@
+package kafkavalue
+
+import (
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	e := NewEncoder(2, SchemaRegistryPrefix(258))
+	value, err := e.Encode(1, map[string]interface{}{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\x00\x00\x00\x01\x02" + `{"a":1}`
+	if string(value) != want {
+		t.Errorf("got %q, wanted %q", value, want)
+	}
+	e.Release(1, value)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		value, err := e.Encode(1, "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Release(1, value)
+	})
+	if allocs > 0 {
+		t.Errorf("got %v allocs per value, wanted none", allocs)
+	}
+
+	if _, err := e.Encode(-1, make(chan int)); err == nil {
+		t.Error("expected an error")
+	}
+	value, err = NewEncoder(0, nil).Encode(5, []interface{}{true})
+	if err != nil || string(value) != "[true]" {
+		t.Errorf("got %q, %v", value, err)
+	}
+}
@@ -0,0 +1,42 @@
+package jsonappender
+
+// Key is a field name escaped ahead of time, for names written often enough that
+// escaping them each time shows up in profiles. The zero Key writes nothing.
+type Key struct {
+	// escaped is the name as FieldName writes it, colon included.
+	escaped string
+}
+
+// NewKey escapes name like FieldName with the default StringOptions. It fails when
+// name isn't valid UTF-8.
+func NewKey(name string) (Key, error) {
+	b, err := FieldNameWithOptions(name, StringOptions{InvalidUTF8: InvalidUTF8Fail}, nil)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{escaped: string(b)}, nil
+}
+
+// MustKey is like NewKey but panics when name isn't valid UTF-8. Use it for keys in
+// package level variables.
+func MustKey(name string) Key {
+	k, err := NewKey(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// AppendKey appends k in the format: "name":
+func AppendKey(k Key, buf []byte) []byte {
+	return append(buf, k.escaped...)
+}
+
+// Key writes k in the format: "name":
+func (bw *BufWriter) Key(k Key) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.writeString(k.escaped)
+}
@@ -0,0 +1,36 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+var testKey = MustKey("<id>")
+
+func TestKey(t *testing.T) {
+	if got := AppendKey(testKey, []byte("{")); string(got) != string(FieldName("<id>", []byte("{"))) {
+		t.Errorf("got %s", got)
+	}
+	if _, err := NewKey("\xff"); err == nil {
+		t.Error("expected an error for invalid UTF-8")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected MustKey to panic")
+			}
+		}()
+		MustKey("\xff")
+	}()
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.RawByte('{')
+	bw.Key(testKey)
+	bw.Int64(1)
+	bw.RawByte('}')
+	bw.Flush()
+	if want := `{"\u003cid\u003e":1}`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+}
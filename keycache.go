@@ -0,0 +1,58 @@
+package jsonappender
+
+import "container/list"
+
+// keyCache is a least recently used cache of escaped field names.
+type keyCache struct {
+	size    int
+	entries map[string]*list.Element
+	// order has the most recently used entry at the front
+	order list.List
+}
+
+type keyCacheEntry struct {
+	name    string
+	escaped []byte
+}
+
+// SetKeyCache makes FieldName keep up to size escaped names, so names written
+// repeatedly are escaped only once. The least recently used name is dropped when the
+// cache is full. A size of 0 or less turns the cache off.
+func (bw *BufWriter) SetKeyCache(size int) {
+	if size <= 0 {
+		bw.keys = nil
+		return
+	}
+	bw.keys = &keyCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the escaped name, or nil when it isn't cached.
+func (c *keyCache) get(name string) []byte {
+	el, ok := c.entries[name]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*keyCacheEntry).escaped
+}
+
+func (c *keyCache) add(name string, escaped []byte) {
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		delete(c.entries, oldest.Value.(*keyCacheEntry).name)
+		c.order.Remove(oldest)
+	}
+	c.entries[name] = c.order.PushFront(&keyCacheEntry{
+		name:    name,
+		escaped: append([]byte(nil), escaped...),
+	})
+}
+
+// clear drops every entry, for when the StringOptions they were escaped with change.
+func (c *keyCache) clear() {
+	c.entries = make(map[string]*list.Element, c.size)
+	c.order.Init()
+}
@@ -0,0 +1,39 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeyCache(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetKeyCache(2)
+	for _, name := range []string{"a", "b", "a", "c", "b", "<"} {
+		bw.FieldName(name)
+	}
+	bw.SetStringOptions(StringOptions{NoHTMLEscape: true})
+	bw.FieldName("<")
+	bw.Flush()
+	if want := `"a":"b":"a":"c":"b":"\u003c":"<":`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+	c := bw.keys
+	if c.order.Len() != 1 || len(c.entries) != 1 {
+		t.Errorf("got %d entries", c.order.Len())
+	}
+
+	bw.SetStringOptions(StringOptions{})
+	for _, name := range []string{"a", "b", "a", "c"} {
+		bw.FieldName(name)
+	}
+	// b was the least recently used when c was added
+	if c.get("b") != nil || string(c.get("a")) != `"a":` || string(c.get("c")) != `"c":` {
+		t.Errorf("unexpected cache contents")
+	}
+
+	bw.SetKeyCache(0)
+	if bw.keys != nil {
+		t.Error("expected the cache to be off")
+	}
+}
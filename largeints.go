@@ -0,0 +1,33 @@
+package jsonappender
+
+// maxSafeInt is the largest integer a float64, and so a JavaScript number, holds exactly.
+const maxSafeInt = 1<<53 - 1
+
+// SetQuoteLargeInts turns quoting of large integers on or off. When it's on, the
+// BufWriter's integer methods, Int64Array, Int64Matrix, Int64Map and Int64MapSorted
+// write integers outside of JavaScript's safe range, ±(2^53-1), as strings like
+// Int64String does, so clients parsing numbers as float64 don't silently round them.
+// So do Value, Object and Array, for integers in maps and slices too. Smaller integers
+// are still written as numbers. Values encoded by reflection, like struct fields, and
+// json.Marshaler output aren't changed.
+func (bw *BufWriter) SetQuoteLargeInts(quote bool) {
+	bw.quoteLargeInts = quote
+}
+
+// appendInt64Quoted appends val like Int64, or like Int64String when quote is set and
+// val is outside of the safe range.
+func appendInt64Quoted(val int64, quote bool, buf []byte) []byte {
+	if quote && (val > maxSafeInt || val < -maxSafeInt) {
+		return Int64String(val, buf)
+	}
+	return Int64(val, buf)
+}
+
+// appendUint64Quoted appends val like Uint64, or like Uint64String when quote is set
+// and val is outside of the safe range.
+func appendUint64Quoted(val uint64, quote bool, buf []byte) []byte {
+	if quote && val > maxSafeInt {
+		return Uint64String(val, buf)
+	}
+	return Uint64(val, buf)
+}
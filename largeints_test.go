@@ -0,0 +1,62 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestQuoteLargeInts(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetQuoteLargeInts(true)
+	bw.Int64(maxSafeInt)
+	bw.RawByte(',')
+	bw.Int64(maxSafeInt + 1)
+	bw.RawByte(',')
+	bw.Int64(-maxSafeInt)
+	bw.RawByte(',')
+	bw.Int64(math.MinInt64)
+	bw.RawByte(',')
+	bw.Uint64(maxSafeInt)
+	bw.RawByte(',')
+	bw.Uint64(math.MaxUint64)
+	bw.SetQuoteLargeInts(false)
+	bw.RawByte(',')
+	bw.Int64(1 << 60)
+	bw.Flush()
+	want := `9007199254740991,"9007199254740992",-9007199254740991,"-9223372036854775808",` +
+		`9007199254740991,"18446744073709551615",1152921504606846976`
+	if out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+}
+
+func TestQuoteLargeInts_collections(t *testing.T) {
+	const big = int64(1 << 60)
+	var bw BufWriter
+	bw.SetQuoteLargeInts(true)
+	bw.BeginArray()
+	bw.Value(big)
+	bw.Value(uint64(1 << 60))
+	bw.Value([]interface{}{big, 1, map[string]interface{}{"a": big}})
+	bw.Int64Array([]int64{1, big})
+	bw.Int64Matrix([][]int64{{big}})
+	bw.Int64Map(map[string]int64{"b": big})
+	bw.Int64MapSorted(map[string]int64{"c": big})
+	bw.Value(map[string]int64{"d": big})
+	bw.Object(map[string]interface{}{"e": big})
+	bw.EndArray()
+	const q = `"1152921504606846976"`
+	want := `[` + q + `,` + q + `,[` + q + `,1,{"a":` + q + `}],[1,` + q + `],[[` + q + `]],{"b":` + q +
+		`},{"c":` + q + `},{"d":` + q + `},{"e":` + q + `}]`
+	if got := string(bw.TakeBuffer()); got != want || bw.Error != nil {
+		t.Errorf("got %s, %v\nwanted %s", got, bw.Error, want)
+	}
+
+	bw.SetQuoteLargeInts(false)
+	bw.Value([]interface{}{big})
+	if got := string(bw.TakeBuffer()); got != `[1152921504606846976]` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,123 @@
+package jsonappender
+
+import (
+	"encoding"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// The KeyObject appenders write maps with keys other than strings the way
+// encoding/json does, without reflection: integer keys become their decimal string and
+// TextMarshaler keys the text they marshal to, members are sorted by those names and
+// a nil map is null. Value uses them for these map types. Maps with other key or value
+// types still go through the encoding/json fallback.
+
+// keyedMember is a member of a KeyObject map with its name worked out.
+type keyedMember struct {
+	name string
+	val  interface{}
+}
+
+// IntKeyObject appends mp as an object with the keys as decimal strings.
+func IntKeyObject(mp map[int]interface{}, buf []byte) ([]byte, error) {
+	if mp == nil {
+		return Null(buf), nil
+	}
+	members := make([]keyedMember, 0, len(mp))
+	for k, v := range mp {
+		members = append(members, keyedMember{name: strconv.Itoa(k), val: v})
+	}
+	return appendKeyedMembers(members, buf)
+}
+
+// Int64KeyObject appends mp as an object with the keys as decimal strings.
+func Int64KeyObject(mp map[int64]interface{}, buf []byte) ([]byte, error) {
+	if mp == nil {
+		return Null(buf), nil
+	}
+	members := make([]keyedMember, 0, len(mp))
+	for k, v := range mp {
+		members = append(members, keyedMember{name: strconv.FormatInt(k, 10), val: v})
+	}
+	return appendKeyedMembers(members, buf)
+}
+
+// TextKeyObject appends mp as an object with the keys' MarshalText output as names. A
+// nil key is an error.
+func TextKeyObject(mp map[encoding.TextMarshaler]interface{}, buf []byte) ([]byte, error) {
+	if mp == nil {
+		return Null(buf), nil
+	}
+	members := make([]keyedMember, 0, len(mp))
+	for k, v := range mp {
+		if k == nil {
+			return buf, errors.New("jsonappender: nil map key")
+		}
+		text, err := k.MarshalText()
+		if err != nil {
+			return buf, err
+		}
+		members = append(members, keyedMember{name: string(text), val: v})
+	}
+	return appendKeyedMembers(members, buf)
+}
+
+// appendKeyedMembers appends members as an object sorted by name.
+func appendKeyedMembers(members []keyedMember, buf []byte) ([]byte, error) {
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].name < members[j].name
+	})
+	buf = append(buf, '{')
+	var err error
+	for i := range members {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(members[i].name, buf)
+		buf, err = Value(members[i].val, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// IntKeyObject writes an object value like IntKeyObject.
+func (bw *BufWriter) IntKeyObject(mp map[int]interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = IntKeyObject(mp, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// Int64KeyObject writes an object value like Int64KeyObject.
+func (bw *BufWriter) Int64KeyObject(mp map[int64]interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = Int64KeyObject(mp, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// TextKeyObject writes an object value like TextKeyObject.
+func (bw *BufWriter) TextKeyObject(mp map[encoding.TextMarshaler]interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = TextKeyObject(mp, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,51 @@
+package jsonappender
+
+import (
+	"encoding"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type upperKey string
+
+func (k upperKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(k))), nil
+}
+
+func TestKeyObjects(t *testing.T) {
+	for _, mp := range []interface{}{
+		map[int]interface{}{-1: "a", 2: []interface{}{true}, 0: nil},
+		map[int64]interface{}{1 << 60: 1.5, -3: map[string]interface{}{}},
+		map[encoding.TextMarshaler]interface{}{upperKey("a"): "x", upperKey("b\n"): 2},
+		map[int]interface{}{},
+		map[int]interface{}{10: 1, 9: 2, -1: 3},
+		map[int]interface{}(nil),
+		map[int64]interface{}(nil),
+		map[encoding.TextMarshaler]interface{}(nil),
+	} {
+		got, err := Value(mp, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(mp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("got %s, wanted %s", got, want)
+		}
+	}
+
+	bw := &BufWriter{}
+	bw.IntKeyObject(map[int]interface{}{7: "a"})
+	bw.Int64KeyObject(map[int64]interface{}{-7: "b"})
+	bw.TextKeyObject(map[encoding.TextMarshaler]interface{}{upperKey("c"): "c"})
+	if got := string(bw.TakeBuffer()); got != `{"7":"a"}{"-7":"b"}{"C":"c"}` {
+		t.Errorf("got %s", got)
+	}
+
+	if _, err := TextKeyObject(map[encoding.TextMarshaler]interface{}{nil: 1}, nil); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,67 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBufferSize keeps unusually large buffers from being held by marshalerBufPool.
+const maxPooledBufferSize = 64 << 10
+
+var marshalerBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// MarshalerFromAppender returns a json.Marshaler that encodes with a.
+// AppendJSON runs against a pooled buffer that is copied into the returned slice.
+func MarshalerFromAppender(a JSONAppender) json.Marshaler {
+	if m, ok := a.(marshalerAppender); ok {
+		return m.m
+	}
+	return appenderMarshaler{a: a}
+}
+
+// AppenderFromMarshaler returns a JSONAppender that encodes with m.
+func AppenderFromMarshaler(m json.Marshaler) JSONAppender {
+	if a, ok := m.(appenderMarshaler); ok {
+		return a.a
+	}
+	return marshalerAppender{m: m}
+}
+
+type appenderMarshaler struct {
+	a JSONAppender
+}
+
+func (am appenderMarshaler) MarshalJSON() ([]byte, error) {
+	bp := marshalerBufPool.Get().(*[]byte)
+	buf, err := am.a.AppendJSON((*bp)[:0])
+	var out []byte
+	if err == nil {
+		out = make([]byte, len(buf))
+		copy(out, buf)
+	}
+	if cap(buf) <= maxPooledBufferSize {
+		*bp = buf
+		marshalerBufPool.Put(bp)
+	}
+	return out, err
+}
+
+type marshalerAppender struct {
+	m json.Marshaler
+}
+
+func (ma marshalerAppender) AppendJSON(buf []byte) ([]byte, error) {
+	bb, err := ma.m.MarshalJSON()
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, bb...), nil
+}
@@ -0,0 +1,52 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMarshalerFromAppender(t *testing.T) {
+	a := AppendFunc(func(buf []byte) ([]byte, error) {
+		return Object(map[string]interface{}{"a": "<b>"}, buf)
+	})
+	got, err := json.Marshal(map[string]interface{}{
+		"x": MarshalerFromAppender(a),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"x":{"a":"\u003cb\u003e"}}` {
+		t.Errorf("got %s", got)
+	}
+
+	wantErr := errors.New("fail")
+	_, err = MarshalerFromAppender(AppendFunc(func(buf []byte) ([]byte, error) {
+		return buf, wantErr
+	})).MarshalJSON()
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestAppenderFromMarshaler(t *testing.T) {
+	tm := time.Date(2020, 5, 6, 7, 8, 9, 10, time.UTC)
+	got, err := AppenderFromMarshaler(tm).AppendJSON([]byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `x"2020-05-06T07:08:09.00000001Z"` {
+		t.Errorf("got %s", got)
+	}
+
+	a := AppendFunc(func(buf []byte) ([]byte, error) {
+		return buf, nil
+	})
+	if _, ok := AppenderFromMarshaler(MarshalerFromAppender(a)).(AppendFunc); !ok {
+		t.Error("expected the original appender back")
+	}
+}
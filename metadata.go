@@ -0,0 +1,117 @@
+package jsonappender
+
+// MetadataField is a member SetMetadata puts first in every top-level object.
+type MetadataField struct {
+	Name  string
+	Value string
+}
+
+// metadata injects members into top-level objects as they're written. It follows
+// enough of the json structure to know where the top-level objects start.
+type metadata struct {
+	members []byte
+	state   metadataState
+	// busy is set while metadata writes through the BufWriter itself.
+	busy    bool
+	scratch []byte
+}
+
+// metadataState is saved by BeginTentative so Discard can go back to it.
+type metadataState struct {
+	depth    int
+	inString bool
+	escaped  bool
+	// comma is set after members were injected until it's known whether the object has
+	// members of its own that need a comma in front of them. Writers that track commas
+	// themselves see the injected members and write it, so it's dropped then.
+	comma bool
+}
+
+// SetMetadata makes fields the first members of every top-level object written from
+// now on, like a "$schema" or "_type" member, so documents are self-describing without
+// changing the code that writes them. Call it with no fields to stop. The BufWriter has
+// to look at everything written to find the top-level objects, which makes writing
+// slower.
+func (bw *BufWriter) SetMetadata(fields ...MetadataField) {
+	if len(fields) == 0 {
+		bw.meta = nil
+		return
+	}
+	var members []byte
+	for i, f := range fields {
+		if i > 0 {
+			members = append(members, ',')
+		}
+		members = FieldName(f.Name, members)
+		members = String(f.Value, members)
+	}
+	bw.meta = &metadata{
+		members: members,
+	}
+}
+
+func (bw *BufWriter) writeMetadataString(s string) {
+	m := bw.meta
+	m.scratch = append(m.scratch[:0], s...)
+	bw.writeMetadata(m.scratch)
+}
+
+func (bw *BufWriter) writeMetadataByte(b byte) {
+	m := bw.meta
+	m.scratch = append(m.scratch[:0], b)
+	bw.writeMetadata(m.scratch)
+}
+
+// writeMetadata writes p, injecting the members after the opening brace of top-level
+// objects.
+func (bw *BufWriter) writeMetadata(p []byte) {
+	m := bw.meta
+	m.busy = true
+	defer func() { m.busy = false }()
+	st := &m.state
+	start := 0
+	for i, c := range p {
+		if st.inString {
+			switch {
+			case st.escaped:
+				st.escaped = false
+			case c == '\\':
+				st.escaped = true
+			case c == '"':
+				st.inString = false
+			}
+			continue
+		}
+		if st.comma {
+			switch c {
+			case ' ', '\t', '\n', '\r':
+				continue
+			case '}', ',':
+			default:
+				bw.write(p[start:i])
+				bw.writeByte(',')
+				start = i
+			}
+			st.comma = false
+		}
+		switch c {
+		case '"':
+			st.inString = true
+		case '{':
+			if st.depth == 0 {
+				bw.write(p[start : i+1])
+				bw.write(m.members)
+				start = i + 1
+				st.comma = true
+			}
+			st.depth++
+		case '[':
+			st.depth++
+		case '}', ']':
+			if st.depth > 0 {
+				st.depth--
+			}
+		}
+	}
+	bw.write(p[start:])
+}
@@ -0,0 +1,77 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBufWriter_SetMetadata(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriterSize(&out, 8)
+	bw.SetMetadata(MetadataField{Name: "$schema", Value: "https://example.com/v1"}, MetadataField{Name: "_version", Value: "1"})
+	bw.RawString(`{"a":"{\"}","b":[{"c":{}}]}`)
+	bw.RawByte('\n')
+	bw.RawByte('{')
+	bw.RawString(" }\n[{},")
+	bw.String("{")
+	bw.RawString("] ")
+
+	bw.BeginTentative()
+	bw.RawString(`{"x":`)
+	bw.Discard()
+	bw.Object(map[string]interface{}{"n": 1})
+
+	bw.SetMetadata()
+	bw.RawString("{}")
+	bw.Flush()
+	if bw.Error != nil {
+		t.Fatal(bw.Error)
+	}
+	const m = `"$schema":"https://example.com/v1","_version":"1"`
+	const want = `{` + m + `,"a":"{\"}","b":[{"c":{}}]}` + "\n" +
+		`{` + m + " }\n" + `[{},"{"] {` + m + `,"n":1}{}`
+	if out.String() != want {
+		t.Errorf("got  %s\nwant %s", out.String(), want)
+	}
+}
+
+func TestSetMetadata_fields(t *testing.T) {
+	var bw BufWriter
+	bw.SetMetadata(MetadataField{Name: "t", Value: "x"})
+	bw.RawByte('{')
+	bw.StringField("a", "b")
+	bw.Int64Field("c", 1)
+	bw.RawByte('}')
+	if got, want := string(bw.TakeBuffer()), `{"t":"x","a":"b","c":1}`; got != want || bw.Error != nil {
+		t.Errorf("got %s, %v, wanted %s", got, bw.Error, want)
+	}
+}
+
+func TestSetMetadata_nesting(t *testing.T) {
+	var bw BufWriter
+	bw.SetMetadata(MetadataField{Name: "t", Value: "x"})
+	bw.BeginObject()
+	bw.FieldName("a")
+	bw.Int64(1)
+	bw.EndObject()
+	bw.ObjectStart()
+	bw.WriteObjectField("b")
+	bw.Int64(2)
+	bw.WriteMore()
+	bw.WriteObjectField("c")
+	bw.Int64(3)
+	bw.ObjectEnd()
+	if got, want := string(bw.TakeBuffer()), `{"t":"x","a":1}{"t":"x","b":2,"c":3}`; got != want || bw.Error != nil {
+		t.Errorf("got %s, %v, wanted %s", got, bw.Error, want)
+	}
+
+	bw.Reset(nil)
+	bw.SetIndent("", " ")
+	bw.BeginObject()
+	bw.FieldName("a")
+	bw.Int64(1)
+	bw.EndObject()
+	if got, want := string(bw.TakeBuffer()), "{\n \"t\": \"x\",\n \"a\": 1\n}"; got != want || bw.Error != nil {
+		t.Errorf("got %q, %v, wanted %q", got, bw.Error, want)
+	}
+}
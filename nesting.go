@@ -0,0 +1,108 @@
+package jsonappender
+
+import "strconv"
+
+// NestingError is set on a BufWriter for an EndObject or EndArray that doesn't match
+// the innermost container opened with BeginObject or BeginArray. Flush and Close
+// return one for output left unfinished.
+type NestingError struct {
+	Msg string
+}
+
+func (e *NestingError) Error() string {
+	return "jsonappender: " + e.Msg
+}
+
+// BeginObject starts an object. Between it and the matching EndObject, FieldName and
+// the Field methods write a comma in front of every member but the first, and in an
+// array started with BeginArray the value methods write one in front of every element
+// but the first. Like the Field methods they look at the last byte written to tell, so
+// values written by hand in between are fine.
+func (bw *BufWriter) BeginObject() {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.open = append(bw.open, '{')
+	bw.writeByte('{')
+}
+
+// EndObject ends the object started by the matching BeginObject.
+func (bw *BufWriter) EndObject() {
+	bw.end('{', '}')
+}
+
+// BeginArray starts an array. See BeginObject.
+func (bw *BufWriter) BeginArray() {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.open = append(bw.open, '[')
+	bw.writeByte('[')
+}
+
+// EndArray ends the array started by the matching BeginArray.
+func (bw *BufWriter) EndArray() {
+	bw.end('[', ']')
+}
+
+// Depth returns the number of containers started with BeginObject or BeginArray that
+// haven't been ended.
+func (bw *BufWriter) Depth() int {
+	return len(bw.open)
+}
+
+func (bw *BufWriter) end(begin, end byte) {
+	if bw.Error != nil {
+		return
+	}
+	n := len(bw.open) - 1
+	switch {
+	case n < 0:
+		bw.Error = &NestingError{Msg: "End" + containerName(end) + " without Begin" + containerName(end)}
+		return
+	case bw.open[n] != begin:
+		bw.Error = &NestingError{Msg: "End" + containerName(end) + " doesn't match Begin" + containerName(bw.open[n])}
+		return
+	}
+	bw.open = bw.open[:n]
+	bw.writeByte(end)
+}
+
+// unfinished returns a *NestingError when the output so far isn't a complete document
+// because of BeginObject or BeginArray.
+func (bw *BufWriter) unfinished() error {
+	n := len(bw.open)
+	switch {
+	case n == 0:
+		return nil
+	case bw.last == ':':
+		return &NestingError{Msg: "member name without a value"}
+	case n == 1:
+		return &NestingError{Msg: "1 container left open"}
+	}
+	return &NestingError{Msg: strconv.Itoa(n) + " containers left open"}
+}
+
+func containerName(c byte) string {
+	if c == '{' || c == '}' {
+		return "Object"
+	}
+	return "Array"
+}
+
+// valueComma writes the comma in front of a value or member name inside a container
+// started with BeginObject or BeginArray, unless the last thing written opened a
+// container, was a comma or was a member name. It's called by the value methods,
+// FieldName and the builders.
+func (bw *BufWriter) valueComma() {
+	if len(bw.open) == 0 || bw.Error != nil {
+		return
+	}
+	switch bw.last {
+	case 0, '{', '[', ',', ':':
+		return
+	}
+	bw.writeByte(',')
+}
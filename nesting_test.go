@@ -0,0 +1,114 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNesting(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BeginArray()
+	bw.Int64(1)
+	bw.String("two")
+	bw.BeginObject()
+	bw.FieldName("a")
+	bw.BeginArray()
+	bw.EndArray()
+	bw.FieldName("b")
+	bw.Bool(true)
+	bw.StringField("c", "d")
+	bw.FieldName("e")
+	bw.BuildObject().Int("f", 1).End()
+	bw.EndObject()
+	bw.Int64Array([]int64{2, 3})
+	bw.BuildArray().Null().End()
+	bw.Null()
+	if bw.Depth() != 1 {
+		t.Errorf("got depth %d", bw.Depth())
+	}
+	bw.EndArray()
+	err := bw.Flush()
+	want := `[1,"two",{"a":[],"b":true,"c":"d","e":{"f":1}},[2,3],[null],null]`
+	if err != nil || out.String() != want {
+		t.Errorf("got %s, %v\nwanted %s", out.String(), err, want)
+	}
+
+	bw.Reset(nil)
+	bw.BeginObject()
+	bw.EndArray()
+	if _, ok := bw.Error.(*NestingError); !ok {
+		t.Errorf("got %v", bw.Error)
+	}
+	bw.Reset(nil)
+	bw.EndObject()
+	if bw.Error == nil || bw.Error.Error() != "jsonappender: EndObject without BeginObject" {
+		t.Errorf("got %v", bw.Error)
+	}
+}
+
+func TestNestingHelpers(t *testing.T) {
+	shards := NewShards(2)
+	err := shards.Run(func(i int, sh *Shard) error {
+		sh.Value(i + 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.BeginArray()
+	bw.Int64(0)
+	bw.ShardedArray(shards)
+	o := NewSparseObject(bw)
+	o.Begin()
+	o.Int64("a", 2)
+	o.End() //nolint:errcheck // checked by Flush
+	bw.Int64(3)
+	bw.EndArray()
+	err = bw.Flush()
+	if want := `[0,[1,2],{"a":2},3]`; err != nil || out.String() != want {
+		t.Errorf("got %s, %v\nwanted %s", out.String(), err, want)
+	}
+}
+
+func TestNestingDiscard(t *testing.T) {
+	bw := &BufWriter{}
+	bw.BeginArray()
+	bw.Int64(1)
+	bw.BeginTentative()
+	bw.EndArray()
+	bw.Discard()
+	bw.BeginTentative()
+	bw.BeginObject()
+	bw.Discard()
+	bw.Int64(2)
+	bw.EndArray()
+	if got := string(bw.TakeBuffer()); got != "[1,2]" || bw.Error != nil {
+		t.Errorf("got %s, %v", got, bw.Error)
+	}
+}
+
+func TestNestingUnfinished(t *testing.T) {
+	bw := &BufWriter{}
+	bw.BeginObject()
+	bw.FieldName("a")
+	err := bw.Flush()
+	if err == nil || err.Error() != "jsonappender: member name without a value" || bw.Error != nil {
+		t.Errorf("got %v, %v", err, bw.Error)
+	}
+	bw.BeginArray()
+	err = bw.Flush()
+	if err == nil || err.Error() != "jsonappender: 2 containers left open" {
+		t.Errorf("got %v", err)
+	}
+	bw.EndArray()
+	bw.EndObject()
+	if err = bw.Close(); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if got := string(bw.TakeBuffer()); got != `{"a":[]}` {
+		t.Errorf("got %s", got)
+	}
+}
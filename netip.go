@@ -0,0 +1,77 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonappender
+
+import "net/netip"
+
+// NetipAddr appends ip as a string value in the same form as ip.String(), or "" for the
+// zero Addr like encoding/json.
+func NetipAddr(ip netip.Addr, buf []byte) []byte {
+	start := len(buf)
+	buf = append(buf, '"')
+	buf = ip.AppendTo(buf)
+	// only a zone can need escaping
+	return quoteAppended(buf, start)
+}
+
+// NetipPrefix appends p as a string value in the same form as p.String(), or "" for the
+// zero Prefix like encoding/json.
+func NetipPrefix(p netip.Prefix, buf []byte) []byte {
+	start := len(buf)
+	buf = append(buf, '"')
+	buf = p.AppendTo(buf)
+	return quoteAppended(buf, start)
+}
+
+// NetipAddrPort appends p as a string value in the same form as p.String(), or "" when
+// its Addr is the zero Addr like encoding/json.
+func NetipAddrPort(p netip.AddrPort, buf []byte) []byte {
+	start := len(buf)
+	buf = append(buf, '"')
+	buf = p.AppendTo(buf)
+	return quoteAppended(buf, start)
+}
+
+// NetipAddr writes ip as a string value.
+func (bw *BufWriter) NetipAddr(ip netip.Addr) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = NetipAddr(ip, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// NetipPrefix writes p as a string value.
+func (bw *BufWriter) NetipPrefix(p netip.Prefix) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = NetipPrefix(p, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// NetipAddrPort writes p as a string value.
+func (bw *BufWriter) NetipAddrPort(p netip.AddrPort) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = NetipAddrPort(p, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// appendNetipValue appends val when it's one of the net/netip types.
+func appendNetipValue(val interface{}, buf []byte) ([]byte, bool) {
+	switch v := val.(type) {
+	case netip.Addr:
+		return NetipAddr(v, buf), true
+	case netip.Prefix:
+		return NetipPrefix(v, buf), true
+	case netip.AddrPort:
+		return NetipAddrPort(v, buf), true
+	}
+	return buf, false
+}
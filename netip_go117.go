@@ -0,0 +1,9 @@
+//go:build !go1.18
+// +build !go1.18
+
+package jsonappender
+
+// appendNetipValue never finds a net/netip value before Go 1.18.
+func appendNetipValue(_ interface{}, buf []byte) ([]byte, bool) {
+	return buf, false
+}
@@ -0,0 +1,64 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonappender
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+)
+
+func TestNetip(t *testing.T) {
+	addrs := []netip.Addr{
+		{},
+		netip.MustParseAddr("192.0.2.1"),
+		netip.MustParseAddr("2001:db8::1"),
+		netip.MustParseAddr("::ffff:10.0.0.1"),
+		netip.MustParseAddr("fe80::1%eth0"),
+		netip.MustParseAddr("fe80::1").WithZone(`a"<b>`),
+	}
+	for _, ip := range addrs {
+		got := NetipAddr(ip, []byte("x"))
+		if !matchesEncodingJSON(ip, []byte("x"), got, nil) {
+			t.Errorf("%v: got %s", ip, got)
+		}
+		p := netip.AddrPortFrom(ip, 8080)
+		got = NetipAddrPort(p, []byte("x"))
+		if !matchesEncodingJSON(p, []byte("x"), got, nil) {
+			t.Errorf("%v: got %s", p, got)
+		}
+		got, err := Value(p, []byte("x"))
+		if !matchesEncodingJSON(p, []byte("x"), got, err) {
+			t.Errorf("Value(%v): got %s", p, got)
+		}
+	}
+	prefixes := []netip.Prefix{
+		{},
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+		netip.PrefixFrom(netip.MustParseAddr("10.0.0.1"), 33),
+	}
+	for _, p := range prefixes {
+		got := NetipPrefix(p, []byte("x"))
+		if !matchesEncodingJSON(p, []byte("x"), got, nil) {
+			t.Errorf("%v: got %s", p, got)
+		}
+		got, err := Value(p, []byte("x"))
+		if !matchesEncodingJSON(p, []byte("x"), got, err) {
+			t.Errorf("Value(%v): got %s", p, got)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.NetipAddr(netip.MustParseAddr("127.0.0.1"))
+	bw.RawByte(',')
+	bw.NetipPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+	bw.RawByte(',')
+	bw.NetipAddrPort(netip.MustParseAddrPort("[::1]:53"))
+	bw.Flush()
+	if want := `"127.0.0.1","10.0.0.0/8","[::1]:53"`; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
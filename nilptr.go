@@ -0,0 +1,12 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import "reflect"
+
+// isNilPointer reports whether val holds a nil pointer.
+func isNilPointer(val interface{}) bool {
+	v := reflect.ValueOf(val)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,50 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"testing"
+)
+
+type nilSafeStringer struct{}
+
+func (s *nilSafeStringer) String() string {
+	if s == nil {
+		return "nil"
+	}
+	return "set"
+}
+
+type panickyStringer struct{}
+
+func (panickyStringer) String() string {
+	panic("boom")
+}
+
+func TestNilPointers(t *testing.T) {
+	if got := Stringer((*countingStringer)(nil), []byte("x")); string(got) != "xnull" {
+		t.Errorf("got %s", got)
+	}
+	if got := Stringer((*nilSafeStringer)(nil), nil); string(got) != `"nil"` {
+		t.Errorf("got %s", got)
+	}
+	if got := Err((*appenderError)(nil), []byte("x")); string(got) != "xnull" {
+		t.Errorf("got %s", got)
+	}
+
+	var bw BufWriter
+	bw.Stringer((*countingStringer)(nil))
+	bw.Err((*appenderError)(nil))
+	if got := string(bw.TakeBuffer()); got != "nullnull" || bw.Error != nil {
+		t.Errorf("got %s, %v", got, bw.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("got panic %v", r)
+		}
+	}()
+	Stringer(panickyStringer{}, nil)
+	t.Error("expected a panic")
+}
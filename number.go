@@ -0,0 +1,39 @@
+package jsonappender
+
+import "strconv"
+
+// InvalidNumberError is returned for a number literal that isn't valid json.
+type InvalidNumberError struct {
+	Number string
+}
+
+func (e *InvalidNumberError) Error() string {
+	return "jsonappender: invalid number literal " + strconv.Quote(e.Number)
+}
+
+// NumberString appends s, which has to be a json number literal, unchanged. An empty s
+// is written as 0 like encoding/json does for json.Number.
+func NumberString(s string, buf []byte) ([]byte, error) {
+	if s == "" {
+		return append(buf, '0'), nil
+	}
+	start := len(buf)
+	buf = append(buf, s...)
+	if numberLen(buf[start:]) != len(s) {
+		return buf[:start], &InvalidNumberError{Number: s}
+	}
+	return buf, nil
+}
+
+// NumberString writes s, which has to be a json number literal, unchanged.
+func (bw *BufWriter) NumberString(s string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = NumberString(s, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
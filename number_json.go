@@ -0,0 +1,26 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import "encoding/json"
+
+// Number appends n after checking that it's a valid json number.
+func Number(n json.Number, buf []byte) ([]byte, error) {
+	return NumberString(string(n), buf)
+}
+
+// Number writes n after checking that it's a valid json number.
+func (bw *BufWriter) Number(n json.Number) {
+	bw.NumberString(string(n))
+}
+
+// appendNumberValue appends val when it's a json.Number.
+func appendNumberValue(val interface{}, buf []byte) ([]byte, bool, error) {
+	n, ok := val.(json.Number)
+	if !ok {
+		return buf, false, nil
+	}
+	buf, err := Number(n, buf)
+	return buf, true, err
+}
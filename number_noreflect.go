@@ -0,0 +1,10 @@
+//go:build jsonappender_noreflect
+// +build jsonappender_noreflect
+
+package jsonappender
+
+// appendNumberValue never finds a json.Number because encoding/json isn't imported with
+// jsonappender_noreflect. Use NumberString instead.
+func appendNumberValue(_ interface{}, buf []byte) ([]byte, bool, error) {
+	return buf, false, nil
+}
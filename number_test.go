@@ -0,0 +1,45 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNumber(t *testing.T) {
+	for _, n := range []json.Number{"0", "-1", "1.5e-10", "12345678901234567890", "1E+2", ""} {
+		got, err := Number(n, []byte("x"))
+		if !matchesEncodingJSON(n, []byte("x"), got, err) {
+			t.Errorf("%q: got %s, %v", n, got, err)
+		}
+		got, err = Value(n, nil)
+		if !matchesEncodingJSON(n, nil, got, err) {
+			t.Errorf("%q: Value got %s, %v", n, got, err)
+		}
+	}
+	for _, n := range []json.Number{"01", "1.", "+1", "1e", "0x10", "1 ", "NaN"} {
+		got, err := Number(n, []byte("x"))
+		var ine *InvalidNumberError
+		if !errors.As(err, &ine) || ine.Number != string(n) || string(got) != "x" {
+			t.Errorf("%q: got %s, %v", n, got, err)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Number("42")
+	bw.RawByte(',')
+	bw.NumberString("-0.5")
+	bw.Flush()
+	if out.String() != "42,-0.5" {
+		t.Errorf("got %q", out.String())
+	}
+	bw.Number("x")
+	if bw.Error == nil {
+		t.Error("expected an error")
+	}
+}
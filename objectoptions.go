@@ -0,0 +1,89 @@
+package jsonappender
+
+// ObjectOptions changes how map[string]interface{} values are written. The zero value
+// writes them like encoding/json.
+type ObjectOptions struct {
+	// OmitNil leaves out members whose value is nil instead of writing them as null,
+	// for APIs where a missing member and a null one mean different things. Only an
+	// untyped nil is left out, not a nil pointer or slice in an interface.
+	OmitNil bool
+
+	// quoteLargeInts is set from SetQuoteLargeInts for the BufWriter's methods.
+	quoteLargeInts bool
+}
+
+// ObjectWithOptions is like Object but writes mp according to opts. Maps in mp's
+// values, directly or in []interface{} values, are written according to opts too.
+func ObjectWithOptions(mp map[string]interface{}, opts ObjectOptions, buf []byte) ([]byte, error) {
+	var comma bool
+	buf = append(buf, '{')
+	var err error
+	for k, v := range mp {
+		if v == nil && opts.OmitNil {
+			continue
+		}
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = FieldName(k, buf)
+		buf, err = ValueWithOptions(v, opts, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// ValueWithOptions is like Value but writes the maps in val according to opts like
+// ObjectWithOptions.
+func ValueWithOptions(val interface{}, opts ObjectOptions, buf []byte) ([]byte, error) {
+	if opts == (ObjectOptions{}) {
+		return Value(val, buf)
+	}
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return ObjectWithOptions(v, opts, buf)
+	case int64:
+		return appendInt64Quoted(v, opts.quoteLargeInts, buf), nil
+	case int:
+		return appendInt64Quoted(int64(v), opts.quoteLargeInts, buf), nil
+	case uint64:
+		return appendUint64Quoted(v, opts.quoteLargeInts, buf), nil
+	case uint:
+		return appendUint64Quoted(uint64(v), opts.quoteLargeInts, buf), nil
+	case uintptr:
+		return appendUint64Quoted(uint64(v), opts.quoteLargeInts, buf), nil
+	case map[string]int64:
+		kp := sortedKeysPool.Get().(*[]string)
+		buf, keys := appendInt64MapSorted(v, opts.quoteLargeInts, (*kp)[:0], buf)
+		putSortedKeys(kp, keys)
+		return buf, nil
+	case []interface{}:
+		buf = append(buf, '[')
+		var err error
+		for i := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf, err = ValueWithOptions(v[i], opts, buf)
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, ']'), nil
+	}
+	return Value(val, buf)
+}
+
+// SetObjectOptions sets the options Object, Array and Value write maps with.
+func (bw *BufWriter) SetObjectOptions(opts ObjectOptions) {
+	bw.objectOpts = opts
+}
+
+// valueOptions returns the ObjectOptions for the BufWriter's Value, Object and Array.
+func (bw *BufWriter) valueOptions() ObjectOptions {
+	opts := bw.objectOpts
+	opts.quoteLargeInts = bw.quoteLargeInts
+	return opts
+}
@@ -0,0 +1,37 @@
+package jsonappender
+
+import "testing"
+
+func TestObjectOmitNil(t *testing.T) {
+	mp := map[string]interface{}{
+		"a": nil,
+		"b": map[string]interface{}{
+			"c": nil,
+			"d": []interface{}{nil, map[string]interface{}{"e": nil, "f": []interface{}(nil)}},
+		},
+	}
+	opts := ObjectOptions{OmitNil: true}
+	want := `{"b":{"d":[null,{"f":[]}]}}`
+	got, err := ObjectWithOptions(mp, opts, nil)
+	if err != nil || string(got) != want {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = ObjectWithOptions(map[string]interface{}{"a": nil}, opts, nil)
+	if err != nil || string(got) != `{}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	bw := &BufWriter{}
+	bw.SetObjectOptions(opts)
+	bw.Value(mp)
+	bw.RawByte(' ')
+	bw.Object(mp)
+	bw.RawByte(' ')
+	bw.Array([]interface{}{mp})
+	bw.SetObjectOptions(ObjectOptions{})
+	bw.RawByte(' ')
+	bw.Object(map[string]interface{}{"a": nil})
+	if got := string(bw.TakeBuffer()); got != want+" "+want+" ["+want+`] {"a":null}` {
+		t.Errorf("got %s", got)
+	}
+}
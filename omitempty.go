@@ -0,0 +1,61 @@
+package jsonappender
+
+// The FieldOmitEmpty methods write an object member like encoding/json's omitempty
+// option: nothing at all, not even the name, when the value is empty. Otherwise they
+// write it like the matching Field method.
+
+// StringFieldOmitEmpty writes the member name with a string value unless val is "".
+func (bw *BufWriter) StringFieldOmitEmpty(name, val string) {
+	if bw.Error != nil || val == "" {
+		return
+	}
+	bw.StringField(name, val)
+}
+
+// Int64FieldOmitEmpty writes the member name with an int64 value unless val is 0.
+func (bw *BufWriter) Int64FieldOmitEmpty(name string, val int64) {
+	if bw.Error != nil || val == 0 {
+		return
+	}
+	bw.Int64Field(name, val)
+}
+
+// Uint64FieldOmitEmpty writes the member name with a uint64 value unless val is 0.
+func (bw *BufWriter) Uint64FieldOmitEmpty(name string, val uint64) {
+	if bw.Error != nil || val == 0 {
+		return
+	}
+	bw.Uint64Field(name, val)
+}
+
+// Float64FieldOmitEmpty writes the member name with a float64 value unless val is 0.
+func (bw *BufWriter) Float64FieldOmitEmpty(name string, val float64) {
+	if bw.Error != nil || val == 0 {
+		return
+	}
+	bw.Float64Field(name, val)
+}
+
+// BoolFieldOmitEmpty writes the member name with a bool value unless val is false.
+func (bw *BufWriter) BoolFieldOmitEmpty(name string, val bool) {
+	if bw.Error != nil || !val {
+		return
+	}
+	bw.BoolField(name, val)
+}
+
+// ObjectFieldOmitEmpty writes the member name with an object value unless mp is empty.
+func (bw *BufWriter) ObjectFieldOmitEmpty(name string, mp map[string]interface{}) {
+	if bw.Error != nil || len(mp) == 0 {
+		return
+	}
+	bw.ObjectField(name, mp)
+}
+
+// ArrayFieldOmitEmpty writes the member name with an array value unless slice is empty.
+func (bw *BufWriter) ArrayFieldOmitEmpty(name string, slice []interface{}) {
+	if bw.Error != nil || len(slice) == 0 {
+		return
+	}
+	bw.ArrayField(name, slice)
+}
@@ -0,0 +1,49 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFieldOmitEmpty(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	write := func(s string, i int64, u uint64, f float64, b bool, mp map[string]interface{}, slice []interface{}) {
+		bw.RawByte('{')
+		bw.StringFieldOmitEmpty("s", s)
+		bw.Int64FieldOmitEmpty("i", i)
+		bw.Uint64FieldOmitEmpty("u", u)
+		bw.Float64FieldOmitEmpty("f", f)
+		bw.BoolFieldOmitEmpty("b", b)
+		bw.ObjectFieldOmitEmpty("o", mp)
+		bw.ArrayFieldOmitEmpty("a", slice)
+		bw.RawByte('}')
+	}
+	write("", 0, 0, 0, false, nil, nil)
+	bw.RawByte('\n')
+	write("x", -1, 2, 0.5, true, map[string]interface{}{"k": "v"}, []interface{}{1.0})
+	bw.RawByte('\n')
+	write("", 0, 3, 0, false, map[string]interface{}{}, []interface{}{})
+	bw.Flush()
+	want := `{}
+{"s":"x","i":-1,"u":2,"f":0.5,"b":true,"o":{"k":"v"},"a":[1]}
+{"u":3}`
+	if out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+
+	// members written by hand, across a flush and a discarded segment
+	out.Reset()
+	bw.ResetWithBuffer(&out, make([]byte, 0, 1))
+	bw.RawString(`{ "id": 1 `)
+	bw.BeginTentative()
+	bw.StringFieldOmitEmpty("dropped", "x")
+	bw.Discard()
+	bw.Flush()
+	bw.Int64FieldOmitEmpty("n", 2)
+	bw.RawByte('}')
+	bw.Flush()
+	if want := `{ "id": 1 ,"n":2}`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+}
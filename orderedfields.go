@@ -0,0 +1,41 @@
+package jsonappender
+
+// Field is a member of a Fields object. Value can be anything Value accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Fields is an object whose members are written in order, for when the order matters
+// and a map[string]interface{} would shuffle it. Names aren't checked for duplicates.
+type Fields []Field
+
+// AppendJSON appends the object.
+func (f Fields) AppendJSON(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	var err error
+	for i := range f {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(f[i].Name, buf)
+		buf, err = Value(f[i].Value, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// Fields writes an object with the members of f in order.
+func (bw *BufWriter) Fields(f Fields) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = f.AppendJSON(bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,28 @@
+package jsonappender
+
+import "testing"
+
+func TestFieldsType(t *testing.T) {
+	f := Fields{
+		{Name: "z", Value: 1},
+		{Name: "a", Value: Fields{{Name: "b", Value: nil}}},
+		{Name: "m", Value: []interface{}{"x"}},
+	}
+	want := `{"z":1,"a":{"b":null},"m":["x"]}`
+	got, err := f.AppendJSON(nil)
+	if err != nil || string(got) != want {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = Value(Fields(nil), nil)
+	if err != nil || string(got) != `{}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	bw := &BufWriter{}
+	bw.Fields(f)
+	if got := string(bw.TakeBuffer()); got != want {
+		t.Errorf("got %s", got)
+	}
+	if _, err := (Fields{{Name: "c", Value: make(chan int)}}).AppendJSON(nil); err == nil {
+		t.Error("expected an error")
+	}
+}
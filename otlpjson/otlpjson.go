@@ -0,0 +1,389 @@
+// Package otlpjson encodes trace payloads in the OTLP/JSON format accepted by
+// OpenTelemetry collectors at /v1/traces.
+//
+// It follows the protobuf json mapping the way OTLP requires: trace and span ids are
+// hex strings, 64 bit integers are strings, enums are numbers and fields with their
+// zero value are omitted.
+package otlpjson
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// TraceID is a 16 byte trace id.
+type TraceID [16]byte
+
+// SpanID is an 8 byte span id.
+type SpanID [8]byte
+
+// SpanKind is the kind of a span.
+type SpanKind int
+
+// Span kinds as defined by OTLP.
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode is the status of a span.
+type StatusCode int
+
+// Status codes as defined by OTLP.
+const (
+	StatusCodeUnset StatusCode = iota
+	StatusCodeOK
+	StatusCodeError
+)
+
+// Traces is the body of an export request. It implements jsonappender.JSONAppender.
+type Traces []ResourceSpans
+
+// ResourceSpans holds the spans from one resource.
+type ResourceSpans struct {
+	Resource   []KeyValue
+	ScopeSpans []ScopeSpans
+}
+
+// ScopeSpans holds the spans from one instrumentation scope.
+type ScopeSpans struct {
+	Scope Scope
+	Spans []Span
+}
+
+// Scope identifies an instrumentation scope.
+type Scope struct {
+	Name    string
+	Version string
+}
+
+// Span is one span.
+type Span struct {
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	TraceState   string
+	Name         string
+	Kind         SpanKind
+	Start        time.Time
+	End          time.Time
+	Attributes   []KeyValue
+	Events       []Event
+	Status       Status
+}
+
+// Event is a timestamped annotation on a span.
+type Event struct {
+	Time       time.Time
+	Name       string
+	Attributes []KeyValue
+}
+
+// Status is the status of a span.
+type Status struct {
+	Code    StatusCode
+	Message string
+}
+
+// KeyValue is an attribute.
+type KeyValue struct {
+	Key   string
+	Value AnyValue
+}
+
+type valueKind uint8
+
+const (
+	kindEmpty valueKind = iota
+	kindString
+	kindBool
+	kindInt
+	kindDouble
+	kindBytes
+	kindArray
+	kindKVList
+)
+
+// AnyValue is an attribute value. The zero value is an empty value.
+type AnyValue struct {
+	kind   valueKind
+	str    string
+	num    int64
+	double float64
+	bytes  []byte
+	array  []AnyValue
+	kvlist []KeyValue
+}
+
+// StringValue returns a string AnyValue.
+func StringValue(s string) AnyValue {
+	return AnyValue{kind: kindString, str: s}
+}
+
+// BoolValue returns a bool AnyValue.
+func BoolValue(b bool) AnyValue {
+	v := AnyValue{kind: kindBool}
+	if b {
+		v.num = 1
+	}
+	return v
+}
+
+// IntValue returns an integer AnyValue.
+func IntValue(i int64) AnyValue {
+	return AnyValue{kind: kindInt, num: i}
+}
+
+// DoubleValue returns a floating point AnyValue.
+func DoubleValue(f float64) AnyValue {
+	return AnyValue{kind: kindDouble, double: f}
+}
+
+// BytesValue returns a bytes AnyValue.
+func BytesValue(b []byte) AnyValue {
+	return AnyValue{kind: kindBytes, bytes: b}
+}
+
+// ArrayValue returns an array AnyValue.
+func ArrayValue(values ...AnyValue) AnyValue {
+	return AnyValue{kind: kindArray, array: values}
+}
+
+// KVListValue returns a key-value list AnyValue.
+func KVListValue(kvs ...KeyValue) AnyValue {
+	return AnyValue{kind: kindKVList, kvlist: kvs}
+}
+
+// String is a shortcut for a KeyValue with a StringValue.
+func String(key, value string) KeyValue {
+	return KeyValue{Key: key, Value: StringValue(value)}
+}
+
+// Int is a shortcut for a KeyValue with an IntValue.
+func Int(key string, value int64) KeyValue {
+	return KeyValue{Key: key, Value: IntValue(value)}
+}
+
+// AppendJSON implements jsonappender.JSONAppender.
+func (t Traces) AppendJSON(buf []byte) ([]byte, error) {
+	return AppendTraces(t, buf), nil
+}
+
+// AppendTraces appends an export request body holding rs.
+func AppendTraces(rs []ResourceSpans, buf []byte) []byte {
+	buf = append(buf, `{"resourceSpans":[`...)
+	for i := range rs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendResourceSpans(&rs[i], buf)
+	}
+	return append(buf, ']', '}')
+}
+
+func appendResourceSpans(rs *ResourceSpans, buf []byte) []byte {
+	buf = append(buf, `{"resource":{`...)
+	if len(rs.Resource) > 0 {
+		buf = appendAttributes(rs.Resource, buf)
+	}
+	buf = append(buf, `},"scopeSpans":[`...)
+	for i := range rs.ScopeSpans {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		ss := &rs.ScopeSpans[i]
+		buf = append(buf, `{"scope":{`...)
+		comma := false
+		if ss.Scope.Name != "" {
+			buf = append(buf, `"name":`...)
+			buf = jsonappender.String(ss.Scope.Name, buf)
+			comma = true
+		}
+		if ss.Scope.Version != "" {
+			if comma {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `"version":`...)
+			buf = jsonappender.String(ss.Scope.Version, buf)
+		}
+		buf = append(buf, `},"spans":[`...)
+		for j := range ss.Spans {
+			if j > 0 {
+				buf = append(buf, ',')
+			}
+			buf = AppendSpan(&ss.Spans[j], buf)
+		}
+		buf = append(buf, ']', '}')
+	}
+	return append(buf, ']', '}')
+}
+
+// AppendSpan appends one span object.
+func AppendSpan(s *Span, buf []byte) []byte {
+	buf = append(buf, `{"traceId":`...)
+	buf = jsonappender.BytesHex(s.TraceID[:], buf)
+	buf = append(buf, `,"spanId":`...)
+	buf = jsonappender.BytesHex(s.SpanID[:], buf)
+	if s.ParentSpanID != (SpanID{}) {
+		buf = append(buf, `,"parentSpanId":`...)
+		buf = jsonappender.BytesHex(s.ParentSpanID[:], buf)
+	}
+	if s.TraceState != "" {
+		buf = append(buf, `,"traceState":`...)
+		buf = jsonappender.String(s.TraceState, buf)
+	}
+	buf = append(buf, `,"name":`...)
+	buf = jsonappender.String(s.Name, buf)
+	if s.Kind != SpanKindUnspecified {
+		buf = append(buf, `,"kind":`...)
+		buf = jsonappender.Int64(int64(s.Kind), buf)
+	}
+	buf = appendUnixNano(",\"startTimeUnixNano\":", s.Start, buf)
+	buf = appendUnixNano(",\"endTimeUnixNano\":", s.End, buf)
+	if len(s.Attributes) > 0 {
+		buf = append(buf, ',')
+		buf = appendAttributes(s.Attributes, buf)
+	}
+	if len(s.Events) > 0 {
+		buf = append(buf, `,"events":[`...)
+		for i := range s.Events {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			e := &s.Events[i]
+			buf = append(buf, '{')
+			buf = appendUnixNano(`"timeUnixNano":`, e.Time, buf)
+			if !e.Time.IsZero() {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `"name":`...)
+			buf = jsonappender.String(e.Name, buf)
+			if len(e.Attributes) > 0 {
+				buf = append(buf, ',')
+				buf = appendAttributes(e.Attributes, buf)
+			}
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, `,"status":{`...)
+	comma := false
+	if s.Status.Message != "" {
+		buf = append(buf, `"message":`...)
+		buf = jsonappender.String(s.Status.Message, buf)
+		comma = true
+	}
+	if s.Status.Code != StatusCodeUnset {
+		if comma {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `"code":`...)
+		buf = jsonappender.Int64(int64(s.Status.Code), buf)
+	}
+	return append(buf, '}', '}')
+}
+
+// appendUnixNano appends name and t as a quoted integer, or nothing when t is zero.
+func appendUnixNano(name string, t time.Time, buf []byte) []byte {
+	if t.IsZero() {
+		return buf
+	}
+	buf = append(buf, name...)
+	buf = append(buf, '"')
+	buf = jsonappender.Uint64(uint64(t.UnixNano()), buf)
+	return append(buf, '"')
+}
+
+func appendAttributes(kvs []KeyValue, buf []byte) []byte {
+	buf = append(buf, `"attributes":`...)
+	return appendKeyValues(kvs, buf)
+}
+
+func appendKeyValues(kvs []KeyValue, buf []byte) []byte {
+	buf = append(buf, '[')
+	for i := range kvs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"key":`...)
+		buf = jsonappender.String(kvs[i].Key, buf)
+		buf = append(buf, `,"value":`...)
+		buf = AppendAnyValue(kvs[i].Value, buf)
+		buf = append(buf, '}')
+	}
+	return append(buf, ']')
+}
+
+// AppendAnyValue appends v as an AnyValue object.
+func AppendAnyValue(v AnyValue, buf []byte) []byte {
+	switch v.kind {
+	case kindString:
+		buf = append(buf, `{"stringValue":`...)
+		buf = jsonappender.String(v.str, buf)
+	case kindBool:
+		buf = append(buf, `{"boolValue":`...)
+		buf = jsonappender.Bool(v.num != 0, buf)
+	case kindInt:
+		buf = append(buf, `{"intValue":"`...)
+		buf = jsonappender.Int64(v.num, buf)
+		buf = append(buf, '"')
+	case kindDouble:
+		buf = append(buf, `{"doubleValue":`...)
+		buf = jsonappender.Float64Named(v.double, protobufNames, buf)
+	case kindBytes:
+		buf = append(buf, `{"bytesValue":"`...)
+		n := len(buf)
+		enc := base64.StdEncoding
+		buf = append(buf, make([]byte, enc.EncodedLen(len(v.bytes)))...)
+		enc.Encode(buf[n:], v.bytes)
+		buf = append(buf, '"')
+	case kindArray:
+		buf = append(buf, `{"arrayValue":{"values":[`...)
+		for i := range v.array {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = AppendAnyValue(v.array[i], buf)
+		}
+		buf = append(buf, ']', '}')
+	case kindKVList:
+		buf = append(buf, `{"kvlistValue":{"values":`...)
+		buf = appendKeyValues(v.kvlist, buf)
+		buf = append(buf, '}')
+	default:
+		buf = append(buf, '{')
+	}
+	return append(buf, '}')
+}
+
+// protobufNames are the protobuf json spellings for non-finite values.
+var protobufNames = jsonappender.NonFiniteNames{NaN: "NaN", PosInf: "Infinity", NegInf: "-Infinity"}
+
+// ParseTraceID parses a 32 character hex trace id.
+func ParseTraceID(s string) (TraceID, error) {
+	var id TraceID
+	err := decodeHex(id[:], s)
+	return id, err
+}
+
+// ParseSpanID parses a 16 character hex span id.
+func ParseSpanID(s string) (SpanID, error) {
+	var id SpanID
+	err := decodeHex(id[:], s)
+	return id, err
+}
+
+func decodeHex(dst []byte, s string) error {
+	if len(s) != hex.EncodedLen(len(dst)) {
+		return hex.ErrLength
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
@@ -0,0 +1,93 @@
+package otlpjson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestTraces(t *testing.T) {
+	traceID, err := ParseTraceID("5b8efff798038103d269b633813fc60c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := ParseSpanID("eee19b7ec3c1b174")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1544712660, 0)
+	traces := Traces{{
+		Resource: []KeyValue{String("service.name", "svc")},
+		ScopeSpans: []ScopeSpans{{
+			Scope: Scope{Name: "lib", Version: "1.0"},
+			Spans: []Span{
+				{
+					TraceID: traceID,
+					SpanID:  spanID,
+					Name:    "GET /",
+					Kind:    SpanKindServer,
+					Start:   start,
+					End:     start.Add(time.Second),
+					Attributes: []KeyValue{
+						Int("http.status_code", 500),
+						{Key: "ok", Value: BoolValue(false)},
+						{Key: "ratio", Value: DoubleValue(0.5)},
+						{Key: "nan", Value: DoubleValue(math.NaN())},
+						{Key: "raw", Value: BytesValue([]byte("hi"))},
+						{Key: "list", Value: ArrayValue(StringValue("a"), IntValue(1))},
+						{Key: "map", Value: KVListValue(String("k", "v"))},
+						{Key: "empty"},
+					},
+					Events: []Event{{Time: start, Name: "retry"}, {Name: "untimed"}},
+					Status: Status{Code: StatusCodeError, Message: "boom"},
+				},
+				{
+					TraceID:      traceID,
+					SpanID:       SpanID{1},
+					ParentSpanID: spanID,
+					Name:         "child",
+				},
+			},
+		}},
+	}}
+	got, err := jsonappender.Value(traces, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"svc"}}]},` +
+		`"scopeSpans":[{"scope":{"name":"lib","version":"1.0"},"spans":[` +
+		`{"traceId":"5b8efff798038103d269b633813fc60c","spanId":"eee19b7ec3c1b174","name":"GET /","kind":2,` +
+		`"startTimeUnixNano":"1544712660000000000","endTimeUnixNano":"1544712661000000000","attributes":[` +
+		`{"key":"http.status_code","value":{"intValue":"500"}},` +
+		`{"key":"ok","value":{"boolValue":false}},` +
+		`{"key":"ratio","value":{"doubleValue":0.5}},` +
+		`{"key":"nan","value":{"doubleValue":"NaN"}},` +
+		`{"key":"raw","value":{"bytesValue":"aGk="}},` +
+		`{"key":"list","value":{"arrayValue":{"values":[{"stringValue":"a"},{"intValue":"1"}]}}},` +
+		`{"key":"map","value":{"kvlistValue":{"values":[{"key":"k","value":{"stringValue":"v"}}]}}},` +
+		`{"key":"empty","value":{}}],` +
+		`"events":[{"timeUnixNano":"1544712660000000000","name":"retry"},{"name":"untimed"}],` +
+		`"status":{"message":"boom","code":2}},` +
+		`{"traceId":"5b8efff798038103d269b633813fc60c","spanId":"0100000000000000","parentSpanId":"eee19b7ec3c1b174","name":"child","status":{}}` +
+		`]}]}]}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !json.Valid(got) {
+		t.Error("invalid json")
+	}
+}
+
+func TestParseTraceID(t *testing.T) {
+	for _, s := range []string{"", "5b8e", "5b8efff798038103d269b633813fc60g"} {
+		if _, err := ParseTraceID(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+	if _, err := ParseSpanID("eee19b7ec3c1b17"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,70 @@
+package jsonappender
+
+import "unicode/utf8"
+
+// Position is a location in a BufWriter's output. Line and Column start at 1. Column
+// counts runes, so multi-byte UTF-8 characters count once.
+type Position struct {
+	Offset int64
+	Line   int
+	Column int
+}
+
+// SetTrackPosition turns tracking of the output position on or off. Positions count
+// from where tracking was turned on, not from the beginning of the output: the next
+// byte written is at Offset 0, line 1, column 1. Turning it on again while it's on keeps
+// the current position, and Reset starts over at the beginning. It costs a scan of
+// everything written, so it's off by default.
+func (bw *BufWriter) SetTrackPosition(track bool) {
+	switch {
+	case !track:
+		bw.pos = nil
+	case bw.pos == nil:
+		bw.pos = &Position{
+			Line:   1,
+			Column: 1,
+		}
+	}
+}
+
+// Position returns the position the next byte will be written at. It is the zero
+// Position unless SetTrackPosition is on.
+func (bw *BufWriter) Position() Position {
+	if bw.pos == nil {
+		return Position{}
+	}
+	return *bw.pos
+}
+
+func (p *Position) advance(b []byte) {
+	p.Offset += int64(len(b))
+	for _, c := range b {
+		p.advanceByte(c)
+	}
+}
+
+func (p *Position) advanceString(s string) {
+	p.Offset += int64(len(s))
+	for i := 0; i < len(s); i++ {
+		p.advanceByte(s[i])
+	}
+}
+
+func (p *Position) advanceByte(c byte) {
+	switch {
+	case c == '\n':
+		p.Line++
+		p.Column = 1
+	case !utf8.RuneStart(c):
+		// continuation bytes don't start a new column
+	default:
+		p.Column++
+	}
+}
+
+func (p *Position) reset() {
+	*p = Position{
+		Line:   1,
+		Column: 1,
+	}
+}
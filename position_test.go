@@ -0,0 +1,47 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBufWriter_Position(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriterSize(&out, 4)
+	if bw.Position() != (Position{}) {
+		t.Errorf("expected the zero Position, got %+v", bw.Position())
+	}
+	bw.SetTrackPosition(true)
+	bw.RawString("{\n  ")
+	bw.FieldName("é")
+	bw.RawByte(' ')
+	want := Position{Offset: 10, Line: 2, Column: 8}
+	if got := bw.Position(); got != want {
+		t.Errorf("got %+v, wanted %+v", got, want)
+	}
+	bw.Raw([]byte("1\n}\n"))
+	want = Position{Offset: 14, Line: 4, Column: 1}
+	if got := bw.Position(); got != want {
+		t.Errorf("got %+v, wanted %+v", got, want)
+	}
+
+	bw.ResetWithBuffer(nil, []byte("[\n"))
+	bw.Int64(12)
+	want = Position{Offset: 4, Line: 2, Column: 3}
+	if got := bw.Position(); got != want {
+		t.Errorf("got %+v, wanted %+v", got, want)
+	}
+
+	bw.SetTrackPosition(false)
+	bw.RawByte('\n')
+	if bw.Position() != (Position{}) {
+		t.Errorf("expected the zero Position, got %+v", bw.Position())
+	}
+
+	bw.SetTrackPosition(true)
+	bw.RawString("ab")
+	want = Position{Offset: 2, Line: 1, Column: 3}
+	if got := bw.Position(); got != want {
+		t.Errorf("positions should count from turning tracking on, got %+v, wanted %+v", got, want)
+	}
+}
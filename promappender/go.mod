@@ -0,0 +1,11 @@
+module github.com/killa-beez/jsonappender/promappender
+
+go 1.15
+
+require (
+	github.com/killa-beez/jsonappender v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.11.1
+	github.com/prometheus/client_model v0.2.0
+)
+
+replace github.com/killa-beez/jsonappender => ../
@@ -0,0 +1,146 @@
+// Package promappender writes Prometheus metrics as json. It is its own module so that
+// jsonappender doesn't depend on the Prometheus client.
+package promappender
+
+import (
+	"github.com/killa-beez/jsonappender"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Gatherer returns a JSONAppender that gathers from g and writes the result like
+// AppendMetricFamilies. Gathering happens each time the appender is used.
+func Gatherer(g prometheus.Gatherer) jsonappender.JSONAppender {
+	return jsonappender.AppendFunc(func(buf []byte) ([]byte, error) {
+		mfs, err := g.Gather()
+		if err != nil {
+			return buf, err
+		}
+		return AppendMetricFamilies(mfs, buf), nil
+	})
+}
+
+// AppendMetricFamilies appends mfs as an array with one object per family:
+//
+//	{"name":"...","help":"...","type":"counter","metrics":[...]}
+//
+// Each metric has "labels" as an object plus "value" for counters, gauges and untyped
+// metrics, "count", "sum" and "buckets" for histograms, and "count", "sum" and
+// "quantiles" for summaries. "timestamp_ms" is included when the metric has one. NaN
+// and infinite values are written as the strings "NaN", "+Inf" and "-Inf" because json
+// numbers can't represent them.
+func AppendMetricFamilies(mfs []*dto.MetricFamily, buf []byte) []byte {
+	buf = append(buf, '[')
+	for i, mf := range mfs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = AppendMetricFamily(mf, buf)
+	}
+	return append(buf, ']')
+}
+
+// AppendMetricFamily appends one family the way AppendMetricFamilies does.
+func AppendMetricFamily(mf *dto.MetricFamily, buf []byte) []byte {
+	buf = append(buf, `{"name":`...)
+	buf = jsonappender.String(mf.GetName(), buf)
+	if mf.Help != nil {
+		buf = append(buf, `,"help":`...)
+		buf = jsonappender.String(mf.GetHelp(), buf)
+	}
+	buf = append(buf, `,"type":`...)
+	buf = jsonappender.String(typeName(mf.GetType()), buf)
+	buf = append(buf, `,"metrics":[`...)
+	for i, m := range mf.Metric {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendMetric(m, buf)
+	}
+	return append(buf, ']', '}')
+}
+
+func typeName(t dto.MetricType) string {
+	switch t {
+	case dto.MetricType_COUNTER:
+		return "counter"
+	case dto.MetricType_GAUGE:
+		return "gauge"
+	case dto.MetricType_SUMMARY:
+		return "summary"
+	case dto.MetricType_HISTOGRAM:
+		return "histogram"
+	}
+	return "untyped"
+}
+
+func appendMetric(m *dto.Metric, buf []byte) []byte {
+	buf = append(buf, `{"labels":{`...)
+	for i, lp := range m.Label {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = jsonappender.FieldName(lp.GetName(), buf)
+		buf = jsonappender.String(lp.GetValue(), buf)
+	}
+	buf = append(buf, '}')
+	switch {
+	case m.Counter != nil:
+		buf = append(buf, `,"value":`...)
+		buf = appendFloat(m.Counter.GetValue(), buf)
+	case m.Gauge != nil:
+		buf = append(buf, `,"value":`...)
+		buf = appendFloat(m.Gauge.GetValue(), buf)
+	case m.Untyped != nil:
+		buf = append(buf, `,"value":`...)
+		buf = appendFloat(m.Untyped.GetValue(), buf)
+	case m.Histogram != nil:
+		h := m.Histogram
+		buf = append(buf, `,"count":`...)
+		buf = jsonappender.Uint64(h.GetSampleCount(), buf)
+		buf = append(buf, `,"sum":`...)
+		buf = appendFloat(h.GetSampleSum(), buf)
+		buf = append(buf, `,"buckets":[`...)
+		for i, b := range h.Bucket {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `{"le":`...)
+			buf = appendFloat(b.GetUpperBound(), buf)
+			buf = append(buf, `,"count":`...)
+			buf = jsonappender.Uint64(b.GetCumulativeCount(), buf)
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	case m.Summary != nil:
+		s := m.Summary
+		buf = append(buf, `,"count":`...)
+		buf = jsonappender.Uint64(s.GetSampleCount(), buf)
+		buf = append(buf, `,"sum":`...)
+		buf = appendFloat(s.GetSampleSum(), buf)
+		buf = append(buf, `,"quantiles":[`...)
+		for i, q := range s.Quantile {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `{"quantile":`...)
+			buf = appendFloat(q.GetQuantile(), buf)
+			buf = append(buf, `,"value":`...)
+			buf = appendFloat(q.GetValue(), buf)
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	}
+	if m.TimestampMs != nil {
+		buf = append(buf, `,"timestamp_ms":`...)
+		buf = jsonappender.Int64(m.GetTimestampMs(), buf)
+	}
+	return append(buf, '}')
+}
+
+// promNames are the spellings Prometheus uses for NaN and infinities.
+var promNames = jsonappender.NonFiniteNames{NaN: "NaN", PosInf: "+Inf", NegInf: "-Inf"}
+
+func appendFloat(f float64, buf []byte) []byte {
+	return jsonappender.Float64Named(f, promNames, buf)
+}
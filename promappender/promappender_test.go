@@ -0,0 +1,73 @@
+package promappender
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestGatherer(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "requests_total",
+		Help: "Requests <served>.",
+	}, []string{"code"})
+	requests.WithLabelValues("200").Add(3)
+	requests.WithLabelValues("500").Inc()
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "latency_seconds",
+		Help:    "Latency.",
+		Buckets: []float64{0.1, 1},
+	})
+	latency.Observe(0.5)
+	size := prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "size_bytes",
+		Help:       "Size.",
+		Objectives: map[float64]float64{0.5: 0.05},
+	})
+	size.Observe(10)
+	reg.MustRegister(requests, latency, size)
+
+	got, err := jsonappender.Value(Gatherer(reg), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[` +
+		`{"name":"latency_seconds","help":"Latency.","type":"histogram","metrics":[{"labels":{},"count":1,"sum":0.5,"buckets":[{"le":0.1,"count":0},{"le":1,"count":1}]}]},` +
+		`{"name":"requests_total","help":"Requests \u003cserved\u003e.","type":"counter","metrics":[{"labels":{"code":"200"},"value":3},{"labels":{"code":"500"},"value":1}]},` +
+		`{"name":"size_bytes","help":"Size.","type":"summary","metrics":[{"labels":{},"count":1,"sum":10,"quantiles":[{"quantile":0.5,"value":10}]}]}` +
+		`]`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestAppendMetricFamily(t *testing.T) {
+	name, value, ts := "up", "a", int64(1600000000000)
+	gaugeType := dto.MetricType_GAUGE
+	nan, inf := math.NaN(), math.Inf(1)
+	mf := &dto.MetricFamily{
+		Name: &name,
+		Type: &gaugeType,
+		Metric: []*dto.Metric{
+			{
+				Label:       []*dto.LabelPair{{Name: &name, Value: &value}},
+				Gauge:       &dto.Gauge{Value: &nan},
+				TimestampMs: &ts,
+			},
+			{Untyped: &dto.Untyped{Value: &inf}},
+		},
+	}
+	got := AppendMetricFamily(mf, nil)
+	want := `{"name":"up","type":"gauge","metrics":[{"labels":{"up":"a"},"value":"NaN","timestamp_ms":1600000000000},{"labels":{},"value":"+Inf"}]}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !json.Valid(got) {
+		t.Error("invalid json")
+	}
+}
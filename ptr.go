@@ -0,0 +1,105 @@
+package jsonappender
+
+import "time"
+
+// Int64Ptr appends *p, or null for a nil p.
+func Int64Ptr(p *int64, buf []byte) []byte {
+	if p == nil {
+		return Null(buf)
+	}
+	return Int64(*p, buf)
+}
+
+// StringPtr appends *p, or null for a nil p.
+func StringPtr(p *string, buf []byte) []byte {
+	if p == nil {
+		return Null(buf)
+	}
+	return String(*p, buf)
+}
+
+// StringOrNull appends *p, or null for a nil p or an empty *p.
+func StringOrNull(p *string, buf []byte) []byte {
+	if p == nil || *p == "" {
+		return Null(buf)
+	}
+	return String(*p, buf)
+}
+
+// BoolPtr appends *p, or null for a nil p.
+func BoolPtr(p *bool, buf []byte) []byte {
+	if p == nil {
+		return Null(buf)
+	}
+	return Bool(*p, buf)
+}
+
+// Float64Ptr appends *p, or null for a nil p. It fails like Float64.
+func Float64Ptr(p *float64, buf []byte) ([]byte, error) {
+	if p == nil {
+		return Null(buf), nil
+	}
+	return Float64(*p, buf)
+}
+
+// TimePtr appends *p, or null for a nil p. It fails like Time.
+func TimePtr(p *time.Time, buf []byte) ([]byte, error) {
+	if p == nil {
+		return Null(buf), nil
+	}
+	return Time(*p, buf)
+}
+
+// Int64Ptr writes *p, or null for a nil p.
+func (bw *BufWriter) Int64Ptr(p *int64) {
+	if p == nil {
+		bw.Null()
+		return
+	}
+	bw.Int64(*p)
+}
+
+// StringPtr writes *p, or null for a nil p.
+func (bw *BufWriter) StringPtr(p *string) {
+	if p == nil {
+		bw.Null()
+		return
+	}
+	bw.String(*p)
+}
+
+// StringOrNull writes *p, or null for a nil p or an empty *p.
+func (bw *BufWriter) StringOrNull(p *string) {
+	if p == nil || *p == "" {
+		bw.Null()
+		return
+	}
+	bw.String(*p)
+}
+
+// BoolPtr writes *p, or null for a nil p.
+func (bw *BufWriter) BoolPtr(p *bool) {
+	if p == nil {
+		bw.Null()
+		return
+	}
+	bw.Bool(*p)
+}
+
+// Float64Ptr writes *p formatted with the BufWriter's FloatOptions, or null for a nil p.
+func (bw *BufWriter) Float64Ptr(p *float64) {
+	if p == nil {
+		bw.Null()
+		return
+	}
+	bw.Float64(*p)
+}
+
+// TimePtr writes *p formatted with the BufWriter's TimeOptions, or null for a nil p.
+func (bw *BufWriter) TimePtr(p *time.Time) {
+	if p == nil {
+		bw.Null()
+		return
+	}
+	bw.Time(*p)
+}
@@ -0,0 +1,80 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPtrs(t *testing.T) {
+	i := int64(-3)
+	s := "s"
+	b := true
+	f := 1.5
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := Int64Ptr(&i, []byte("x")); !matchesEncodingJSON(&i, []byte("x"), got, nil) {
+		t.Errorf("got %s", got)
+	}
+	if got := StringPtr(&s, []byte("x")); !matchesEncodingJSON(&s, []byte("x"), got, nil) {
+		t.Errorf("got %s", got)
+	}
+	if got := BoolPtr(&b, []byte("x")); !matchesEncodingJSON(&b, []byte("x"), got, nil) {
+		t.Errorf("got %s", got)
+	}
+	if got, err := Float64Ptr(&f, []byte("x")); !matchesEncodingJSON(&f, []byte("x"), got, err) {
+		t.Errorf("got %s", got)
+	}
+	if got, err := TimePtr(&ts, []byte("x")); !matchesEncodingJSON(&ts, []byte("x"), got, err) {
+		t.Errorf("got %s", got)
+	}
+	nan := math.NaN()
+	if _, err := Float64Ptr(&nan, nil); err == nil {
+		t.Error("expected an error for NaN")
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Int64Ptr(nil)
+	bw.StringPtr(nil)
+	bw.BoolPtr(nil)
+	bw.Float64Ptr(nil)
+	bw.TimePtr(nil)
+	bw.RawByte(',')
+	bw.Int64Ptr(&i)
+	bw.StringPtr(&s)
+	bw.BoolPtr(&b)
+	bw.Float64Ptr(&f)
+	bw.TimePtr(&ts)
+	bw.Flush()
+	want := `nullnullnullnullnull,-3"s"true1.5"2021-03-04T05:06:07Z"`
+	if out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
+
+func TestStringOrNull(t *testing.T) {
+	empty, s := "", "s"
+	for _, td := range []struct {
+		p    *string
+		want string
+	}{
+		{want: "xnull"},
+		{p: &empty, want: "xnull"},
+		{p: &s, want: `x"s"`},
+	} {
+		if got := StringOrNull(td.p, []byte("x")); string(got) != td.want {
+			t.Errorf("got %s, wanted %s", got, td.want)
+		}
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.StringOrNull(&empty)
+	bw.RawByte(',')
+	bw.StringOrNull(&s)
+	bw.Flush()
+	if out.String() != `null,"s"` {
+		t.Errorf("got %q", out.String())
+	}
+}
@@ -0,0 +1,98 @@
+// Package publisher periodically builds a json document and writes it to a sink. It
+// covers the common "publish state every N seconds" pattern.
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// ErrBusy is returned by Publish when the previous publish hasn't finished yet.
+var ErrBusy = errors.New("publisher: previous publish still running")
+
+// ErrInterval is returned by Run when Interval isn't positive.
+var ErrInterval = errors.New("publisher: interval must be positive")
+
+// Sink receives published documents. doc is only valid until Publish returns.
+type Sink interface {
+	Publish(ctx context.Context, doc []byte) error
+}
+
+// SinkFunc adapts a function to Sink.
+type SinkFunc func(ctx context.Context, doc []byte) error
+
+// Publish implements Sink.
+func (fn SinkFunc) Publish(ctx context.Context, doc []byte) error {
+	return fn(ctx, doc)
+}
+
+// Publisher builds a document with Build and hands it to Sink. Only one publish runs
+// at a time. Run publishes between ticks, and its ticker holds at most one tick: when
+// a publish takes longer than Interval, the next one starts as soon as it returns and
+// any further ticks from that time are dropped. Publish called while another publish
+// is running returns ErrBusy.
+type Publisher struct {
+	// Interval is the time between publishes when using Run. It must be positive.
+	Interval time.Duration
+	// Build writes the document. It may return an error instead of setting bw.Error.
+	Build func(bw *jsonappender.BufWriter) error
+	Sink  Sink
+	// OnError is called with the errors of Run's publishes: those from Build and Sink,
+	// and ErrBusy when a tick comes while a call to Publish is running. It may be nil.
+	OnError func(err error)
+
+	busy int32
+	buf  bytes.Buffer
+	bw   *jsonappender.BufWriter
+}
+
+// Run publishes every Interval until ctx is done. It returns ctx.Err(), or ErrInterval
+// right away when Interval isn't positive.
+func (p *Publisher) Run(ctx context.Context) error {
+	if p.Interval <= 0 {
+		return ErrInterval
+	}
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			err := p.Publish(ctx)
+			if err != nil && p.OnError != nil {
+				p.OnError(err)
+			}
+		}
+	}
+}
+
+// Publish builds and publishes one document now. It returns ErrBusy without doing
+// anything if another publish is running.
+func (p *Publisher) Publish(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&p.busy, 0, 1) {
+		return ErrBusy
+	}
+	defer atomic.StoreInt32(&p.busy, 0)
+
+	p.buf.Reset()
+	if p.bw == nil {
+		p.bw = jsonappender.NewBufWriter(&p.buf)
+	} else {
+		p.bw.Reset(&p.buf)
+	}
+	err := p.Build(p.bw)
+	if err != nil {
+		return err
+	}
+	err = p.bw.Flush()
+	if err != nil {
+		return err
+	}
+	return p.Sink.Publish(ctx, p.buf.Bytes())
+}
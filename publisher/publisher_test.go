@@ -0,0 +1,166 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+func TestPublisher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		mu   sync.Mutex
+		docs []string
+	)
+	n := 0
+	p := &Publisher{
+		Interval: time.Millisecond,
+		Build: func(bw *jsonappender.BufWriter) error {
+			n++
+			if n == 2 {
+				return errors.New("build failed")
+			}
+			bw.RawString(`{"n":`)
+			bw.Int64(int64(n))
+			bw.RawByte('}')
+			return nil
+		},
+		Sink: SinkFunc(func(_ context.Context, doc []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+			docs = append(docs, string(doc))
+			if len(docs) == 3 {
+				cancel()
+			}
+			return nil
+		}),
+	}
+	var errs []error
+	p.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+	err := p.Run(ctx)
+	if err != context.Canceled {
+		t.Errorf("got error %v", err)
+	}
+	want := []string{`{"n":1}`, `{"n":3}`, `{"n":4}`}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(docs) != len(want) {
+		t.Fatalf("got %q", docs)
+	}
+	for i := range want {
+		if docs[i] != want[i] {
+			t.Errorf("got %q, wanted %q", docs, want)
+		}
+	}
+	if len(errs) != 1 || errs[0].Error() != "build failed" {
+		t.Errorf("got errors %v", errs)
+	}
+}
+
+func TestPublisher_RunInterval(t *testing.T) {
+	p := &Publisher{}
+	if err := p.Run(context.Background()); err != ErrInterval {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestPublisher_busy(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p := &Publisher{
+		Build: func(bw *jsonappender.BufWriter) error {
+			bw.RawString("null")
+			return nil
+		},
+		Sink: SinkFunc(func(context.Context, []byte) error {
+			close(started)
+			<-release
+			return nil
+		}),
+	}
+	done := make(chan error)
+	go func() {
+		done <- p.Publish(context.Background())
+	}()
+	<-started
+	if err := p.Publish(context.Background()); err != ErrBusy {
+		t.Errorf("got error %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+	sink := &FileSink{Path: path}
+	for _, doc := range []string{`{"a":1}`, `{}`} {
+		err = sink.Publish(context.Background(), []byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != doc {
+			t.Errorf("got %s, wanted %s", got, doc)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected temporary files to be removed, got %d files", len(files))
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" ||
+			r.Header.Get("X-Token") != "t" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		got, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	sink := &HTTPSink{
+		URL:    srv.URL,
+		Header: http.Header{"X-Token": {"t"}},
+	}
+	err := sink.Publish(context.Background(), []byte(`[1]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `[1]` {
+		t.Errorf("got %s", got)
+	}
+	status = http.StatusBadGateway
+	err = sink.Publish(context.Background(), []byte(`[1]`))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// FileSink replaces the file at Path with each document. It writes to a temporary file
+// in the same directory first so readers never see a partial document.
+type FileSink struct {
+	Path string
+	// Perm defaults to 0644.
+	Perm os.FileMode
+}
+
+// Publish implements Sink.
+func (s *FileSink) Publish(_ context.Context, doc []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // already renamed on success
+	_, err = tmp.Write(doc)
+	if err != nil {
+		tmp.Close() //nolint:errcheck // already failing
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	perm := s.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// HTTPSink POSTs each document to URL.
+type HTTPSink struct {
+	URL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+	// Header is added to each request. Content-Type defaults to application/json.
+	Header http.Header
+}
+
+// StatusError is returned by HTTPSink for responses outside the 2xx range.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return "publisher: unexpected response status " + strconv.Itoa(e.StatusCode)
+}
+
+// Publish implements Sink.
+func (s *HTTPSink) Publish(ctx context.Context, doc []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(doc))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // nothing useful to do with the error
+	_, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
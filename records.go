@@ -0,0 +1,77 @@
+package jsonappender
+
+// Record separators for RecordWriter.
+const (
+	// SeparatorLF separates newline delimited json (ndjson, jsonl).
+	SeparatorLF = "\n"
+	// SeparatorCRLF is a Windows line ending.
+	SeparatorCRLF = "\r\n"
+	// SeparatorRS is the ASCII record separator. Json text sequences (RFC 7464) put it
+	// before each record rather than between them, so for those use SetPrefix with it
+	// and SeparatorLF as a trailing separator.
+	SeparatorRS = "\x1e"
+)
+
+// RecordWriter writes a sequence of json documents to a BufWriter with a separator
+// between them. Call Next before writing each record, or use Value for records that
+// are a single value, and Finish after the last.
+type RecordWriter struct {
+	bw        *BufWriter
+	separator string
+	prefix    string
+	trailing  bool
+	records   int
+}
+
+// NewRecordWriter returns a RecordWriter that writes to bw. When trailing is true the
+// separator also follows the last record.
+func NewRecordWriter(bw *BufWriter, separator string, trailing bool) *RecordWriter {
+	return &RecordWriter{
+		bw:        bw,
+		separator: separator,
+		trailing:  trailing,
+	}
+}
+
+// SetPrefix sets a string to write before every record, after the separator.
+func (rw *RecordWriter) SetPrefix(prefix string) {
+	rw.prefix = prefix
+}
+
+// Next starts a record, writing the separator if a record came before and then the
+// prefix.
+func (rw *RecordWriter) Next() {
+	if rw.records > 0 {
+		rw.bw.RawString(rw.separator)
+	}
+	if rw.prefix != "" {
+		rw.bw.RawString(rw.prefix)
+	}
+	rw.records++
+}
+
+// Value writes val as a record.
+func (rw *RecordWriter) Value(val interface{}) {
+	rw.Next()
+	rw.bw.Value(val)
+}
+
+// Records returns the number of records started.
+func (rw *RecordWriter) Records() int {
+	return rw.records
+}
+
+// Finish writes the trailing separator when there is one and returns the BufWriter's
+// error. It doesn't flush.
+func (rw *RecordWriter) Finish() error {
+	if rw.trailing && rw.records > 0 {
+		rw.bw.RawString(rw.separator)
+	}
+	return rw.bw.Error
+}
+
+// Reset forgets the records written so far so the next record isn't preceded by a
+// separator.
+func (rw *RecordWriter) Reset() {
+	rw.records = 0
+}
@@ -0,0 +1,43 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecordWriter(t *testing.T) {
+	for _, td := range []struct {
+		separator string
+		prefix    string
+		trailing  bool
+		records   []interface{}
+		want      string
+	}{
+		{separator: SeparatorLF, trailing: true, records: []interface{}{1, "a"}, want: "1\n\"a\"\n"},
+		{separator: SeparatorCRLF, trailing: false, records: []interface{}{1, "a"}, want: "1\r\n\"a\""},
+		{separator: SeparatorRS, trailing: true, records: []interface{}{true}, want: "true\x1e"},
+		{separator: ",\n", trailing: true, records: nil, want: ""},
+		// RFC 7464
+		{separator: SeparatorLF, prefix: SeparatorRS, trailing: true, records: []interface{}{1, "a"}, want: "\x1e1\n\x1e\"a\"\n"},
+	} {
+		var out bytes.Buffer
+		bw := NewBufWriter(&out)
+		rw := NewRecordWriter(bw, td.separator, td.trailing)
+		rw.SetPrefix(td.prefix)
+		for _, r := range td.records {
+			rw.Value(r)
+		}
+		if err := rw.Finish(); err != nil {
+			t.Fatal(err)
+		}
+		if err := bw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != td.want {
+			t.Errorf("got %q, wanted %q", out.String(), td.want)
+		}
+		if rw.Records() != len(td.records) {
+			t.Errorf("got %d records", rw.Records())
+		}
+	}
+}
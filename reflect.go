@@ -0,0 +1,507 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict
+// +build !jsonappender_noreflect,!jsonappender_strict
+
+package jsonappender
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// encoderFunc appends v. depth counts the pointers, maps and slices followed so far.
+// Past maxReflectDepth, seen holds the ones on the way to v to detect cycles.
+type encoderFunc func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error)
+
+// maxReflectDepth is how deep appendReflect goes before it starts looking for cycles,
+// like encoding/json. Checking every pointer would be too slow.
+const maxReflectDepth = 1000
+
+// ptrSeen holds the pointers, maps and slices being encoded. It's nil until
+// maxReflectDepth is passed.
+type ptrSeen map[interface{}]struct{}
+
+// sliceKey identifies a slice in ptrSeen. Slices of different lengths sharing an array
+// aren't a cycle.
+type sliceKey struct {
+	ptr uintptr
+	len int
+}
+
+// enter adds key for v to seen, allocating it if needed, or fails when key is already
+// there because v refers back to one of the values containing it.
+func (seen ptrSeen) enter(v reflect.Value, key interface{}) (ptrSeen, error) {
+	if seen == nil {
+		seen = ptrSeen{}
+	}
+	if _, ok := seen[key]; ok {
+		return seen, errCycle(v)
+	}
+	seen[key] = struct{}{}
+	return seen, nil
+}
+
+// encoderCache maps a reflect.Type to its encoderFunc. Each encoder is a plan compiled
+// once per type, so repeat encodes only pay for the type lookup.
+var encoderCache sync.Map
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	numberType        = reflect.TypeOf(json.Number(""))
+	appenderType      = reflect.TypeOf((*JSONAppender)(nil)).Elem()
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// appendReflect appends val using reflection.
+func appendReflect(val interface{}, buf []byte) ([]byte, error) {
+	if val == nil {
+		return Null(buf), nil
+	}
+	v := reflect.ValueOf(val)
+	return typeEncoder(v.Type())(v, buf, 0, nil)
+}
+
+func typeEncoder(t reflect.Type) encoderFunc {
+	if enc, ok := encoderCache.Load(t); ok {
+		return enc.(encoderFunc)
+	}
+
+	// Recursive types need an encoder before theirs is compiled. Store one that waits
+	// for the real encoder and then calls it.
+	var (
+		wg  sync.WaitGroup
+		enc encoderFunc
+	)
+	wg.Add(1)
+	indirect, loaded := encoderCache.LoadOrStore(t, encoderFunc(func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		wg.Wait()
+		return enc(v, buf, depth, seen)
+	}))
+	if loaded {
+		return indirect.(encoderFunc)
+	}
+	enc = newTypeEncoder(t, true)
+	wg.Done()
+	encoderCache.Store(t, enc)
+	return enc
+}
+
+func newTypeEncoder(t reflect.Type, allowAddr bool) encoderFunc {
+	switch t {
+	case timeType:
+		return timeEncoder
+	case numberType:
+		return numberEncoder
+	}
+	// Like encoding/json, use pointer receiver methods when the value is addressable.
+	if t.Kind() != reflect.Ptr && allowAddr {
+		pt := reflect.PtrTo(t)
+		if pt.Implements(appenderType) || pt.Implements(marshalerType) || pt.Implements(textMarshalerType) {
+			return condAddrEncoder(newTypeEncoder(pt, false), newTypeEncoder(t, false))
+		}
+	}
+	switch {
+	case t.Implements(appenderType):
+		return appenderEncoder
+	case t.Implements(marshalerType):
+		return marshalerEncoder
+	case t.Implements(textMarshalerType):
+		return textMarshalerEncoder
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolEncoder
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intEncoder
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintEncoder
+	case reflect.Float64:
+		return float64Encoder
+	case reflect.Float32:
+		return float32Encoder
+	case reflect.String:
+		return stringEncoder
+	case reflect.Interface:
+		return interfaceEncoder
+	case reflect.Ptr:
+		return newPtrEncoder(t)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(t.Elem()).Implements(marshalerType) &&
+			!reflect.PtrTo(t.Elem()).Implements(textMarshalerType) {
+			return bytesEncoder
+		}
+		return newSliceEncoder(t)
+	case reflect.Array:
+		return newArrayEncoder(t)
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String && !t.Key().Implements(textMarshalerType) {
+			return newMapEncoder(t)
+		}
+	case reflect.Struct:
+		if enc := newStructEncoder(t); enc != nil {
+			return enc
+		}
+	}
+	// Everything else is rare enough to leave to encoding/json.
+	return marshalEncoder
+}
+
+func condAddrEncoder(addrEnc, elseEnc encoderFunc) encoderFunc {
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		if v.CanAddr() {
+			return addrEnc(v.Addr(), buf, depth, seen)
+		}
+		return elseEnc(v, buf, depth, seen)
+	}
+}
+
+func marshalEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	bb, err := json.Marshal(v.Interface())
+	return append(buf, bb...), err
+}
+
+func timeEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Time(v.Interface().(time.Time), buf)
+}
+
+func appenderEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return Null(buf), nil
+	}
+	return v.Interface().(JSONAppender).AppendJSON(buf)
+}
+
+func marshalerEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return Null(buf), nil
+	}
+	// json.Marshal validates and compacts MarshalJSON output
+	bb, err := json.Marshal(v.Interface())
+	return append(buf, bb...), err
+}
+
+func textMarshalerEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return Null(buf), nil
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return buf, err
+	}
+	return String(string(text), buf), nil
+}
+
+func boolEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Bool(v.Bool(), buf), nil
+}
+
+func intEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Int64(v.Int(), buf), nil
+}
+
+func uintEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Uint64(v.Uint(), buf), nil
+}
+
+func float64Encoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Float64(v.Float(), buf)
+}
+
+func float32Encoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Float32(float32(v.Float()), buf)
+}
+
+func numberEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return NumberString(v.String(), buf)
+}
+
+func stringEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return String(v.String(), buf), nil
+}
+
+func bytesEncoder(v reflect.Value, buf []byte, _ int, _ ptrSeen) ([]byte, error) {
+	return Bytes(v.Bytes(), buf), nil
+}
+
+func interfaceEncoder(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+	if v.IsNil() {
+		return Null(buf), nil
+	}
+	e := v.Elem()
+	return typeEncoder(e.Type())(e, buf, depth, seen)
+}
+
+func errCycle(v reflect.Value) error {
+	return &json.UnsupportedValueError{
+		Value: v,
+		Str:   "encountered a cycle via " + v.Type().String(),
+	}
+}
+
+func newPtrEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		if v.IsNil() {
+			return Null(buf), nil
+		}
+		if depth > maxReflectDepth {
+			key := v.Pointer()
+			var err error
+			if seen, err = seen.enter(v, key); err != nil {
+				return buf, err
+			}
+			defer delete(seen, key)
+		}
+		return elemEnc(v.Elem(), buf, depth+1, seen)
+	}
+}
+
+func newSliceEncoder(t reflect.Type) encoderFunc {
+	arrayEnc := newArrayEncoder(t)
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		if v.IsNil() {
+			return Null(buf), nil
+		}
+		if depth > maxReflectDepth {
+			key := sliceKey{ptr: v.Pointer(), len: v.Len()}
+			var err error
+			if seen, err = seen.enter(v, key); err != nil {
+				return buf, err
+			}
+			defer delete(seen, key)
+		}
+		return arrayEnc(v, buf, depth+1, seen)
+	}
+}
+
+func newArrayEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		buf = append(buf, '[')
+		var err error
+		n := v.Len()
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf, err = elemEnc(v.Index(i), buf, depth, seen)
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, ']'), nil
+	}
+}
+
+func newMapEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		if v.IsNil() {
+			return Null(buf), nil
+		}
+		if depth > maxReflectDepth {
+			key := v.Pointer()
+			var err error
+			if seen, err = seen.enter(v, key); err != nil {
+				return buf, err
+			}
+			defer delete(seen, key)
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		buf = append(buf, '{')
+		var err error
+		for i, k := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = FieldName(k.String(), buf)
+			buf, err = elemEnc(v.MapIndex(k), buf, depth+1, seen)
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, '}'), nil
+	}
+}
+
+// fieldPlan is how one struct field is encoded.
+type fieldPlan struct {
+	index     int
+	key       []byte // `"name":`
+	omitEmpty bool
+	quoted    bool
+	enc       encoderFunc
+	name      string
+	tagged    bool
+}
+
+// newStructEncoder returns nil for structs with embedded fields. Those are left to
+// encoding/json, which resolves the promoted fields.
+func newStructEncoder(t reflect.Type) encoderFunc {
+	var fields []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous {
+			return nil
+		}
+		if sf.PkgPath != "" {
+			// unexported
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, opts = tag[:idx], tag[idx:]
+		}
+		tagged := validTagName(name)
+		if !tagged {
+			name = sf.Name
+		}
+		fields = append(fields, fieldPlan{
+			name:      name,
+			tagged:    tagged,
+			index:     i,
+			key:       FieldName(name, nil),
+			omitEmpty: strings.Contains(opts, ",omitempty"),
+			quoted:    strings.Contains(opts, ",string") && quotableKind(sf.Type),
+			enc:       typeEncoder(sf.Type),
+		})
+	}
+	fields = dominantFields(fields)
+	return unsafeStructEncoder(t, fields, func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		buf = append(buf, '{')
+		comma := false
+		var err error
+		for i := range fields {
+			f := &fields[i]
+			fv := v.Field(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if comma {
+				buf = append(buf, ',')
+			}
+			comma = true
+			buf = append(buf, f.key...)
+			if f.quoted {
+				buf, err = appendQuoted(fv, f.enc, buf, depth, seen)
+			} else {
+				buf, err = f.enc(fv, buf, depth, seen)
+			}
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, '}'), nil
+	})
+}
+
+// dominantFields applies encoding/json's rules to fields that share a name: a single
+// tagged one wins, and otherwise none of them is written. Embedded structs aren't
+// encoded here, so every field has the same depth.
+func dominantFields(fields []fieldPlan) []fieldPlan {
+	type count struct{ all, tagged int }
+	counts := make(map[string]count, len(fields))
+	for _, f := range fields {
+		c := counts[f.name]
+		c.all++
+		if f.tagged {
+			c.tagged++
+		}
+		counts[f.name] = c
+	}
+	kept := fields[:0]
+	for _, f := range fields {
+		c := counts[f.name]
+		if c.all == 1 || c.tagged == 1 && f.tagged {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// appendQuoted handles the ",string" tag option. Like encoding/json it looks through
+// pointers and writes null for nil ones.
+func appendQuoted(v reflect.Value, enc encoderFunc, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return Null(buf), nil
+		}
+		v = v.Elem()
+		enc = typeEncoder(v.Type())
+	}
+	if v.Kind() == reflect.String && v.Type() != numberType {
+		return String(string(String(v.String(), nil)), buf), nil
+	}
+	buf = append(buf, '"')
+	buf, err := enc(v, buf, depth, seen)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, '"'), nil
+}
+
+// quotableKind reports whether the ",string" option applies to fields of type t. For an
+// unnamed pointer type that's up to its element type, like with encoding/json.
+func quotableKind(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr && t.Name() == "" {
+		if t.Implements(marshalerType) || t.Implements(textMarshalerType) || t.Implements(appenderType) {
+			return false
+		}
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float64, reflect.Float32,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return !t.Implements(marshalerType) && !t.Implements(textMarshalerType) &&
+			!t.Implements(appenderType)
+	}
+	return false
+}
+
+// validTagName reports whether encoding/json would use name from a struct tag.
+func validTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case c >= 0x80:
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
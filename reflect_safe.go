@@ -0,0 +1,11 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict && !jsonappender_unsafe
+// +build !jsonappender_noreflect,!jsonappender_strict,!jsonappender_unsafe
+
+package jsonappender
+
+import "reflect"
+
+// unsafeStructEncoder returns enc unchanged unless built with jsonappender_unsafe.
+func unsafeStructEncoder(_ reflect.Type, _ []fieldPlan, enc encoderFunc) encoderFunc {
+	return enc
+}
@@ -0,0 +1,283 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict
+// +build !jsonappender_noreflect,!jsonappender_strict
+
+package jsonappender
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+type reflectTestNode struct {
+	Name     string             `json:"name"`
+	Children []*reflectTestNode `json:"children,omitempty"`
+}
+
+type reflectTestPtrMarshaler struct {
+	A int
+}
+
+func (m *reflectTestPtrMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"ptr marshaler"`), nil
+}
+
+type reflectTestAppender struct{}
+
+func (reflectTestAppender) AppendJSON(buf []byte) ([]byte, error) {
+	return append(buf, `"appender"`...), nil
+}
+
+type reflectTestEmbedded struct {
+	A int
+}
+
+type reflectTestStruct struct {
+	String    string
+	Tagged    int            `json:"tagged"`
+	Omitted   string         `json:",omitempty"`
+	Skipped   string         `json:"-"`
+	Dash      string         `json:"-,"`
+	Quoted    int64          `json:",string"`
+	QuotedStr string         `json:",string"`
+	QuotedF   float64        `json:",string"`
+	Bool      bool           `json:"bool,omitempty"`
+	Float32   float32        `json:"float32"`
+	Bytes     []byte         `json:"bytes"`
+	Array     [2]uint8       `json:"array"`
+	Map       map[string]int `json:"map"`
+	IntMap    map[int]string `json:"int_map"`
+	Iface     interface{}    `json:"iface"`
+	Ptr       *int           `json:"ptr"`
+	Time      time.Time      `json:"time"`
+	IP        net.IP         `json:"ip"`
+	PtrMarsh  reflectTestPtrMarshaler
+	PtrMarsh2 *reflectTestPtrMarshaler
+	Tree      *reflectTestNode
+	Embedded  struct{ reflectTestEmbedded }
+	Number    json.Number
+	QuotedNum json.Number `json:",string"`
+	unexp     int
+}
+
+func TestAppendReflect(t *testing.T) {
+	num := 12
+	values := []interface{}{
+		nil,
+		struct{}{},
+		&struct{ A, B int }{A: 1, B: 2},
+		[]int(nil),
+		[]string{"a", "<b>"},
+		map[string][]float64{"b": {1.5}, "a": nil},
+		reflectTestStruct{},
+		&reflectTestStruct{
+			String:    "s\u2028",
+			Tagged:    3,
+			Omitted:   "o",
+			Quoted:    -4,
+			QuotedStr: `a"b`,
+			QuotedF:   1.25,
+			Bool:      true,
+			Float32:   1.1,
+			Bytes:     []byte("hello world"),
+			Array:     [2]uint8{1, 2},
+			Map:       map[string]int{"z": 1, "a": 2},
+			IntMap:    map[int]string{2: "b", 1: "a"},
+			Iface:     []interface{}{1, "a", map[string]interface{}{"x": nil}},
+			Ptr:       &num,
+			Time:      time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC),
+			IP:        net.ParseIP("10.0.0.1"),
+			PtrMarsh2: &reflectTestPtrMarshaler{},
+			Tree: &reflectTestNode{
+				Name:     "root",
+				Children: []*reflectTestNode{{Name: "a"}, {Name: "b", Children: []*reflectTestNode{{Name: "c"}}}},
+			},
+			Embedded:  struct{ reflectTestEmbedded }{reflectTestEmbedded{A: 5}},
+			Number:    "1.50",
+			QuotedNum: "2",
+			unexp:     1,
+		},
+	}
+	for _, val := range values {
+		want, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := appendReflect(val, []byte("x"))
+		if err != nil {
+			t.Errorf("%T: %v", val, err)
+			continue
+		}
+		if string(got) != "x"+string(want) {
+			t.Errorf("%T:\ngot  %s\nwant x%s", val, got, want)
+		}
+	}
+
+	got, err := Value(struct{ Appender reflectTestAppender }{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"Appender":"appender"`) {
+		t.Errorf("expected JSONAppender to be used: %s", got)
+	}
+}
+
+// reflectTestString generates strings without \b and \f, which newer versions of
+// encoding/json escape differently than String does.
+func reflectTestString() gopter.Gen {
+	return gen.AnyString().Map(func(s string) string {
+		return strings.NewReplacer("\b", "", "\f", "").Replace(s)
+	})
+}
+
+func TestAppendReflect_property(t *testing.T) {
+	type sample struct {
+		A string            `json:"a,omitempty"`
+		B int64             `json:"b"`
+		C []float64         `json:"c"`
+		D map[string]string `json:"d,omitempty"`
+		E bool              `json:",string"`
+	}
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json", prop.ForAll(
+		func(val sample) bool {
+			got, err := appendReflect(val, nil)
+			want, wantErr := json.Marshal(val)
+			if wantErr != nil {
+				return err != nil
+			}
+			return err == nil && string(got) == string(want)
+		},
+		gen.Struct(reflect.TypeOf(sample{}), map[string]gopter.Gen{
+			"A": reflectTestString(),
+			"B": gen.Int64(),
+			"C": gen.SliceOf(gen.Float64()),
+			"D": gen.MapOf(reflectTestString(), reflectTestString()),
+			"E": gen.Bool(),
+		}),
+	))
+	properties.TestingRun(t)
+}
+
+func TestAppendReflect_duplicateNames(t *testing.T) {
+	// built with StructOf because vet rejects repeated tags in struct literals
+	newStruct := func(tags ...string) interface{} {
+		fields := make([]reflect.StructField, len(tags))
+		for i, tag := range tags {
+			fields[i] = reflect.StructField{
+				Name: string(rune('A' + i)),
+				Type: reflect.TypeOf(0),
+				Tag:  reflect.StructTag(tag),
+			}
+		}
+		v := reflect.New(reflect.StructOf(fields)).Elem()
+		for i := range tags {
+			v.Field(i).SetInt(int64(i + 1))
+		}
+		return v.Interface()
+	}
+	for _, val := range []interface{}{
+		newStruct(`json:"x"`, `json:"x"`),
+		newStruct(``, `json:"A"`),
+		newStruct(``, `json:"A"`, `json:"A"`, `json:"d"`),
+		newStruct(`json:"B"`, ``),
+	} {
+		want, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := appendReflect(val, nil)
+		if err != nil || string(got) != string(want) {
+			t.Errorf("got %s, %v, wanted %s", got, err, want)
+		}
+	}
+}
+
+func TestAppendReflect_quotedPointers(t *testing.T) {
+	n := int64(5)
+	str := "s"
+	type quotedPointers struct {
+		P *int64   `json:"p,string"`
+		S *string  `json:",string"`
+		B **bool   `json:",string"`
+		N *float64 `json:",string"`
+	}
+	val := quotedPointers{P: &n, S: &str}
+	for _, v := range []interface{}{val, &val} {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := appendReflect(v, nil)
+		if err != nil || string(got) != string(want) {
+			t.Errorf("got %s, %v, wanted %s", got, err, want)
+		}
+	}
+}
+
+func TestAppendReflect_errors(t *testing.T) {
+	wantErr := errors.New("fail")
+	_, err := appendReflect(struct{ A interface{} }{A: AppendFunc(func(buf []byte) ([]byte, error) {
+		return buf, wantErr
+	})}, nil)
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+
+	type cycle struct {
+		Next *cycle
+	}
+	c := &cycle{}
+	c.Next = c
+	_, err = appendReflect(c, nil)
+	var unsupported *json.UnsupportedValueError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedValueError, got %v", err)
+	}
+
+	// deep but acyclic values are fine, like with encoding/json
+	deep := &cycle{}
+	for i := 0; i < 1200; i++ {
+		deep = &cycle{Next: deep}
+	}
+	got, err := appendReflect(deep, nil)
+	want, wantErr := json.Marshal(deep)
+	if err != nil || wantErr != nil || string(got) != string(want) {
+		t.Errorf("got %v, wanted %v, outputs equal: %v", err, wantErr, string(got) == string(want))
+	}
+	deepMap := map[string]interface{}{}
+	m := deepMap
+	for i := 0; i < 1200; i++ {
+		next := map[string]interface{}{}
+		m["m"] = next
+		m = next
+	}
+	m["m"] = deepMap
+	_, err = appendReflect(deepMap, nil)
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedValueError for a deep cycle, got %v", err)
+	}
+}
+
+func BenchmarkAppendReflect(b *testing.B) {
+	val := reflectTestNode{
+		Name:     "root",
+		Children: []*reflectTestNode{{Name: "a"}, {Name: "b", Children: []*reflectTestNode{{Name: "c"}}}},
+	}
+	buf := make([]byte, 0, 1024)
+	var err error
+	for i := 0; i < b.N; i++ {
+		buf, err = appendReflect(val, buf[:0])
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict && jsonappender_unsafe
+// +build !jsonappender_noreflect,!jsonappender_strict,jsonappender_unsafe
+
+package jsonappender
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// unsafeFieldFunc appends the field at p.
+type unsafeFieldFunc func(p unsafe.Pointer, buf []byte) ([]byte, error)
+
+// unsafeField is a fieldPlan with direct access to the field's memory. enc is nil for
+// fields that still go through reflect.
+type unsafeField struct {
+	fieldPlan
+	offset uintptr
+	enc    unsafeFieldFunc
+	empty  func(p unsafe.Pointer) bool
+}
+
+// unsafeStructEncoder reads basic fields at their offset from the struct's address
+// instead of through reflect.Value. The struct has to be addressable, which it is when
+// it's reached through a pointer, slice or array. Other values use enc.
+func unsafeStructEncoder(t reflect.Type, fields []fieldPlan, enc encoderFunc) encoderFunc {
+	plans := make([]unsafeField, len(fields))
+	fast := false
+	for i, f := range fields {
+		sf := t.Field(f.index)
+		plans[i] = unsafeField{
+			fieldPlan: f,
+			offset:    sf.Offset,
+		}
+		if f.quoted {
+			continue
+		}
+		plans[i].enc, plans[i].empty = unsafeFieldEncoder(sf.Type)
+		if plans[i].enc != nil {
+			fast = true
+		}
+	}
+	if !fast {
+		return enc
+	}
+	return func(v reflect.Value, buf []byte, depth int, seen ptrSeen) ([]byte, error) {
+		if !v.CanAddr() {
+			return enc(v, buf, depth, seen)
+		}
+		base := unsafe.Pointer(v.UnsafeAddr())
+		buf = append(buf, '{')
+		comma := false
+		var err error
+		for i := range plans {
+			f := &plans[i]
+			if f.enc == nil {
+				fv := v.Field(f.index)
+				if f.omitEmpty && isEmptyValue(fv) {
+					continue
+				}
+				if comma {
+					buf = append(buf, ',')
+				}
+				comma = true
+				buf = append(buf, f.key...)
+				if f.quoted {
+					buf, err = appendQuoted(fv, f.fieldPlan.enc, buf, depth, seen)
+				} else {
+					buf, err = f.fieldPlan.enc(fv, buf, depth, seen)
+				}
+				if err != nil {
+					return buf, err
+				}
+				continue
+			}
+			p := unsafe.Pointer(uintptr(base) + f.offset)
+			if f.omitEmpty && f.empty(p) {
+				continue
+			}
+			if comma {
+				buf = append(buf, ',')
+			}
+			comma = true
+			buf = append(buf, f.key...)
+			buf, err = f.enc(p, buf)
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, '}'), nil
+	}
+}
+
+// unsafeFieldEncoder returns nil for types that need reflect, including json.Number
+// and any type with a method that changes how it's encoded.
+func unsafeFieldEncoder(t reflect.Type) (unsafeFieldFunc, func(p unsafe.Pointer) bool) {
+	pt := reflect.PtrTo(t)
+	if t == numberType || pt.Implements(appenderType) || pt.Implements(marshalerType) || pt.Implements(textMarshalerType) {
+		return nil, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Bool(*(*bool)(p), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return !*(*bool)(p)
+			}
+	case reflect.Int:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Int64(int64(*(*int)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*int)(p) == 0
+			}
+	case reflect.Int8:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Int64(int64(*(*int8)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*int8)(p) == 0
+			}
+	case reflect.Int16:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Int64(int64(*(*int16)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*int16)(p) == 0
+			}
+	case reflect.Int32:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Int64(int64(*(*int32)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*int32)(p) == 0
+			}
+	case reflect.Int64:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Int64(*(*int64)(p), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*int64)(p) == 0
+			}
+	case reflect.Uint:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Uint64(uint64(*(*uint)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*uint)(p) == 0
+			}
+	case reflect.Uint8:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Uint64(uint64(*(*uint8)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*uint8)(p) == 0
+			}
+	case reflect.Uint16:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Uint64(uint64(*(*uint16)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*uint16)(p) == 0
+			}
+	case reflect.Uint32:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Uint64(uint64(*(*uint32)(p)), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*uint32)(p) == 0
+			}
+	case reflect.Uint64:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Uint64(*(*uint64)(p), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*uint64)(p) == 0
+			}
+	case reflect.Float64:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Float64(*(*float64)(p), buf)
+			}, func(p unsafe.Pointer) bool {
+				return *(*float64)(p) == 0
+			}
+	case reflect.Float32:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return Float32(*(*float32)(p), buf)
+			}, func(p unsafe.Pointer) bool {
+				return *(*float32)(p) == 0
+			}
+	case reflect.String:
+		return func(p unsafe.Pointer, buf []byte) ([]byte, error) {
+				return String(*(*string)(p), buf), nil
+			}, func(p unsafe.Pointer) bool {
+				return *(*string)(p) == ""
+			}
+	}
+	return nil, nil
+}
@@ -0,0 +1,60 @@
+//go:build !jsonappender_noreflect && !jsonappender_strict && jsonappender_unsafe
+// +build !jsonappender_noreflect,!jsonappender_strict,jsonappender_unsafe
+
+package jsonappender
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type unsafeTestStruct struct {
+	Bool   bool    `json:"bool"`
+	Int    int     `json:"int,omitempty"`
+	Int8   int8    `json:"int8"`
+	Int16  int16   `json:"int16"`
+	Int32  int32   `json:"int32"`
+	Int64  int64   `json:"int64,string"`
+	Uint   uint    `json:"uint"`
+	Uint8  uint8   `json:"uint8"`
+	Uint16 uint16  `json:"uint16"`
+	Uint32 uint32  `json:"uint32"`
+	Uint64 uint64  `json:"uint64"`
+	Float  float64 `json:"float,omitempty"`
+	String string  `json:"string"`
+	Slice  []int   `json:"slice"`
+	Inner  struct {
+		A string `json:",omitempty"`
+		B int
+	}
+}
+
+func TestUnsafeStructEncoder(t *testing.T) {
+	full := unsafeTestStruct{
+		Bool: true, Int: -1, Int8: -8, Int16: -16, Int32: -32, Int64: -64,
+		Uint: 1, Uint8: 8, Uint16: 16, Uint32: 32, Uint64: 64,
+		Float: 1.5, String: "<s>", Slice: []int{1},
+	}
+	full.Inner.A = "a"
+	full.Inner.B = 2
+	for _, val := range []interface{}{
+		unsafeTestStruct{},
+		&unsafeTestStruct{},
+		full,
+		&full,
+		[]unsafeTestStruct{full, {}},
+		[1]*unsafeTestStruct{&full},
+	} {
+		want, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := appendReflect(val, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%T:\ngot  %s\nwant %s", val, got, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package runtimemetrics writes runtime/metrics samples as json. It needs Go 1.16 or
+// newer and is empty on older versions.
+package runtimemetrics
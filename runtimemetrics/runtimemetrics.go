@@ -0,0 +1,92 @@
+//go:build go1.16
+// +build go1.16
+
+package runtimemetrics
+
+import (
+	"runtime/metrics"
+	"sync"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Sampler reads every metric the runtime supports and writes them as one json object
+// keyed by metric name. Sampler reuses its samples between reads, so reading again
+// doesn't allocate for the metrics themselves.
+//
+// Uint64 and float64 metrics are written as numbers. Histograms are written as
+//
+//	{"counts":[...],"buckets":[...]}
+//
+// where buckets holds the len(counts)+1 bucket boundaries. NaN and infinite values are
+// written as the strings "NaN", "+Inf" and "-Inf". Metrics the runtime no longer
+// supports are written as null.
+//
+// A Sampler is safe for concurrent use.
+type Sampler struct {
+	mu      sync.Mutex
+	samples []metrics.Sample
+}
+
+// NewSampler returns a Sampler for all metrics in metrics.All.
+func NewSampler() *Sampler {
+	descs := metrics.All()
+	samples := make([]metrics.Sample, len(descs))
+	for i := range descs {
+		samples[i].Name = descs[i].Name
+	}
+	return &Sampler{
+		samples: samples,
+	}
+}
+
+// AppendJSON implements jsonappender.JSONAppender. It reads the metrics and appends
+// them.
+func (s *Sampler) AppendJSON(buf []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metrics.Read(s.samples)
+	buf = append(buf, '{')
+	for i := range s.samples {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendSample(&s.samples[i], buf)
+	}
+	return append(buf, '}'), nil
+}
+
+func appendSample(s *metrics.Sample, buf []byte) []byte {
+	buf = jsonappender.FieldName(s.Name, buf)
+	switch s.Value.Kind() {
+	case metrics.KindUint64:
+		return jsonappender.Uint64(s.Value.Uint64(), buf)
+	case metrics.KindFloat64:
+		return appendFloat(s.Value.Float64(), buf)
+	case metrics.KindFloat64Histogram:
+		h := s.Value.Float64Histogram()
+		buf = append(buf, `{"counts":[`...)
+		for i, c := range h.Counts {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = jsonappender.Uint64(c, buf)
+		}
+		buf = append(buf, `],"buckets":[`...)
+		for i, b := range h.Buckets {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendFloat(b, buf)
+		}
+		return append(buf, ']', '}')
+	}
+	return jsonappender.Null(buf)
+}
+
+// floatNames spell NaN and infinities the way strconv does.
+var floatNames = jsonappender.NonFiniteNames{NaN: "NaN", PosInf: "+Inf", NegInf: "-Inf"}
+
+func appendFloat(f float64, buf []byte) []byte {
+	return jsonappender.Float64Named(f, floatNames, buf)
+}
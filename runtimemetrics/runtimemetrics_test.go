@@ -0,0 +1,43 @@
+//go:build go1.16
+// +build go1.16
+
+package runtimemetrics
+
+import (
+	"encoding/json"
+	"runtime/metrics"
+	"testing"
+)
+
+func TestSampler(t *testing.T) {
+	s := NewSampler()
+	got, err := s.AppendJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("%v: %s", err, got)
+	}
+	if len(doc) != len(metrics.All()) {
+		t.Errorf("got %d metrics, wanted %d", len(doc), len(metrics.All()))
+	}
+	if _, ok := doc["/gc/cycles/total:gc-cycles"].(float64); !ok {
+		t.Errorf("expected a number for gc cycles, got %v", doc["/gc/cycles/total:gc-cycles"])
+	}
+	for _, desc := range metrics.All() {
+		if desc.Kind != metrics.KindFloat64Histogram {
+			continue
+		}
+		hist, ok := doc[desc.Name].(map[string]interface{})
+		if !ok {
+			t.Errorf("expected an object for %s, got %v", desc.Name, doc[desc.Name])
+			continue
+		}
+		counts, _ := hist["counts"].([]interface{})
+		buckets, _ := hist["buckets"].([]interface{})
+		if len(buckets) != len(counts)+1 {
+			t.Errorf("%s: got %d buckets for %d counts", desc.Name, len(buckets), len(counts))
+		}
+	}
+}
@@ -0,0 +1,254 @@
+//go:build jsonappender_ryu
+// +build jsonappender_ryu
+
+package jsonappender
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// useRyu makes appendFloat format float64 values with appendRyu.
+const useRyu = true
+
+const (
+	ryuMantissaBits    = 52
+	ryuBias            = 1023
+	ryuPow5InvBitCount = 125
+	ryuPow5BitCount    = 125
+)
+
+// appendRyu appends the finite f like appendFloat, finding its shortest digits with
+// Ulf Adams' Ryū algorithm instead of strconv.
+func appendRyu(f float64, buf []byte) []byte {
+	u := math.Float64bits(f)
+	if u>>63 != 0 {
+		buf = append(buf, '-')
+	}
+	ieeeMantissa := u & (1<<ryuMantissaBits - 1)
+	ieeeExponent := uint32(u>>ryuMantissaBits) & 0x7ff
+	if ieeeMantissa == 0 && ieeeExponent == 0 {
+		return append(buf, '0')
+	}
+	digits, exp := ryuShortest(ieeeMantissa, ieeeExponent)
+	return appendES6(digits, exp, buf)
+}
+
+// appendES6 appends digits*10^exp the way ES6 converts numbers to strings: in plain
+// decimal notation from 1e-6 up to 1e21 and with an exponent outside of that.
+func appendES6(digits uint64, exp int32, buf []byte) []byte {
+	var scratch [20]byte
+	d := strconv.AppendUint(scratch[:0], digits, 10)
+	n := int32(len(d))
+	point := n + exp
+	switch {
+	case point >= 22 || point <= -6:
+		buf = append(buf, d[0])
+		if n > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, d[1:]...)
+		}
+		buf = append(buf, 'e')
+		e := point - 1
+		if e < 0 {
+			buf = append(buf, '-')
+			e = -e
+		} else {
+			buf = append(buf, '+')
+		}
+		return strconv.AppendInt(buf, int64(e), 10)
+	case point <= 0:
+		buf = append(buf, "0."...)
+		for i := point; i < 0; i++ {
+			buf = append(buf, '0')
+		}
+		return append(buf, d...)
+	case point < n:
+		buf = append(buf, d[:point]...)
+		buf = append(buf, '.')
+		return append(buf, d[point:]...)
+	}
+	buf = append(buf, d...)
+	for i := n; i < point; i++ {
+		buf = append(buf, '0')
+	}
+	return buf
+}
+
+// ryuShortest returns the shortest digits and decimal exponent that read back as the
+// float64 with the given mantissa and biased exponent bits. It follows d2d from the
+// reference implementation at github.com/ulfjack/ryu.
+func ryuShortest(ieeeMantissa uint64, ieeeExponent uint32) (uint64, int32) {
+	var e2 int32
+	var m2 uint64
+	if ieeeExponent == 0 {
+		e2 = 1 - ryuBias - ryuMantissaBits - 2
+		m2 = ieeeMantissa
+	} else {
+		e2 = int32(ieeeExponent) - ryuBias - ryuMantissaBits - 2
+		m2 = 1<<ryuMantissaBits | ieeeMantissa
+	}
+	acceptBounds := m2&1 == 0
+
+	// Determine the interval of valid decimal representations.
+	mv := 4 * m2
+	var mmShift uint64
+	if ieeeMantissa != 0 || ieeeExponent <= 1 {
+		mmShift = 1
+	}
+
+	// Convert to a decimal power base using 128-bit arithmetic.
+	var vr, vp, vm uint64
+	var e10 int32
+	vmIsTrailingZeros := false
+	vrIsTrailingZeros := false
+	if e2 >= 0 {
+		q := log10Pow2(e2)
+		if e2 > 3 {
+			q--
+		}
+		e10 = q
+		k := ryuPow5InvBitCount + pow5Bits(q) - 1
+		i := -e2 + q + k
+		vr, vp, vm = mulShiftAll64(m2, &ryuPow5InvSplit[q], i, mmShift)
+		if q <= 21 {
+			// Only one of mp, mv, and mm can be a multiple of 5, if any.
+			switch {
+			case mv%5 == 0:
+				vrIsTrailingZeros = multipleOfPowerOf5(mv, q)
+			case acceptBounds:
+				vmIsTrailingZeros = multipleOfPowerOf5(mv-1-mmShift, q)
+			case multipleOfPowerOf5(mv+2, q):
+				vp--
+			}
+		}
+	} else {
+		q := log10Pow5(-e2)
+		if -e2 > 1 {
+			q--
+		}
+		e10 = q + e2
+		i := -e2 - q
+		k := pow5Bits(i) - ryuPow5BitCount
+		j := q - k
+		vr, vp, vm = mulShiftAll64(m2, &ryuPow5Split[i], j, mmShift)
+		switch {
+		case q <= 1:
+			// mv = 4 * m2, so it always has at least two trailing 0 bits.
+			vrIsTrailingZeros = true
+			if acceptBounds {
+				// mm = mv - 1 - mmShift, so it has 1 trailing 0 bit iff mmShift == 1.
+				vmIsTrailingZeros = mmShift == 1
+			} else {
+				// mp = mv + 2, so it always has at least one trailing 0 bit.
+				vp--
+			}
+		case q < 63:
+			vrIsTrailingZeros = mv&(1<<uint(q)-1) == 0
+		}
+	}
+
+	// Find the shortest decimal representation in the interval of valid
+	// representations.
+	var removed int32
+	var lastRemovedDigit uint64
+	var output uint64
+	if vmIsTrailingZeros || vrIsTrailingZeros {
+		// The general case, which happens rarely.
+		for vp/10 > vm/10 {
+			vmIsTrailingZeros = vmIsTrailingZeros && vm%10 == 0
+			vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+			lastRemovedDigit = vr % 10
+			vr /= 10
+			vp /= 10
+			vm /= 10
+			removed++
+		}
+		if vmIsTrailingZeros {
+			for vm%10 == 0 {
+				vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+				lastRemovedDigit = vr % 10
+				vr /= 10
+				vp /= 10
+				vm /= 10
+				removed++
+			}
+		}
+		if vrIsTrailingZeros && lastRemovedDigit == 5 && vr%2 == 0 {
+			// Round to even if the exact number is .....50..0.
+			lastRemovedDigit = 4
+		}
+		output = vr
+		// Take vr + 1 if vr is outside of the bounds or needs rounding up.
+		if vr == vm && (!acceptBounds || !vmIsTrailingZeros) || lastRemovedDigit >= 5 {
+			output++
+		}
+	} else {
+		// The common case.
+		roundUp := false
+		if vp/100 > vm/100 {
+			// Remove two digits at a time.
+			roundUp = vr%100 >= 50
+			vr /= 100
+			vp /= 100
+			vm /= 100
+			removed += 2
+		}
+		for vp/10 > vm/10 {
+			roundUp = vr%10 >= 5
+			vr /= 10
+			vp /= 10
+			vm /= 10
+			removed++
+		}
+		output = vr
+		if vr == vm || roundUp {
+			output++
+		}
+	}
+	return output, e10 + removed
+}
+
+// pow5Bits returns the number of bits in 5^e, or 1 for e == 0.
+func pow5Bits(e int32) int32 {
+	return int32(uint32(e)*1217359>>19) + 1
+}
+
+// log10Pow2 returns floor(log10(2^e)).
+func log10Pow2(e int32) int32 {
+	return int32(uint32(e) * 78913 >> 18)
+}
+
+// log10Pow5 returns floor(log10(5^e)).
+func log10Pow5(e int32) int32 {
+	return int32(uint32(e) * 732923 >> 20)
+}
+
+func multipleOfPowerOf5(v uint64, p int32) bool {
+	var count int32
+	for v%5 == 0 {
+		v /= 5
+		count++
+	}
+	return count >= p
+}
+
+// mulShift64 returns (m * mul) >> j, where mul is a 128-bit {low, high} factor.
+func mulShift64(m uint64, mul *[2]uint64, j int32) uint64 {
+	hi0, _ := bits.Mul64(m, mul[0])
+	hi2, lo2 := bits.Mul64(m, mul[1])
+	lo, carry := bits.Add64(hi0, lo2, 0)
+	hi := hi2 + carry
+	shift := uint(j - 64)
+	return hi<<(64-shift) | lo>>shift
+}
+
+// mulShiftAll64 returns mulShift64 of mv, mp and mm, the middle, upper and lower
+// bounds for m.
+func mulShiftAll64(m uint64, mul *[2]uint64, j int32, mmShift uint64) (vr, vp, vm uint64) {
+	vr = mulShift64(4*m, mul, j)
+	vp = mulShift64(4*m+2, mul, j)
+	vm = mulShift64(4*m-1-mmShift, mul, j)
+	return vr, vp, vm
+}
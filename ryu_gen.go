@@ -0,0 +1,74 @@
+//go:build ignore
+// +build ignore
+
+// ryu_gen.go writes ryu_tables.go, the powers of five used by the Ryū formatter.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"math/big"
+)
+
+const (
+	pow5InvBitCount = 125
+	pow5BitCount    = 125
+	pow5InvEntries  = 342
+	pow5Entries     = 326
+)
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString(`// Code generated by ryu_gen.go. DO NOT EDIT.
+
+//go:build jsonappender_ryu
+// +build jsonappender_ryu
+
+package jsonappender
+
+`)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	writeEntry := func(v *big.Int) {
+		lo := new(big.Int).And(v, mask).Uint64()
+		hi := new(big.Int).Rsh(v, 64).Uint64()
+		fmt.Fprintf(&buf, "\t{%d, %d},\n", lo, hi)
+	}
+
+	buf.WriteString("// ryuPow5InvSplit holds 2^(bitlen(5^i)-1+125) / 5^i + 1 as {low, high} words.\n")
+	fmt.Fprintf(&buf, "var ryuPow5InvSplit = [%d][2]uint64{\n", pow5InvEntries)
+	for i := 0; i < pow5InvEntries; i++ {
+		pow := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+		j := uint(pow.BitLen() - 1 + pow5InvBitCount)
+		inv := new(big.Int).Lsh(big.NewInt(1), j)
+		inv.Div(inv, pow)
+		inv.Add(inv, big.NewInt(1))
+		writeEntry(inv)
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// ryuPow5Split holds 5^i scaled to 125 bits as {low, high} words.\n")
+	fmt.Fprintf(&buf, "var ryuPow5Split = [%d][2]uint64{\n", pow5Entries)
+	for i := 0; i < pow5Entries; i++ {
+		pow := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+		shift := pow.BitLen() - pow5BitCount
+		if shift > 0 {
+			pow.Rsh(pow, uint(shift))
+		} else {
+			pow.Lsh(pow, uint(-shift))
+		}
+		writeEntry(pow)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile("ryu_tables.go", src, 0o600)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !jsonappender_ryu
+// +build !jsonappender_ryu
+
+package jsonappender
+
+// useRyu makes appendFloat format float64 values with appendRyu. It's only set when
+// built with jsonappender_ryu.
+const useRyu = false
+
+// appendRyu is only implemented when built with jsonappender_ryu.
+func appendRyu(_ float64, buf []byte) []byte {
+	return buf
+}
@@ -0,0 +1,65 @@
+//go:build jsonappender_ryu
+// +build jsonappender_ryu
+
+package jsonappender
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestRyu(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("any bits same as encoding/json", prop.ForAll(
+		func(u uint64) bool {
+			f := math.Float64frombits(u)
+			got, err := Float64(f, nil)
+			return matchesEncodingJSON(f, nil, got, err)
+		}, gen.UInt64(),
+	))
+	properties.Property("integers same as encoding/json", prop.ForAll(
+		func(i int64) bool {
+			got, err := Float64(float64(i), nil)
+			return matchesEncodingJSON(float64(i), nil, got, err)
+		}, gen.Int64(),
+	))
+	properties.TestingRun(t)
+
+	for _, f := range []float64{
+		0, math.Copysign(0, -1), 1, -1, 0.1, 0.3, 1.5, 123456789, 9007199254740993,
+		math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, 2.2250738585072014e-308,
+		2.225073858507201e-308, 1e21, 999999999999999900000, 1e-6, 9.999999999999999e-7,
+		1e-7, 1e22, 1e23, 5e-324, 1.7976931348623157e308, 4.940656458412e-324,
+		math.Pi, math.E, 2.98023223876953125e-8, 1 << 53, 1 << 63, 0.000001234,
+	} {
+		want, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Float64(f, nil)
+		if err != nil || string(got) != string(want) {
+			t.Errorf("Float64(%v) = %s, %v, want %s", f, got, err, want)
+		}
+	}
+	for e := -324; e <= 308; e++ {
+		f := math.Pow(10, float64(e))
+		for _, v := range []float64{f, math.Nextafter(f, 0), math.Nextafter(f, math.Inf(1))} {
+			if math.IsInf(v, 0) {
+				continue
+			}
+			want, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Float64(v, nil)
+			if err != nil || string(got) != string(want) {
+				t.Errorf("Float64(%v) = %s, %v, want %s", v, got, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package s3upload streams large json and ndjson exports to object storage with
+// S3-style multipart uploads. A Writer is an io.Writer, so a BufWriter can write to it
+// directly. It doesn't depend on an SDK. Adapt your client to Client instead.
+package s3upload
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// S3's limits on multipart uploads.
+const (
+	// MinPartSize is the smallest part other than the last one.
+	MinPartSize = 5 << 20
+	// MaxParts is the most parts an upload can have.
+	MaxParts = 10000
+)
+
+var (
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("s3upload: writer is closed")
+	// ErrTooManyParts is returned when the output needs more than MaxParts parts. Use a
+	// bigger PartSize.
+	ErrTooManyParts = errors.New("s3upload: too many parts")
+)
+
+// Client makes the multipart upload calls for one object. Its methods may be called
+// from several goroutines at once when Concurrency is more than one.
+type Client interface {
+	CreateMultipartUpload(ctx context.Context) (uploadID string, err error)
+	// UploadPart uploads part number partNumber, counting from 1. body is only valid
+	// until UploadPart returns.
+	UploadPart(ctx context.Context, uploadID string, partNumber int, body []byte) (etag string, err error)
+	// CompleteMultipartUpload is called with the parts in order.
+	CompleteMultipartUpload(ctx context.Context, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, uploadID string) error
+}
+
+// CompletedPart is an uploaded part.
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// Writer uploads everything written to it as one object. The upload is created when
+// the first part is full, and completed by Close. When a write or an upload fails the
+// upload is aborted by Close, so always call Close or Abort.
+type Writer struct {
+	// PartSize is the size of each part but the last. It defaults to, and can't be less
+	// than, MinPartSize.
+	PartSize int
+	// Concurrency is the number of parts uploaded at once. It defaults to 1. Each part
+	// being uploaded holds a buffer of PartSize bytes.
+	Concurrency int
+
+	ctx      context.Context
+	client   Client
+	buf      []byte
+	uploadID string
+	started  bool
+	closed   bool
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	// mu guards the fields below, which upload goroutines write.
+	mu    sync.Mutex
+	parts []CompletedPart
+	free  [][]byte
+	err   error
+}
+
+// NewWriter returns a Writer that uploads with client. ctx is used for every call to
+// client. Set PartSize and Concurrency before the first Write.
+func NewWriter(ctx context.Context, client Client) *Writer {
+	return &Writer{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+// Write implements io.Writer. It blocks while Concurrency parts are being uploaded.
+// Errors from earlier uploads are returned by the next Write.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if err := w.Err(); err != nil {
+		return 0, err
+	}
+	size := w.partSize()
+	n := 0
+	for len(p) > 0 {
+		if w.buf == nil {
+			w.buf = w.getBuf(size)
+		}
+		c := copy(w.buf[len(w.buf):size], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		n += c
+		p = p[c:]
+		if len(w.buf) == size {
+			err := w.uploadBuf()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Err returns the first error from a write or an upload.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close uploads the rest of the output and completes the upload. If anything failed it
+// aborts the upload instead and returns the first error.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.Err() == nil && (w.buf != nil || !w.started) {
+		// S3 needs at least one part, even when it's empty.
+		if w.buf == nil {
+			w.buf = []byte{}
+		}
+		w.uploadBuf() //nolint:errcheck // checked with Err below
+	}
+	w.closed = true
+	w.wg.Wait()
+	if err := w.Err(); err != nil {
+		w.abort() //nolint:errcheck // already failing
+		return err
+	}
+	return w.client.CompleteMultipartUpload(w.ctx, w.uploadID, w.parts)
+}
+
+// Abort abandons the output and aborts the upload. Use it when the export fails partway
+// through.
+func (w *Writer) Abort() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	w.wg.Wait()
+	return w.abort()
+}
+
+func (w *Writer) abort() error {
+	if !w.started || w.uploadID == "" {
+		return nil
+	}
+	return w.client.AbortMultipartUpload(w.ctx, w.uploadID)
+}
+
+func (w *Writer) partSize() int {
+	if w.PartSize < MinPartSize {
+		return MinPartSize
+	}
+	return w.PartSize
+}
+
+func (w *Writer) getBuf(size int) []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.free) > 0 {
+		buf := w.free[len(w.free)-1]
+		w.free = w.free[:len(w.free)-1]
+		return buf[:0]
+	}
+	return make([]byte, 0, size)
+}
+
+func (w *Writer) setErr(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+}
+
+// uploadBuf hands buf to an upload goroutine.
+func (w *Writer) uploadBuf() error {
+	if !w.started {
+		w.started = true
+		id, err := w.client.CreateMultipartUpload(w.ctx)
+		if err != nil {
+			w.setErr(err)
+			return err
+		}
+		w.uploadID = id
+		concurrency := w.Concurrency
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		w.sem = make(chan struct{}, concurrency)
+	}
+	w.mu.Lock()
+	partNumber := len(w.parts) + 1
+	if partNumber > MaxParts {
+		w.mu.Unlock()
+		w.setErr(ErrTooManyParts)
+		return ErrTooManyParts
+	}
+	// parts are filled in by index, so they stay in order however uploads finish
+	w.parts = append(w.parts, CompletedPart{PartNumber: partNumber})
+	w.mu.Unlock()
+
+	buf := w.buf
+	w.buf = nil
+	w.sem <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer func() { <-w.sem }()
+		etag, err := w.client.UploadPart(w.ctx, w.uploadID, partNumber, buf)
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		w.parts[partNumber-1].ETag = etag
+		w.free = append(w.free, buf)
+	}()
+	return w.Err()
+}
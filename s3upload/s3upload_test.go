@@ -0,0 +1,125 @@
+package s3upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+type testClient struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	completed []CompletedPart
+	aborted   bool
+	failPart  int
+}
+
+func (c *testClient) CreateMultipartUpload(context.Context) (string, error) {
+	c.parts = map[int][]byte{}
+	return "upload", nil
+}
+
+func (c *testClient) UploadPart(_ context.Context, _ string, partNumber int, body []byte) (string, error) {
+	if partNumber == c.failPart {
+		return "", errors.New("upload failed")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.parts[partNumber] = append([]byte(nil), body...)
+	return "etag" + strconv.Itoa(partNumber), nil
+}
+
+func (c *testClient) CompleteMultipartUpload(_ context.Context, _ string, parts []CompletedPart) error {
+	c.completed = parts
+	return nil
+}
+
+func (c *testClient) AbortMultipartUpload(context.Context, string) error {
+	c.aborted = true
+	return nil
+}
+
+func (c *testClient) object() []byte {
+	var obj []byte
+	for i, p := range c.completed {
+		if p.PartNumber != i+1 || p.ETag != "etag"+strconv.Itoa(i+1) {
+			return nil
+		}
+		obj = append(obj, c.parts[p.PartNumber]...)
+	}
+	return obj
+}
+
+func TestWriter(t *testing.T) {
+	client := &testClient{}
+	w := NewWriter(context.Background(), client)
+	w.Concurrency = 3
+	bw := jsonappender.NewBufWriter(w)
+	var want bytes.Buffer
+	for i := 0; i < 3000000; i++ {
+		bw.Int64(int64(i))
+		bw.RawByte('\n')
+		want.WriteString(strconv.Itoa(i) + "\n")
+	}
+	bw.Flush()
+	if bw.Error != nil {
+		t.Fatal(bw.Error)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.completed) != 5 {
+		t.Errorf("got %d parts, wanted 5", len(client.completed))
+	}
+	if !bytes.Equal(client.object(), want.Bytes()) {
+		t.Error("uploaded object doesn't match")
+	}
+
+	client = &testClient{}
+	w = NewWriter(context.Background(), client)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.completed) != 1 || len(client.object()) != 0 {
+		t.Errorf("expected one empty part, got %v", client.completed)
+	}
+	if _, err := w.Write([]byte("1")); err != ErrClosed {
+		t.Errorf("got %v, wanted ErrClosed", err)
+	}
+}
+
+func TestWriter_abort(t *testing.T) {
+	client := &testClient{failPart: 1}
+	w := NewWriter(context.Background(), client)
+	chunk := make([]byte, MinPartSize)
+	_, err := w.Write(chunk)
+	if err == nil {
+		_, err = w.Write(chunk)
+	}
+	if err == nil {
+		t.Error("expected the failed upload to fail a later Write")
+	}
+	if err := w.Close(); err == nil {
+		t.Error("expected Close to return the upload error")
+	}
+	if !client.aborted || client.completed != nil {
+		t.Error("expected the upload to be aborted")
+	}
+
+	client = &testClient{}
+	w = NewWriter(context.Background(), client)
+	if _, err := w.Write(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if !client.aborted {
+		t.Error("expected Abort to abort the upload")
+	}
+}
@@ -0,0 +1,434 @@
+package jsonappender
+
+import (
+	"io"
+	"strconv"
+)
+
+// TokenKind identifies the kind of a Token.
+type TokenKind uint8
+
+// Token kinds returned by Scanner.
+const (
+	TokenObjectStart TokenKind = iota + 1
+	TokenObjectEnd
+	TokenArrayStart
+	TokenArrayEnd
+	TokenString
+	TokenNumber
+	TokenTrue
+	TokenFalse
+	TokenNull
+)
+
+var tokenKindNames = [...]string{
+	TokenObjectStart: "object start",
+	TokenObjectEnd:   "object end",
+	TokenArrayStart:  "array start",
+	TokenArrayEnd:    "array end",
+	TokenString:      "string",
+	TokenNumber:      "number",
+	TokenTrue:        "true",
+	TokenFalse:       "false",
+	TokenNull:        "null",
+}
+
+func (k TokenKind) String() string {
+	if int(k) < len(tokenKindNames) && tokenKindNames[k] != "" {
+		return tokenKindNames[k]
+	}
+	return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Token is a single json token. Commas and colons are consumed by the Scanner and never returned.
+type Token struct {
+	Kind TokenKind
+
+	// Raw is the token exactly as it appears in the input, including quotes for strings.
+	// It references the Scanner's input and is only valid as long as that is.
+	Raw []byte
+
+	// Key is true when the token is a string in an object key position.
+	Key bool
+
+	// Offset is the position of the token in the input.
+	Offset int
+}
+
+// SyntaxError describes invalid json found by Scanner.
+type SyntaxError struct {
+	Offset int
+	msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return "jsonappender: " + e.msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+type scanState uint8
+
+const (
+	scanValue        scanState = iota // expecting a top-level value or the value after a colon or comma
+	scanValueOrEnd                    // just after '['
+	scanKeyOrEnd                      // just after '{'
+	scanKey                           // after a comma in an object
+	scanColon                         // after an object key
+	scanCommaOrEnd                    // after a value inside a container
+	scanTopLevelDone                  // after a complete top-level value
+)
+
+// Scanner splits json input into tokens while validating it. The input may contain
+// any number of whitespace separated top-level values, so it also handles ndjson.
+type Scanner struct {
+	data  []byte
+	pos   int
+	stack []byte
+	state scanState
+	err   error
+}
+
+// NewScanner returns a Scanner reading data.
+func NewScanner(data []byte) *Scanner {
+	var s Scanner
+	s.Reset(data)
+	return &s
+}
+
+// Reset resets the Scanner to read data from the beginning.
+func (s *Scanner) Reset(data []byte) {
+	s.data = data
+	s.pos = 0
+	s.stack = s.stack[:0]
+	s.state = scanValue
+	s.err = nil
+}
+
+// Depth returns the number of currently open objects and arrays.
+func (s *Scanner) Depth() int {
+	return len(s.stack)
+}
+
+// Offset returns the position in the input just past the last token returned.
+func (s *Scanner) Offset() int {
+	return s.pos
+}
+
+// Next returns the next token. It returns io.EOF after the last complete top-level value.
+// Any other error is a *SyntaxError and is returned again by subsequent calls.
+func (s *Scanner) Next() (Token, error) {
+	if s.err != nil {
+		return Token{}, s.err
+	}
+	tok, err := s.next()
+	if err != nil {
+		s.err = err
+	}
+	return tok, err
+}
+
+func (s *Scanner) next() (Token, error) {
+	for {
+		s.skipWhitespace()
+		if s.pos == len(s.data) {
+			if s.state == scanTopLevelDone || (s.state == scanValue && len(s.stack) == 0) {
+				return Token{}, io.EOF
+			}
+			return Token{}, s.syntaxError("unexpected end of input")
+		}
+		c := s.data[s.pos]
+		switch s.state {
+		case scanColon:
+			if c != ':' {
+				return Token{}, s.syntaxError("expected colon after object key")
+			}
+			s.pos++
+			s.state = scanValue
+			continue
+		case scanCommaOrEnd:
+			if c == ',' {
+				s.pos++
+				s.state = scanValue
+				if s.stack[len(s.stack)-1] == '{' {
+					s.state = scanKey
+				}
+				continue
+			}
+			if c != ']' && c != '}' {
+				return Token{}, s.syntaxError("expected comma or end of container")
+			}
+			return s.end(c)
+		case scanValueOrEnd:
+			if c == ']' {
+				return s.end(c)
+			}
+		case scanKeyOrEnd:
+			if c == '}' {
+				return s.end(c)
+			}
+			return s.key(c)
+		case scanKey:
+			return s.key(c)
+		}
+		return s.value(c)
+	}
+}
+
+func (s *Scanner) skipWhitespace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *Scanner) key(c byte) (Token, error) {
+	if c != '"' {
+		return Token{}, s.syntaxError("expected string for object key")
+	}
+	tok, err := s.scanString()
+	if err != nil {
+		return tok, err
+	}
+	tok.Key = true
+	s.state = scanColon
+	return tok, nil
+}
+
+func (s *Scanner) end(c byte) (Token, error) {
+	top := s.stack[len(s.stack)-1]
+	if (top == '{') != (c == '}') {
+		return Token{}, s.syntaxError("mismatched " + strconv.QuoteRune(rune(c)))
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	tok := Token{
+		Kind:   TokenArrayEnd,
+		Raw:    s.data[s.pos : s.pos+1],
+		Offset: s.pos,
+	}
+	if c == '}' {
+		tok.Kind = TokenObjectEnd
+	}
+	s.pos++
+	s.afterValue()
+	return tok, nil
+}
+
+func (s *Scanner) afterValue() {
+	if len(s.stack) == 0 {
+		s.state = scanTopLevelDone
+		return
+	}
+	s.state = scanCommaOrEnd
+}
+
+func (s *Scanner) value(c byte) (Token, error) {
+	start := s.pos
+	switch c {
+	case '{', '[':
+		s.stack = append(s.stack, c)
+		s.pos++
+		tok := Token{
+			Kind:   TokenArrayStart,
+			Raw:    s.data[start:s.pos],
+			Offset: start,
+		}
+		s.state = scanValueOrEnd
+		if c == '{' {
+			tok.Kind = TokenObjectStart
+			s.state = scanKeyOrEnd
+		}
+		return tok, nil
+	case '"':
+		tok, err := s.scanString()
+		if err != nil {
+			return tok, err
+		}
+		s.afterValue()
+		return tok, nil
+	case 't':
+		return s.literal("true", TokenTrue)
+	case 'f':
+		return s.literal("false", TokenFalse)
+	case 'n':
+		return s.literal("null", TokenNull)
+	}
+	if c == '-' || (c >= '0' && c <= '9') {
+		return s.scanNumber()
+	}
+	return Token{}, s.syntaxError("invalid character " + strconv.QuoteRune(rune(c)) + " looking for beginning of value")
+}
+
+func (s *Scanner) literal(lit string, kind TokenKind) (Token, error) {
+	start := s.pos
+	if len(s.data)-start < len(lit) || string(s.data[start:start+len(lit)]) != lit {
+		return Token{}, s.syntaxError("invalid literal")
+	}
+	s.pos += len(lit)
+	if !s.atDelimiter() {
+		return Token{}, s.syntaxError("invalid character after " + lit)
+	}
+	s.afterValue()
+	return Token{
+		Kind:   kind,
+		Raw:    s.data[start:s.pos],
+		Offset: start,
+	}, nil
+}
+
+func (s *Scanner) scanString() (Token, error) {
+	start := s.pos
+	i := s.pos + 1
+	for i < len(s.data) {
+		c := s.data[i]
+		switch {
+		case c == '"':
+			s.pos = i + 1
+			return Token{
+				Kind:   TokenString,
+				Raw:    s.data[start:s.pos],
+				Offset: start,
+			}, nil
+		case c == '\\':
+			if i+1 == len(s.data) {
+				s.pos = i
+				return Token{}, s.syntaxError("unexpected end of input in string")
+			}
+			switch s.data[i+1] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				i += 2
+			case 'u':
+				if len(s.data)-i < 6 || !isHex4(s.data[i+2:i+6]) {
+					s.pos = i
+					return Token{}, s.syntaxError("invalid unicode escape in string")
+				}
+				i += 6
+			default:
+				s.pos = i
+				return Token{}, s.syntaxError("invalid escape in string")
+			}
+		case c < 0x20:
+			s.pos = i
+			return Token{}, s.syntaxError("invalid control character in string")
+		default:
+			i++
+		}
+	}
+	s.pos = i
+	return Token{}, s.syntaxError("unexpected end of input in string")
+}
+
+func isHex4(b []byte) bool {
+	for _, c := range b {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Scanner) scanNumber() (Token, error) {
+	start := s.pos
+	n := numberLen(s.data[start:])
+	if n == 0 {
+		return Token{}, s.syntaxError("invalid number")
+	}
+	s.pos += n
+	if !s.atDelimiter() {
+		return Token{}, s.syntaxError("invalid character in number")
+	}
+	s.afterValue()
+	return Token{
+		Kind:   TokenNumber,
+		Raw:    s.data[start:s.pos],
+		Offset: start,
+	}, nil
+}
+
+// numberLen returns the length of the json number at the start of b or 0 if there isn't one.
+func numberLen(b []byte) int {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && b[i] >= '1' && b[i] <= '9':
+		i = digitsEnd(b, i+1)
+	default:
+		return 0
+	}
+	if i < len(b) && b[i] == '.' {
+		j := digitsEnd(b, i+1)
+		if j == i+1 {
+			return 0
+		}
+		i = j
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		j := digitsEnd(b, i)
+		if j == i {
+			return 0
+		}
+		i = j
+	}
+	return i
+}
+
+// atDelimiter reports whether the input at the current position can end a number or literal.
+func (s *Scanner) atDelimiter() bool {
+	if s.pos == len(s.data) {
+		return true
+	}
+	switch s.data[s.pos] {
+	case ' ', '\t', '\n', '\r', ',', ']', '}':
+		return true
+	}
+	return false
+}
+
+func digitsEnd(b []byte, i int) int {
+	for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+func (s *Scanner) syntaxError(msg string) error {
+	return &SyntaxError{
+		Offset: s.pos,
+		msg:    msg,
+	}
+}
+
+// validateValue returns a *SyntaxError unless data is exactly one json value, with
+// optional whitespace around it.
+func validateValue(data []byte) error {
+	var s Scanner
+	s.Reset(data)
+	_, err := s.Next()
+	if err == io.EOF {
+		return s.syntaxError("expected a value")
+	}
+	for err == nil && s.Depth() > 0 {
+		_, err = s.Next()
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.Next()
+	if err != io.EOF {
+		return &SyntaxError{
+			Offset: s.Offset(),
+			msg:    "unexpected data after value",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// scanAll returns the input re-assembled from its tokens with commas and colons restored.
+func scanAll(data []byte) ([]byte, int, error) {
+	sc := NewScanner(data)
+	var out []byte
+	values := 0
+	var prev Token
+	for i := 0; ; i++ {
+		tok, err := sc.Next()
+		if err == io.EOF {
+			return out, values, nil
+		}
+		if err != nil {
+			return out, values, err
+		}
+		if sc.Depth() == 0 {
+			values++
+		}
+		switch {
+		case i == 0, tok.Kind == TokenObjectEnd, tok.Kind == TokenArrayEnd:
+		case prev.Key:
+			out = append(out, ':')
+		case prev.Kind != TokenObjectStart && prev.Kind != TokenArrayStart && sc.Depth() > 0:
+			out = append(out, ',')
+		}
+		out = append(out, tok.Raw...)
+		prev = tok
+	}
+}
+
+func TestScanner(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("round trips encoding/json output", prop.ForAll(
+		func(m map[string]string, f []float64, b bool) bool {
+			val := []interface{}{m, f, b, nil, map[string]interface{}{}, []interface{}{}}
+			want, err := json.MarshalIndent(val, "", "  ")
+			if err != nil {
+				return false
+			}
+			got, n, err := scanAll(want)
+			if err != nil || n != 1 {
+				return false
+			}
+			var compact bytes.Buffer
+			err = json.Compact(&compact, want)
+			return err == nil && compact.String() == string(got)
+		},
+		gen.MapOf(gen.AnyString(), gen.AnyString()),
+		gen.SliceOf(gen.Float64Range(-1e30, 1e30)),
+		gen.Bool(),
+	))
+	properties.Property("agrees with json.Valid", prop.ForAll(
+		func(s string) bool {
+			_, n, err := scanAll([]byte(s))
+			return json.Valid([]byte(s)) == (err == nil && n == 1)
+		},
+		gen.OneGenOf(
+			gen.AnyString(),
+			gen.RegexMatch(`[\[\]{}:, "a-c0-9.eE+\-\\]{0,12}`),
+		),
+	))
+	properties.TestingRun(t)
+}
+
+func TestScanner_values(t *testing.T) {
+	for _, td := range []struct {
+		input  string
+		values int
+		err    bool
+	}{
+		{input: "", values: 0},
+		{input: " \n", values: 0},
+		{input: `{"a":1}{"b":2}`, values: 2},
+		{input: "1\n2\n\"x\"\n[]\n", values: 4},
+		{input: "0123", err: true},
+		{input: "truefalse", err: true},
+		{input: `{"a":1,}`, err: true},
+		{input: `[1,]`, err: true},
+		{input: `[1}`, err: true},
+		{input: `{"a"}`, err: true},
+		{input: `{1:2}`, err: true},
+		{input: `"\x"`, err: true},
+		{input: `"\u12"`, err: true},
+		{input: "\"\t\"", err: true},
+		{input: `[`, err: true},
+		{input: `-`, err: true},
+		{input: `1.`, err: true},
+		{input: `1e`, err: true},
+	} {
+		_, n, err := scanAll([]byte(td.input))
+		if td.err {
+			if err == nil {
+				t.Errorf("%q: expected error", td.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", td.input, err)
+		}
+		if n != td.values {
+			t.Errorf("%q: got %d values, wanted %d", td.input, n, td.values)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// Package sentry builds Sentry event payloads. Sections like tags, exceptions and
+// breadcrumbs are streamed into the payload as they're added rather than collected
+// into nested maps first.
+package sentry
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// EventID is a Sentry event id. It's written as 32 hex characters.
+type EventID [16]byte
+
+// NewEventID returns a random EventID.
+func NewEventID() (EventID, error) {
+	var id EventID
+	_, err := rand.Read(id[:])
+	return id, err
+}
+
+// Level is the severity of an event or breadcrumb.
+type Level string
+
+// Levels Sentry understands.
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+// Exception is one entry of an event's exception list.
+type Exception struct {
+	Type   string
+	Value  string
+	Module string
+}
+
+// Frame is one stack frame. Sentry wants frames ordered from the outermost call to the
+// innermost. Empty fields are omitted.
+type Frame struct {
+	Function string
+	Module   string
+	Filename string
+	AbsPath  string
+	Lineno   int
+	InApp    bool
+}
+
+// Breadcrumb is an event that led up to the reported one. Empty fields are omitted.
+type Breadcrumb struct {
+	Time     time.Time
+	Type     string
+	Category string
+	Message  string
+	Level    Level
+	Data     []Field
+}
+
+// Field is a name and any value jsonappender.Value accepts.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// ErrSectionReopened is the error from Finish when calls for one section, such as
+// Tag, were interleaved with calls for another. Each section has to be written in one
+// go.
+var ErrSectionReopened = errors.New("sentry: section was already written")
+
+// ErrFrameWithoutException is the error from Finish when Frame was called before any
+// Exception.
+var ErrFrameWithoutException = errors.New("sentry: Frame called without an Exception")
+
+type section uint8
+
+const (
+	sectionNone section = 0
+	sectionTags section = 1 << iota
+	sectionExtra
+	sectionException
+	sectionBreadcrumbs
+)
+
+// EventBuilder builds one event at a time. Start an event with Reset, add to it, then
+// call Finish. Errors are held until Finish.
+//
+// An EventBuilder is not safe for concurrent use.
+type EventBuilder struct {
+	buf        []byte
+	section    section
+	written    section
+	framesOpen bool
+	err        error
+}
+
+// Reset starts a new event in buf.
+func (b *EventBuilder) Reset(id EventID, ts time.Time, level Level, buf []byte) {
+	b.section = sectionNone
+	b.written = sectionNone
+	b.framesOpen = false
+	b.err = nil
+	buf = append(buf, `{"event_id":"`...)
+	buf = appendHex(id[:], buf)
+	buf = append(buf, `","platform":"go","level":`...)
+	buf = jsonappender.String(string(level), buf)
+	buf = append(buf, `,"timestamp":`...)
+	b.buf, b.err = jsonappender.Time(ts, buf)
+	if b.err != nil {
+		b.buf = buf
+	}
+}
+
+// Message sets the event's message.
+func (b *EventBuilder) Message(msg string) {
+	b.stringMember("message", msg)
+}
+
+// Logger sets the name of the logger that reported the event.
+func (b *EventBuilder) Logger(name string) {
+	b.stringMember("logger", name)
+}
+
+// Release sets the release the event happened in.
+func (b *EventBuilder) Release(release string) {
+	b.stringMember("release", release)
+}
+
+// Environment sets the environment the event happened in.
+func (b *EventBuilder) Environment(env string) {
+	b.stringMember("environment", env)
+}
+
+// ServerName sets the host the event happened on.
+func (b *EventBuilder) ServerName(name string) {
+	b.stringMember("server_name", name)
+}
+
+func (b *EventBuilder) stringMember(name, value string) {
+	if b.err != nil {
+		return
+	}
+	b.closeSection()
+	b.buf = append(b.buf, ',')
+	b.buf = jsonappender.FieldName(name, b.buf)
+	b.buf = jsonappender.String(value, b.buf)
+}
+
+// Tag adds a tag.
+func (b *EventBuilder) Tag(key, value string) {
+	if !b.enter(sectionTags, `"tags":{`) {
+		return
+	}
+	b.buf = jsonappender.FieldName(key, b.buf)
+	b.buf = jsonappender.String(value, b.buf)
+}
+
+// Extra adds arbitrary extra data.
+func (b *EventBuilder) Extra(name string, value interface{}) {
+	if !b.enter(sectionExtra, `"extra":{`) {
+		return
+	}
+	b.buf = jsonappender.FieldName(name, b.buf)
+	b.buf, b.err = jsonappender.Value(value, b.buf)
+}
+
+// Exception adds an exception. Frames added after it belong to its stack trace.
+func (b *EventBuilder) Exception(e Exception) {
+	if b.section == sectionException {
+		b.closeException()
+	}
+	if !b.enter(sectionException, `"exception":{"values":[`) {
+		return
+	}
+	b.buf = append(b.buf, `{"type":`...)
+	b.buf = jsonappender.String(e.Type, b.buf)
+	b.buf = append(b.buf, `,"value":`...)
+	b.buf = jsonappender.String(e.Value, b.buf)
+	if e.Module != "" {
+		b.buf = append(b.buf, `,"module":`...)
+		b.buf = jsonappender.String(e.Module, b.buf)
+	}
+}
+
+// Frame adds a frame to the stack trace of the last Exception.
+func (b *EventBuilder) Frame(f Frame) {
+	if b.err != nil {
+		return
+	}
+	if b.section != sectionException {
+		b.err = ErrFrameWithoutException
+		return
+	}
+	if b.framesOpen {
+		b.buf = append(b.buf, ',')
+	} else {
+		b.buf = append(b.buf, `,"stacktrace":{"frames":[`...)
+		b.framesOpen = true
+	}
+	b.buf = append(b.buf, '{')
+	comma := false
+	for _, m := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "function", value: f.Function},
+		{name: "module", value: f.Module},
+		{name: "filename", value: f.Filename},
+		{name: "abs_path", value: f.AbsPath},
+	} {
+		if m.value == "" {
+			continue
+		}
+		if comma {
+			b.buf = append(b.buf, ',')
+		}
+		comma = true
+		b.buf = jsonappender.FieldName(m.name, b.buf)
+		b.buf = jsonappender.String(m.value, b.buf)
+	}
+	if f.Lineno != 0 {
+		if comma {
+			b.buf = append(b.buf, ',')
+		}
+		comma = true
+		b.buf = append(b.buf, `"lineno":`...)
+		b.buf = jsonappender.Int64(int64(f.Lineno), b.buf)
+	}
+	if comma {
+		b.buf = append(b.buf, ',')
+	}
+	b.buf = append(b.buf, `"in_app":`...)
+	b.buf = jsonappender.Bool(f.InApp, b.buf)
+	b.buf = append(b.buf, '}')
+}
+
+// Breadcrumb adds a breadcrumb.
+func (b *EventBuilder) Breadcrumb(bc Breadcrumb) {
+	if !b.enter(sectionBreadcrumbs, `"breadcrumbs":{"values":[`) {
+		return
+	}
+	b.buf = append(b.buf, '{')
+	comma := false
+	if !bc.Time.IsZero() {
+		b.buf = append(b.buf, `"timestamp":`...)
+		buf, err := jsonappender.Time(bc.Time, b.buf)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.buf = buf
+		comma = true
+	}
+	for _, m := range [...]struct {
+		name  string
+		value string
+	}{
+		{name: "type", value: bc.Type},
+		{name: "category", value: bc.Category},
+		{name: "message", value: bc.Message},
+		{name: "level", value: string(bc.Level)},
+	} {
+		if m.value == "" {
+			continue
+		}
+		if comma {
+			b.buf = append(b.buf, ',')
+		}
+		comma = true
+		b.buf = jsonappender.FieldName(m.name, b.buf)
+		b.buf = jsonappender.String(m.value, b.buf)
+	}
+	if len(bc.Data) > 0 {
+		if comma {
+			b.buf = append(b.buf, ',')
+		}
+		b.buf = append(b.buf, `"data":{`...)
+		for i, f := range bc.Data {
+			if i > 0 {
+				b.buf = append(b.buf, ',')
+			}
+			b.buf = jsonappender.FieldName(f.Name, b.buf)
+			b.buf, b.err = jsonappender.Value(f.Value, b.buf)
+			if b.err != nil {
+				return
+			}
+		}
+		b.buf = append(b.buf, '}')
+	}
+	b.buf = append(b.buf, '}')
+}
+
+// Finish completes the event and returns the buffer passed to Reset with the event
+// appended.
+func (b *EventBuilder) Finish() ([]byte, error) {
+	if b.err != nil {
+		return b.buf, b.err
+	}
+	b.closeSection()
+	b.buf = append(b.buf, '}')
+	return b.buf, nil
+}
+
+// enter makes sec the current section, writing header when it starts. It reports
+// whether the caller should go ahead and write its member.
+func (b *EventBuilder) enter(sec section, header string) bool {
+	if b.err != nil {
+		return false
+	}
+	if b.section == sec {
+		if sec != sectionException {
+			b.buf = append(b.buf, ',')
+		}
+		return true
+	}
+	b.closeSection()
+	if b.written&sec != 0 {
+		b.err = ErrSectionReopened
+		return false
+	}
+	b.written |= sec
+	b.section = sec
+	b.buf = append(b.buf, ',')
+	b.buf = append(b.buf, header...)
+	return true
+}
+
+// closeException closes the current exception object so another can follow.
+func (b *EventBuilder) closeException() {
+	if b.framesOpen {
+		b.buf = append(b.buf, ']', '}')
+		b.framesOpen = false
+	}
+	b.buf = append(b.buf, '}', ',')
+}
+
+func (b *EventBuilder) closeSection() {
+	switch b.section {
+	case sectionTags, sectionExtra:
+		b.buf = append(b.buf, '}')
+	case sectionException:
+		if b.framesOpen {
+			b.buf = append(b.buf, ']', '}')
+			b.framesOpen = false
+		}
+		b.buf = append(b.buf, '}', ']', '}')
+	case sectionBreadcrumbs:
+		b.buf = append(b.buf, ']', '}')
+	}
+	b.section = sectionNone
+}
+
+func appendHex(b, buf []byte) []byte {
+	const digits = "0123456789abcdef"
+	for _, c := range b {
+		buf = append(buf, digits[c>>4], digits[c&0xF])
+	}
+	return buf
+}
@@ -0,0 +1,83 @@
+package sentry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEventBuilder(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var b EventBuilder
+	b.Reset(EventID{0xab, 1}, ts, LevelError, []byte("x"))
+	b.Message("boom")
+	b.Tag("region", "eu")
+	b.Tag("os", "linux")
+	b.Exception(Exception{Type: "*errors.errorString", Value: "wrapped"})
+	b.Exception(Exception{Type: "*os.PathError", Value: "open x", Module: "os"})
+	b.Frame(Frame{Function: "main", Module: "main", Filename: "main.go", Lineno: 10, InApp: true})
+	b.Frame(Frame{Function: "Open", Module: "os"})
+	b.Breadcrumb(Breadcrumb{Time: ts, Category: "http", Message: "GET /", Data: []Field{{Name: "status", Value: 200}}})
+	b.Breadcrumb(Breadcrumb{Message: "second", Level: LevelInfo})
+	b.Extra("attempt", 3)
+	b.Release("1.0")
+	got, err := b.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `x{"event_id":"ab010000000000000000000000000000","platform":"go","level":"error","timestamp":"2020-01-02T03:04:05Z",` +
+		`"message":"boom","tags":{"region":"eu","os":"linux"},` +
+		`"exception":{"values":[{"type":"*errors.errorString","value":"wrapped"},` +
+		`{"type":"*os.PathError","value":"open x","module":"os","stacktrace":{"frames":[` +
+		`{"function":"main","module":"main","filename":"main.go","lineno":10,"in_app":true},` +
+		`{"function":"Open","module":"os","in_app":false}]}}]},` +
+		`"breadcrumbs":{"values":[{"timestamp":"2020-01-02T03:04:05Z","category":"http","message":"GET /","data":{"status":200}},` +
+		`{"message":"second","level":"info"}]},` +
+		`"extra":{"attempt":3},"release":"1.0"}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !json.Valid(got[1:]) {
+		t.Error("invalid json")
+	}
+
+	b.Reset(EventID{}, ts, LevelInfo, got[:0])
+	got, err = b.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"event_id":"00000000000000000000000000000000","platform":"go","level":"info","timestamp":"2020-01-02T03:04:05Z"}`; string(got) != want {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestEventBuilder_errors(t *testing.T) {
+	var b EventBuilder
+	b.Reset(EventID{}, time.Now(), LevelError, nil)
+	b.Tag("a", "b")
+	b.Message("m")
+	b.Tag("c", "d")
+	if _, err := b.Finish(); err != ErrSectionReopened {
+		t.Errorf("got error %v", err)
+	}
+
+	b.Reset(EventID{}, time.Now(), LevelError, nil)
+	b.Frame(Frame{Function: "f"})
+	if _, err := b.Finish(); err != ErrFrameWithoutException {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestNewEventID(t *testing.T) {
+	a, err := NewEventID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewEventID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("expected different ids")
+	}
+}
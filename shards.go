@@ -0,0 +1,181 @@
+package jsonappender
+
+import "sync"
+
+// Shards splits the encoding of one array or object between goroutines. Each Shard
+// collects elements or members from a single goroutine, and Shards stitches them
+// together in shard order so the output is deterministic.
+type Shards struct {
+	shards []Shard
+}
+
+// Shard holds the encoded fragments from one goroutine. A Shard is not safe for
+// concurrent use, but different shards can be used concurrently.
+type Shard struct {
+	buf []byte
+	n   int
+	err error
+}
+
+// NewShards returns Shards with n shards.
+func NewShards(n int) *Shards {
+	return &Shards{
+		shards: make([]Shard, n),
+	}
+}
+
+// Len returns the number of shards.
+func (s *Shards) Len() int {
+	return len(s.shards)
+}
+
+// Shard returns the i'th shard.
+func (s *Shards) Shard(i int) *Shard {
+	return &s.shards[i]
+}
+
+// Reset empties all shards while keeping their buffers for reuse.
+func (s *Shards) Reset() {
+	for i := range s.shards {
+		s.shards[i].Reset()
+	}
+}
+
+// Run calls fn for each shard in its own goroutine and waits for them to finish. It
+// returns the error of the lowest numbered shard that failed.
+func (s *Shards) Run(fn func(i int, sh *Shard) error) error {
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	wg.Add(len(s.shards))
+	for i := range s.shards {
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i, &s.shards[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return s.err()
+}
+
+func (s *Shards) err() error {
+	for i := range s.shards {
+		if s.shards[i].err != nil {
+			return s.shards[i].err
+		}
+	}
+	return nil
+}
+
+// AppendArray appends an array of every element in every shard.
+func (s *Shards) AppendArray(buf []byte) ([]byte, error) {
+	return s.appendJoined('[', ']', buf)
+}
+
+// AppendObject appends an object of every member in every shard.
+func (s *Shards) AppendObject(buf []byte) ([]byte, error) {
+	return s.appendJoined('{', '}', buf)
+}
+
+func (s *Shards) appendJoined(open, end byte, buf []byte) ([]byte, error) {
+	err := s.err()
+	if err != nil {
+		return buf, err
+	}
+	buf = append(buf, open)
+	comma := false
+	for i := range s.shards {
+		sh := &s.shards[i]
+		if sh.n == 0 {
+			continue
+		}
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = append(buf, sh.buf...)
+	}
+	return append(buf, end), nil
+}
+
+// ShardedArray writes an array of every element in every shard
+func (bw *BufWriter) ShardedArray(s *Shards) {
+	bw.writeShards('[', ']', s)
+}
+
+// ShardedObject writes an object of every member in every shard
+func (bw *BufWriter) ShardedObject(s *Shards) {
+	bw.writeShards('{', '}', s)
+}
+
+func (bw *BufWriter) writeShards(open, end byte, s *Shards) {
+	if bw.Error != nil {
+		return
+	}
+	bw.Error = s.err()
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.RawByte(open)
+	comma := false
+	for i := range s.shards {
+		sh := &s.shards[i]
+		if sh.n == 0 {
+			continue
+		}
+		if comma {
+			bw.RawByte(',')
+		}
+		comma = true
+		bw.Raw(sh.buf)
+	}
+	bw.RawByte(end)
+}
+
+// Reset empties the shard while keeping its buffer for reuse.
+func (sh *Shard) Reset() {
+	sh.buf = sh.buf[:0]
+	sh.n = 0
+	sh.err = nil
+}
+
+// Err returns the first error encountered by the shard.
+func (sh *Shard) Err() error {
+	return sh.err
+}
+
+// Len returns the number of elements or members in the shard.
+func (sh *Shard) Len() int {
+	return sh.n
+}
+
+// Value adds an array element.
+func (sh *Shard) Value(val interface{}) {
+	if sh.err != nil {
+		return
+	}
+	sh.comma()
+	sh.buf, sh.err = Value(val, sh.buf)
+}
+
+// Field adds an object member.
+func (sh *Shard) Field(name string, val interface{}) {
+	if sh.err != nil {
+		return
+	}
+	sh.comma()
+	sh.buf = FieldName(name, sh.buf)
+	sh.buf, sh.err = Value(val, sh.buf)
+}
+
+func (sh *Shard) comma() {
+	if sh.n > 0 {
+		sh.buf = append(sh.buf, ',')
+	}
+	sh.n++
+}
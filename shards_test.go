@@ -0,0 +1,109 @@
+package jsonappender
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestShards(t *testing.T) {
+	shards := NewShards(4)
+	err := shards.Run(func(i int, sh *Shard) error {
+		if i == 2 {
+			// empty shards are skipped
+			return nil
+		}
+		for j := 0; j < 100; j++ {
+			sh.Value(i*100 + j)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []interface{}
+	for i := 0; i < 400; i++ {
+		if i/100 != 2 {
+			want = append(want, i)
+		}
+	}
+	wantBytes, err := Array(want, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := shards.AppendArray(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(wantBytes) {
+		t.Errorf("got %s", got)
+	}
+
+	var buf bytes.Buffer
+	bw := NewBufWriter(&buf)
+	bw.ShardedArray(shards)
+	err = bw.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(wantBytes) {
+		t.Errorf("got %s", buf.String())
+	}
+}
+
+func TestShards_object(t *testing.T) {
+	shards := NewShards(3)
+	err := shards.Run(func(i int, sh *Shard) error {
+		sh.Field("a"+strconv.Itoa(i), i)
+		sh.Field("b"+strconv.Itoa(i), true)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := shards.AppendObject(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"a0":0,"b0":true,"a1":1,"b1":true,"a2":2,"b2":true}` {
+		t.Errorf("got %s", got)
+	}
+
+	shards.Reset()
+	got, err = shards.AppendObject(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestShards_errors(t *testing.T) {
+	wantErr := errors.New("fail")
+	shards := NewShards(3)
+	err := shards.Run(func(i int, sh *Shard) error {
+		if i > 0 {
+			return wantErr
+		}
+		return nil
+	})
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+
+	shards.Reset()
+	shards.Shard(1).Value(AppendFunc(func(buf []byte) ([]byte, error) {
+		return buf, wantErr
+	}))
+	_, err = shards.AppendArray(nil)
+	if err != wantErr {
+		t.Errorf("got error %v", err)
+	}
+	bw := NewBufWriter(&bytes.Buffer{})
+	bw.ShardedArray(shards)
+	if bw.Error != wantErr {
+		t.Errorf("got error %v", bw.Error)
+	}
+}
@@ -0,0 +1,101 @@
+package jsonappender
+
+import (
+	"sort"
+	"sync"
+)
+
+// maxPooledKeys keeps the key slices of unusually large maps from being held by
+// sortedKeysPool.
+const maxPooledKeys = 1024
+
+var sortedKeysPool = sync.Pool{
+	New: func() interface{} {
+		k := make([]string, 0, 16)
+		return &k
+	},
+}
+
+// SortedObject is like Object but writes the members sorted by name, so the same map
+// always gives the same output. Maps in mp's values, directly or in []interface{}
+// values, are sorted too.
+func SortedObject(mp map[string]interface{}, buf []byte) ([]byte, error) {
+	kp := sortedKeysPool.Get().(*[]string)
+	buf, keys, err := appendSortedObject(mp, (*kp)[:0], buf)
+	putSortedKeys(kp, keys)
+	return buf, err
+}
+
+// putSortedKeys returns keys, taken from sortedKeysPool as kp, to the pool. keys has
+// to be empty.
+func putSortedKeys(kp *[]string, keys []string) {
+	if cap(keys) <= maxPooledKeys {
+		*kp = keys
+		sortedKeysPool.Put(kp)
+	}
+}
+
+// SortedObject writes an object value with its members sorted by name like
+// SortedObject.
+func (bw *BufWriter) SortedObject(mp map[string]interface{}) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.sortKeys, bw.Error = appendSortedObject(mp, bw.sortKeys[:0], bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// appendSortedObject appends mp sorted using keys, which it returns emptied so it can
+// be reused. Nested maps use keys past the ones of their parent.
+func appendSortedObject(mp map[string]interface{}, keys []string, buf []byte) ([]byte, []string, error) {
+	start := len(keys)
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys[start:])
+	buf = append(buf, '{')
+	var err error
+	for i := start; i < len(keys); i++ {
+		if i > start {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(keys[i], buf)
+		buf, keys, err = appendSortedValue(mp[keys[i]], keys, buf)
+		if err != nil {
+			break
+		}
+	}
+	for i := start; i < len(keys); i++ {
+		keys[i] = ""
+	}
+	if err != nil {
+		return buf, keys[:start], err
+	}
+	return append(buf, '}'), keys[:start], nil
+}
+
+func appendSortedValue(val interface{}, keys []string, buf []byte) ([]byte, []string, error) {
+	var err error
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return appendSortedObject(v, keys, buf)
+	case []interface{}:
+		buf = append(buf, '[')
+		for i := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf, keys, err = appendSortedValue(v[i], keys, buf)
+			if err != nil {
+				return buf, keys, err
+			}
+		}
+		return append(buf, ']'), keys, nil
+	}
+	buf, err = Value(val, buf)
+	return buf, keys, err
+}
@@ -0,0 +1,28 @@
+package jsonappender
+
+import "testing"
+
+func TestSortedObject(t *testing.T) {
+	mp := map[string]interface{}{
+		"b": 1,
+		"a": []interface{}{map[string]interface{}{"y": true, "x": nil}},
+		"c": map[string]interface{}{"e": "f", "d": map[string]interface{}{}},
+	}
+	want := `{"a":[{"x":null,"y":true}],"b":1,"c":{"d":{},"e":"f"}}`
+	for i := 0; i < 10; i++ {
+		got, err := SortedObject(mp, nil)
+		if err != nil || string(got) != want {
+			t.Fatalf("got %s, %v", got, err)
+		}
+	}
+	bw := &BufWriter{}
+	bw.SortedObject(mp)
+	bw.RawByte(' ')
+	bw.SortedObject(map[string]interface{}{"z": 1, "a": 2})
+	if got := string(bw.TakeBuffer()); got != want+` {"a":2,"z":1}` {
+		t.Errorf("got %s", got)
+	}
+	if _, err := SortedObject(map[string]interface{}{"a": make(chan int)}, nil); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,199 @@
+package jsonappender
+
+import "time"
+
+// SparseObject writes the members of an object to a BufWriter, leaving out members
+// whose value is the zero value of its type, an empty collection, or a default
+// registered with SetDefault. It takes care of the commas between members. Call Begin
+// before the first member and End after the last.
+type SparseObject struct {
+	bw       *BufWriter
+	defaults map[string]interface{}
+	members  int
+}
+
+// NewSparseObject returns a SparseObject that writes to bw.
+func NewSparseObject(bw *BufWriter) *SparseObject {
+	return &SparseObject{
+		bw: bw,
+	}
+}
+
+// SetDefault registers val as the default for the member name, so it's left out when it
+// has that value. val has to be a string, int64, uint64, float64 or bool, matching the
+// method the member is written with. Defaults are kept between objects.
+func (o *SparseObject) SetDefault(name string, val interface{}) {
+	if o.defaults == nil {
+		o.defaults = map[string]interface{}{}
+	}
+	o.defaults[name] = val
+}
+
+// Begin starts an object.
+func (o *SparseObject) Begin() {
+	o.members = 0
+	o.bw.valueComma()
+	o.bw.RawByte('{')
+}
+
+// End ends the object and returns the BufWriter's error.
+func (o *SparseObject) End() error {
+	o.bw.RawByte('}')
+	return o.bw.Error
+}
+
+// Members returns the number of members written to the current object.
+func (o *SparseObject) Members() int {
+	return o.members
+}
+
+func (o *SparseObject) name(name string) {
+	if o.members > 0 {
+		o.bw.RawByte(',')
+	}
+	o.members++
+	o.bw.FieldName(name)
+}
+
+// isDefault is only called when there are defaults so val isn't boxed otherwise.
+func (o *SparseObject) isDefault(name string, val interface{}) bool {
+	d, ok := o.defaults[name]
+	return ok && d == val
+}
+
+// String writes a string member unless val is "" or the default.
+func (o *SparseObject) String(name, val string) {
+	if val == "" || o.defaults != nil && o.isDefault(name, val) {
+		return
+	}
+	o.name(name)
+	o.bw.String(val)
+}
+
+// Int64 writes an int64 member unless val is 0 or the default.
+func (o *SparseObject) Int64(name string, val int64) {
+	if val == 0 || o.defaults != nil && o.isDefault(name, val) {
+		return
+	}
+	o.name(name)
+	o.bw.Int64(val)
+}
+
+// Uint64 writes a uint64 member unless val is 0 or the default.
+func (o *SparseObject) Uint64(name string, val uint64) {
+	if val == 0 || o.defaults != nil && o.isDefault(name, val) {
+		return
+	}
+	o.name(name)
+	o.bw.Uint64(val)
+}
+
+// Float64 writes a float64 member unless val is 0 or the default.
+func (o *SparseObject) Float64(name string, val float64) {
+	if val == 0 || o.defaults != nil && o.isDefault(name, val) {
+		return
+	}
+	o.name(name)
+	o.bw.Float64(val)
+}
+
+// Bool writes a bool member unless val is false or the default.
+func (o *SparseObject) Bool(name string, val bool) {
+	if !val || o.defaults != nil && o.isDefault(name, val) {
+		return
+	}
+	o.name(name)
+	o.bw.Bool(val)
+}
+
+// Time writes a time member unless t is the zero time.
+func (o *SparseObject) Time(name string, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	o.name(name)
+	o.bw.Time(t)
+}
+
+// Int64Array writes an int64 array member unless vals is empty.
+func (o *SparseObject) Int64Array(name string, vals []int64) {
+	if len(vals) == 0 {
+		return
+	}
+	o.name(name)
+	o.bw.Int64Array(vals)
+}
+
+// Float64Array writes a float64 array member unless vals is empty.
+func (o *SparseObject) Float64Array(name string, vals []float64) {
+	if len(vals) == 0 {
+		return
+	}
+	o.name(name)
+	o.bw.Float64Array(vals)
+}
+
+// Object writes an object member unless mp is empty.
+func (o *SparseObject) Object(name string, mp map[string]interface{}) {
+	if len(mp) == 0 {
+		return
+	}
+	o.name(name)
+	o.bw.Object(mp)
+}
+
+// Array writes an array member unless slice is empty.
+func (o *SparseObject) Array(name string, slice []interface{}) {
+	if len(slice) == 0 {
+		return
+	}
+	o.name(name)
+	o.bw.Array(slice)
+}
+
+// Value writes a member with any value unless it's nil, an empty value of a type
+// SparseObject has a method for, or the default.
+func (o *SparseObject) Value(name string, val interface{}) {
+	switch v := val.(type) {
+	case nil:
+		return
+	case string:
+		o.String(name, v)
+		return
+	case int64:
+		o.Int64(name, v)
+		return
+	case int:
+		o.Int64(name, int64(v))
+		return
+	case uint64:
+		o.Uint64(name, v)
+		return
+	case uint:
+		o.Uint64(name, uint64(v))
+		return
+	case float64:
+		o.Float64(name, v)
+		return
+	case bool:
+		o.Bool(name, v)
+		return
+	case time.Time:
+		o.Time(name, v)
+		return
+	case []int64:
+		o.Int64Array(name, v)
+		return
+	case []float64:
+		o.Float64Array(name, v)
+		return
+	case map[string]interface{}:
+		o.Object(name, v)
+		return
+	case []interface{}:
+		o.Array(name, v)
+		return
+	}
+	o.name(name)
+	o.bw.Value(val)
+}
@@ -0,0 +1,66 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSparseObject(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	o := NewSparseObject(bw)
+	o.SetDefault("status", "ok")
+	o.SetDefault("retries", int64(3))
+
+	o.Begin()
+	o.String("status", "ok")
+	o.String("empty", "")
+	o.Int64("retries", 3)
+	o.Int64("count", 0)
+	o.Uint64("bytes", 0)
+	o.Float64("ratio", 0)
+	o.Bool("cached", false)
+	o.Time("start", time.Time{})
+	o.Int64Array("ids", nil)
+	o.Float64Array("weights", []float64{})
+	o.Object("labels", map[string]interface{}{})
+	o.Array("tags", nil)
+	o.Value("extra", nil)
+	o.Value("n", 0)
+	if o.Members() != 0 {
+		t.Errorf("expected no members, got %d", o.Members())
+	}
+	if err := o.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Begin()
+	o.String("status", "degraded")
+	o.Int64("retries", 4)
+	o.Bool("cached", true)
+	o.Float64Array("weights", []float64{0.5})
+	o.Value("labels", map[string]interface{}{"a": "b"})
+	o.Value("other", AppendFunc(func(buf []byte) ([]byte, error) {
+		return append(buf, "{}"...), nil
+	}))
+	if err := o.End(); err != nil {
+		t.Fatal(err)
+	}
+	bw.Flush()
+	const want = `{}{"status":"degraded","retries":4,"cached":true,"weights":[0.5],"labels":{"a":"b"},"other":{}}`
+	if out.String() != want {
+		t.Errorf("got  %s\nwant %s", out.String(), want)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		o := SparseObject{bw: bw}
+		o.Begin()
+		o.String("a", "")
+		o.Int64("b", 1)
+		o.End()
+	})
+	if allocs > 0 {
+		t.Errorf("got %v allocs, wanted none", allocs)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import "database/sql"
+
+// SQLNullString appends v.String, or null when v isn't Valid.
+func SQLNullString(v sql.NullString, buf []byte) []byte {
+	if !v.Valid {
+		return Null(buf)
+	}
+	return String(v.String, buf)
+}
+
+// SQLNullInt64 appends v.Int64, or null when v isn't Valid.
+func SQLNullInt64(v sql.NullInt64, buf []byte) []byte {
+	if !v.Valid {
+		return Null(buf)
+	}
+	return Int64(v.Int64, buf)
+}
+
+// SQLNullFloat64 appends v.Float64, or null when v isn't Valid. It fails like Float64.
+func SQLNullFloat64(v sql.NullFloat64, buf []byte) ([]byte, error) {
+	if !v.Valid {
+		return Null(buf), nil
+	}
+	return Float64(v.Float64, buf)
+}
+
+// SQLNullBool appends v.Bool, or null when v isn't Valid.
+func SQLNullBool(v sql.NullBool, buf []byte) []byte {
+	if !v.Valid {
+		return Null(buf)
+	}
+	return Bool(v.Bool, buf)
+}
+
+// SQLNullTime appends v.Time, or null when v isn't Valid. It fails like Time.
+func SQLNullTime(v sql.NullTime, buf []byte) ([]byte, error) {
+	if !v.Valid {
+		return Null(buf), nil
+	}
+	return Time(v.Time, buf)
+}
+
+// SQLNullString writes v.String, or null when v isn't Valid.
+func (bw *BufWriter) SQLNullString(v sql.NullString) {
+	if !v.Valid {
+		bw.Null()
+		return
+	}
+	bw.String(v.String)
+}
+
+// SQLNullInt64 writes v.Int64, or null when v isn't Valid.
+func (bw *BufWriter) SQLNullInt64(v sql.NullInt64) {
+	if !v.Valid {
+		bw.Null()
+		return
+	}
+	bw.Int64(v.Int64)
+}
+
+// SQLNullFloat64 writes v.Float64, or null when v isn't Valid.
+func (bw *BufWriter) SQLNullFloat64(v sql.NullFloat64) {
+	if !v.Valid {
+		bw.Null()
+		return
+	}
+	bw.Float64(v.Float64)
+}
+
+// SQLNullBool writes v.Bool, or null when v isn't Valid.
+func (bw *BufWriter) SQLNullBool(v sql.NullBool) {
+	if !v.Valid {
+		bw.Null()
+		return
+	}
+	bw.Bool(v.Bool)
+}
+
+// SQLNullTime writes v.Time, or null when v isn't Valid.
+func (bw *BufWriter) SQLNullTime(v sql.NullTime) {
+	if !v.Valid {
+		bw.Null()
+		return
+	}
+	bw.Time(v.Time)
+}
+
+// appendSQLValue appends val when it's one of the database/sql Null types.
+func appendSQLValue(val interface{}, buf []byte) ([]byte, bool, error) {
+	switch v := val.(type) {
+	case sql.NullString:
+		return SQLNullString(v, buf), true, nil
+	case sql.NullInt64:
+		return SQLNullInt64(v, buf), true, nil
+	case sql.NullFloat64:
+		b, err := SQLNullFloat64(v, buf)
+		return b, true, err
+	case sql.NullBool:
+		return SQLNullBool(v, buf), true, nil
+	case sql.NullTime:
+		b, err := SQLNullTime(v, buf)
+		return b, true, err
+	}
+	return buf, false, nil
+}
@@ -0,0 +1,10 @@
+//go:build jsonappender_noreflect
+// +build jsonappender_noreflect
+
+package jsonappender
+
+// appendSQLValue never finds a database/sql Null type because database/sql isn't
+// imported with jsonappender_noreflect.
+func appendSQLValue(_ interface{}, buf []byte) ([]byte, bool, error) {
+	return buf, false, nil
+}
@@ -0,0 +1,48 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSQLNull(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, td := range []struct {
+		val  interface{}
+		want string
+	}{
+		{sql.NullString{}, `null`},
+		{sql.NullString{String: "a", Valid: true}, `"a"`},
+		{sql.NullInt64{}, `null`},
+		{sql.NullInt64{Int64: -2, Valid: true}, `-2`},
+		{sql.NullFloat64{}, `null`},
+		{sql.NullFloat64{Float64: 0.5, Valid: true}, `0.5`},
+		{sql.NullBool{}, `null`},
+		{sql.NullBool{Bool: true, Valid: true}, `true`},
+		{sql.NullTime{}, `null`},
+		{sql.NullTime{Time: ts, Valid: true}, `"2021-03-04T05:06:07Z"`},
+	} {
+		got, err := Value(td.val, nil)
+		if err != nil || string(got) != td.want {
+			t.Errorf("%#v: got %s, %v", td.val, got, err)
+		}
+	}
+	if _, err := SQLNullFloat64(sql.NullFloat64{Float64: math.NaN(), Valid: true}, nil); err == nil {
+		t.Error("expected an error")
+	}
+
+	bw := &BufWriter{}
+	bw.SQLNullString(sql.NullString{String: "a", Valid: true})
+	bw.SQLNullInt64(sql.NullInt64{})
+	bw.SQLNullFloat64(sql.NullFloat64{Float64: 1, Valid: true})
+	bw.SQLNullBool(sql.NullBool{})
+	bw.SQLNullTime(sql.NullTime{Time: ts, Valid: true})
+	if got := string(bw.TakeBuffer()); got != `"a"null1null"2021-03-04T05:06:07Z"` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,33 @@
+package jsonappender
+
+// Stringer appends the result of s.String() as a string value, or null for a nil s.
+// s is typically a fmt.Stringer. The parameter is spelled out so the package doesn't
+// need to import fmt. Like fmt, a nil pointer whose String method panics is null too,
+// except with jsonappender_noreflect.
+func Stringer(s interface{ String() string }, buf []byte) []byte {
+	if s == nil {
+		return Null(buf)
+	}
+	str, ok := callString(s)
+	if !ok {
+		return Null(buf)
+	}
+	return String(str, buf)
+}
+
+// callString returns s.String(). ok is false when s is a nil pointer and String
+// panicked.
+func callString(s interface{ String() string }) (str string, ok bool) {
+	defer func() { recoverNilPointer(s, recover()) }()
+	return s.String(), true
+}
+
+// Stringer writes the result of s.String() as a string value, or null for a nil s.
+func (bw *BufWriter) Stringer(s interface{ String() string }) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Stringer(s, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,36 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type countingStringer struct {
+	calls int
+}
+
+func (s *countingStringer) String() string {
+	s.calls++
+	return "counted"
+}
+
+func TestStringer(t *testing.T) {
+	if got := Stringer(nil, []byte("x")); string(got) != "xnull" {
+		t.Errorf("got %s", got)
+	}
+	if got := Stringer(time.Duration(90)*time.Minute, nil); string(got) != `"1h30m0s"` {
+		t.Errorf("got %s", got)
+	}
+	s := &countingStringer{}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Stringer(s)
+	bw.Flush()
+	if out.String() != `"counted"` {
+		t.Errorf("got %q", out.String())
+	}
+	if s.calls != 1 {
+		t.Errorf("String called %d times", s.calls)
+	}
+}
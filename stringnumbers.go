@@ -0,0 +1,81 @@
+package jsonappender
+
+import (
+	"math"
+	"strconv"
+)
+
+// Int64String appends val as a quoted number, like encoding/json does for a field with
+// the ",string" option.
+func Int64String(val int64, buf []byte) []byte {
+	buf = append(buf, '"')
+	buf = strconv.AppendInt(buf, val, 10)
+	return append(buf, '"')
+}
+
+// Uint64String appends val as a quoted number, like encoding/json does for a field with
+// the ",string" option.
+func Uint64String(val uint64, buf []byte) []byte {
+	buf = append(buf, '"')
+	buf = strconv.AppendUint(buf, val, 10)
+	return append(buf, '"')
+}
+
+// Float64String appends f as a quoted number, like encoding/json does for a field with
+// the ",string" option. It fails like Float64.
+func Float64String(f float64, buf []byte) ([]byte, error) {
+	start := len(buf)
+	buf, err := Float64(f, append(buf, '"'))
+	if err != nil {
+		return buf[:start], err
+	}
+	return append(buf, '"'), nil
+}
+
+// Float64StringWithOptions is like Float64String but formats f according to opts. NaN
+// and infinities aren't quoted again: they are written as the NonFinite policy says.
+func Float64StringWithOptions(f float64, opts FloatOptions, buf []byte) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Float64WithOptions(f, opts, buf)
+	}
+	start := len(buf)
+	buf, err := Float64WithOptions(f, opts, append(buf, '"'))
+	if err != nil {
+		return buf[:start], err
+	}
+	return append(buf, '"'), nil
+}
+
+// Int64String writes val as a quoted number.
+func (bw *BufWriter) Int64String(val int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Int64String(val, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Uint64String writes val as a quoted number.
+func (bw *BufWriter) Uint64String(val uint64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = Uint64String(val, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Float64String writes f as a quoted number formatted with the BufWriter's
+// FloatOptions like Float64StringWithOptions.
+func (bw *BufWriter) Float64String(f float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = Float64StringWithOptions(f, bw.floatOpts, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,95 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// stringTagged returns what encoding/json writes for val in a field with the ",string"
+// option.
+func stringTagged(val interface{}) string {
+	var b []byte
+	var err error
+	switch v := val.(type) {
+	case int64:
+		b, err = json.Marshal(struct {
+			V int64 `json:",string"`
+		}{v})
+	case uint64:
+		b, err = json.Marshal(struct {
+			V uint64 `json:",string"`
+		}{v})
+	case float64:
+		b, err = json.Marshal(struct {
+			V float64 `json:",string"`
+		}{v})
+	}
+	if err != nil {
+		return ""
+	}
+	return string(b[len(`{"V":`) : len(b)-1])
+}
+
+func TestStringNumbers(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("Int64String same as encoding/json", prop.ForAll(
+		func(val int64) bool {
+			return string(Int64String(val, nil)) == stringTagged(val)
+		}, gen.Int64(),
+	))
+	properties.Property("Uint64String same as encoding/json", prop.ForAll(
+		func(val uint64) bool {
+			return string(Uint64String(val, nil)) == stringTagged(val)
+		}, gen.UInt64(),
+	))
+	properties.Property("Float64String same as encoding/json", prop.ForAll(
+		func(val float64) bool {
+			got, err := Float64String(val, nil)
+			return err == nil && string(got) == stringTagged(val)
+		}, gen.Float64(),
+	))
+	properties.TestingRun(t)
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Int64String(-1)
+	bw.RawByte(',')
+	bw.Uint64String(2)
+	bw.RawByte(',')
+	bw.Float64String(1e21)
+	bw.Flush()
+	if want := `"-1","2","1e+21"`; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
+
+func TestFloat64StringNonFinite(t *testing.T) {
+	for _, tc := range []struct {
+		policy NonFinitePolicy
+		f      float64
+		want   string
+	}{
+		{NonFiniteNull, math.NaN(), `null`},
+		{NonFiniteQuoted, math.NaN(), `"NaN"`},
+		{NonFiniteQuoted, math.Inf(-1), `"-Infinity"`},
+		{NonFiniteQuoted, 2.5, `"2.5"`},
+	} {
+		var out bytes.Buffer
+		bw := NewBufWriter(&out)
+		bw.SetFloatOptions(FloatOptions{NonFinite: tc.policy})
+		bw.Float64String(tc.f)
+		if err := bw.Flush(); err != nil || out.String() != tc.want {
+			t.Errorf("Float64String(%v) with %v = %s, %v, want %s", tc.f, tc.policy, out.String(), err, tc.want)
+		}
+	}
+	got, err := Float64StringWithOptions(math.Inf(1), FloatOptions{}, []byte("x"))
+	if err == nil || string(got) != "x" {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
@@ -0,0 +1,224 @@
+package jsonappender
+
+import (
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// StringOptions changes how strings and field names are escaped. The zero value
+// escapes them like String.
+type StringOptions struct {
+	// NoHTMLEscape leaves <, > and & alone. Only json itself needs escaping then, which
+	// matches encoding/json's Encoder with SetEscapeHTML(false). Don't use it for
+	// output that may end up in HTML.
+	NoHTMLEscape bool
+	// ASCII escapes every non-ASCII character as \uXXXX, with a surrogate pair for
+	// characters outside the Basic Multilingual Plane, so the output is plain ASCII
+	// like Python's ensure_ascii.
+	ASCII bool
+	// EscapeSlash escapes / as \/, like PHP's json_encode does by default.
+	EscapeSlash bool
+	// InvalidUTF8 is what to do with bytes that aren't valid UTF-8.
+	InvalidUTF8 InvalidUTF8Policy
+}
+
+// InvalidUTF8Policy is what string appenders do with invalid UTF-8.
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8Replace writes each invalid byte as \ufffd, the replacement
+	// character, like encoding/json.
+	InvalidUTF8Replace InvalidUTF8Policy = iota
+	// InvalidUTF8Fail fails with an *InvalidUTF8Error.
+	InvalidUTF8Fail
+	// InvalidUTF8PassThrough writes invalid bytes as they are. The output isn't valid
+	// json then, but nothing is lost for readers that expect it.
+	InvalidUTF8PassThrough
+)
+
+// InvalidUTF8Error is returned for a string that isn't valid UTF-8 when StringOptions
+// ask for InvalidUTF8Fail.
+type InvalidUTF8Error struct {
+	// Offset is where the first invalid byte is in the string.
+	Offset int
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return "jsonappender: invalid UTF-8 at byte " + strconv.Itoa(e.Offset)
+}
+
+// safeSet is htmlSafeSet with <, > and & allowed.
+var safeSet = func() [utf8.RuneSelf]bool {
+	set := htmlSafeSet
+	set['<'] = true
+	set['>'] = true
+	set['&'] = true
+	return set
+}()
+
+// htmlSafeSlashSet and safeSlashSet are htmlSafeSet and safeSet with / escaped.
+var (
+	htmlSafeSlashSet = withoutSlash(htmlSafeSet)
+	safeSlashSet     = withoutSlash(safeSet)
+)
+
+func withoutSlash(set [utf8.RuneSelf]bool) [utf8.RuneSelf]bool {
+	set['/'] = false
+	return set
+}
+
+// StringWithOptions is like String but escapes s according to opts. It only fails when
+// opts ask for InvalidUTF8Fail, and then returns buf as it was.
+func StringWithOptions(s string, opts StringOptions, buf []byte) ([]byte, error) {
+	return appendStringWithOptions(s, opts, buf)
+}
+
+// FieldNameWithOptions is like FieldName but escapes name according to opts. It only
+// fails when opts ask for InvalidUTF8Fail, and then returns buf as it was.
+func FieldNameWithOptions(name string, opts StringOptions, buf []byte) ([]byte, error) {
+	buf, err := appendStringWithOptions(name, opts, buf)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, ':'), nil
+}
+
+// StringNoHTMLEscape appends a string value without escaping <, > and &.
+func StringNoHTMLEscape(s string, buf []byte) []byte {
+	b, _ := appendStringWithOptions(s, StringOptions{NoHTMLEscape: true}, buf) //nolint:errcheck // InvalidUTF8Replace can't fail
+	return b
+}
+
+// FieldNameNoHTMLEscape appends a fieldname without escaping <, > and &.
+func FieldNameNoHTMLEscape(name string, buf []byte) []byte {
+	return append(StringNoHTMLEscape(name, buf), ':')
+}
+
+// StringNoHTMLEscape writes a string value without escaping <, > and &.
+func (bw *BufWriter) StringNoHTMLEscape(val string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = StringNoHTMLEscape(val, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// FieldNameNoHTMLEscape writes a fieldname without escaping <, > and &.
+func (bw *BufWriter) FieldNameNoHTMLEscape(name string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = FieldNameNoHTMLEscape(name, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// SetStringOptions sets the options String and FieldName escape with. An
+// InvalidUTF8Error goes to the BufWriter's Error.
+func (bw *BufWriter) SetStringOptions(opts StringOptions) {
+	if bw.keys != nil && opts != bw.stringOpts {
+		bw.keys.clear()
+	}
+	bw.stringOpts = opts
+}
+
+// appendUnicodeEscape appends r, which is at most 0xFFFF, as \uXXXX.
+func appendUnicodeEscape(buf []byte, r rune) []byte {
+	const hex = "0123456789abcdef"
+	return append(buf, '\\', 'u', hex[r>>12&0xF], hex[r>>8&0xF], hex[r>>4&0xF], hex[r&0xF])
+}
+
+// appendStringWithOptions is String with the escaping opts asks for. String keeps its own
+// loop so the default stays as fast as it can be.
+func appendStringWithOptions(s string, opts StringOptions, buf []byte) ([]byte, error) {
+	const hex = "0123456789abcdef"
+	var set *[utf8.RuneSelf]bool
+	switch {
+	case opts.NoHTMLEscape && opts.EscapeSlash:
+		set = &safeSlashSet
+	case opts.NoHTMLEscape:
+		set = &safeSet
+	case opts.EscapeSlash:
+		set = &htmlSafeSlashSet
+	default:
+		set = &htmlSafeSet
+	}
+	bufStart := len(buf)
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if set[b] {
+				i++
+				continue
+			}
+			if start < i {
+				buf = append(buf, s[start:i]...)
+			}
+			buf = append(buf, '\\')
+			switch b {
+			case '\\', '"', '/':
+				buf = append(buf, b)
+			case '\n':
+				buf = append(buf, 'n')
+			case '\r':
+				buf = append(buf, 'r')
+			case '\t':
+				buf = append(buf, 't')
+			default:
+				buf = append(buf, 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			switch opts.InvalidUTF8 {
+			case InvalidUTF8Fail:
+				return buf[:bufStart], &InvalidUTF8Error{Offset: i}
+			case InvalidUTF8PassThrough:
+				i++
+				continue
+			}
+			if start < i {
+				buf = append(buf, s[start:i]...)
+			}
+			buf = append(buf, '\\', 'u', 'f', 'f', 'f', 'd')
+			i += size
+			start = i
+			continue
+		}
+		if opts.ASCII {
+			if start < i {
+				buf = append(buf, s[start:i]...)
+			}
+			if c > 0xFFFF {
+				r1, r2 := utf16.EncodeRune(c)
+				buf = appendUnicodeEscape(buf, r1)
+				buf = appendUnicodeEscape(buf, r2)
+			} else {
+				buf = appendUnicodeEscape(buf, c)
+			}
+			i += size
+			start = i
+			continue
+		}
+		if c == '\u2028' || c == '\u2029' {
+			if start < i {
+				buf = append(buf, s[start:i]...)
+			}
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	if start < len(s) {
+		buf = append(buf, s[start:]...)
+	}
+	return append(buf, '"'), nil
+}
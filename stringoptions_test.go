@@ -0,0 +1,161 @@
+package jsonappender
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// encodingJSONNoHTML is what encoding/json writes for s with SetEscapeHTML(false).
+func encodingJSONNoHTML(s string) string {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return ""
+	}
+	return string(bytes.TrimSuffix(out.Bytes(), []byte{'\n'}))
+}
+
+func TestStringNoHTMLEscape(t *testing.T) {
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as encoding/json without html escaping", prop.ForAll(
+		func(val, buf string) bool {
+			return string(StringNoHTMLEscape(val, []byte(buf))) == buf+encodingJSONNoHTML(val)
+		}, gen.AnyString(), gen.AnyString(),
+	))
+	properties.Property("default options same as String", prop.ForAll(
+		func(val string) bool {
+			got, err := StringWithOptions(val, StringOptions{}, nil)
+			return err == nil && string(got) == string(String(val, nil))
+		}, gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	if got := FieldNameNoHTMLEscape("a<b>", nil); string(got) != `"a<b>":` {
+		t.Errorf("got %s", got)
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.RawByte('{')
+	bw.FieldNameNoHTMLEscape("&")
+	bw.StringNoHTMLEscape("<\u2028>")
+	bw.RawByte('}')
+	bw.Flush()
+	if want := `{"&":"<\u2028>"}`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+}
+
+func TestStringOptionsASCII(t *testing.T) {
+	opts := StringOptions{ASCII: true}
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("output is ASCII and decodes to the same string", prop.ForAll(
+		func(val string) bool {
+			got, err := StringWithOptions(val, opts, nil)
+			if err != nil {
+				return false
+			}
+			for _, c := range got {
+				if c >= 0x80 {
+					return false
+				}
+			}
+			var want, back string
+			if json.Unmarshal(String(val, nil), &want) != nil || json.Unmarshal(got, &back) != nil {
+				return false
+			}
+			return back == want
+		}, gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	if got, err := StringWithOptions("\u00e9\U0001F600<", opts, nil); err != nil || string(got) != `"\u00e9\ud83d\ude00\u003c"` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetStringOptions(StringOptions{ASCII: true, NoHTMLEscape: true})
+	bw.RawByte('{')
+	bw.FieldName("\u00fc")
+	bw.String("<\u2028>")
+	bw.RawByte('}')
+	bw.Flush()
+	if want := `{"\u00fc":"<\u2028>"}`; out.String() != want {
+		t.Errorf("got %s, wanted %s", out.String(), want)
+	}
+}
+
+func TestStringOptionsEscapeSlash(t *testing.T) {
+	for _, opts := range []StringOptions{
+		{EscapeSlash: true},
+		{EscapeSlash: true, NoHTMLEscape: true},
+		{EscapeSlash: true, ASCII: true},
+	} {
+		got, err := StringWithOptions("a/b</c>", opts, nil)
+		var back string
+		if err == nil {
+			err = json.Unmarshal(got, &back)
+		}
+		if err != nil || back != "a/b</c>" {
+			t.Errorf("%+v: %s doesn't decode: %v", opts, got, err)
+		}
+		if bytes.Count(got, []byte(`\/`)) != 2 {
+			t.Errorf("%+v: got %s", opts, got)
+		}
+	}
+	if got, err := FieldNameWithOptions("/", StringOptions{EscapeSlash: true}, nil); err != nil || string(got) != `"\/":` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	if got, err := StringWithOptions("/", StringOptions{}, nil); err != nil || string(got) != `"/"` {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
+
+func TestStringOptionsInvalidUTF8(t *testing.T) {
+	const s = "a\xffb\xc3"
+	const replaced = `"a\ufffdb\ufffd"`
+	got, err := StringWithOptions(s, StringOptions{}, nil)
+	if err != nil || string(got) != replaced {
+		t.Errorf("got %s, %v", got, err)
+	}
+	if got := String(s, nil); string(got) != replaced {
+		t.Errorf("String: got %s", got)
+	}
+	var back string
+	if err := json.Unmarshal([]byte(replaced), &back); err != nil || back != "a\uFFFDb\uFFFD" {
+		t.Errorf("got %q, %v", back, err)
+	}
+	got, err = StringWithOptions(s, StringOptions{InvalidUTF8: InvalidUTF8PassThrough}, nil)
+	if err != nil || string(got) != "\"a\xffb\xc3\"" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	got, err = StringWithOptions(s, StringOptions{InvalidUTF8: InvalidUTF8Fail}, []byte("x"))
+	var utf8Err *InvalidUTF8Error
+	if !errors.As(err, &utf8Err) || utf8Err.Offset != 1 || string(got) != "x" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	got, err = FieldNameWithOptions(s, StringOptions{InvalidUTF8: InvalidUTF8Fail}, []byte("x"))
+	if !errors.As(err, &utf8Err) || string(got) != "x" {
+		t.Errorf("FieldNameWithOptions: got %q, %v", got, err)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetStringOptions(StringOptions{InvalidUTF8: InvalidUTF8Fail})
+	bw.String("ok")
+	bw.FieldName(s)
+	if !errors.As(bw.Error, &utf8Err) {
+		t.Errorf("got %v", bw.Error)
+	}
+	bw.Error = nil
+	bw.Flush()
+	if out.String() != `"ok"` {
+		t.Errorf("got %q", out.String())
+	}
+}
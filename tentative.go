@@ -0,0 +1,106 @@
+package jsonappender
+
+// tentative holds output written since the outermost BeginTentative.
+type tentative struct {
+	buf   []byte
+	marks []tentativeMark
+	// spare keeps the last segment's buffer for reuse.
+	spare []byte
+}
+
+// tentativeMark is what Discard restores.
+type tentativeMark struct {
+	offset int
+	err    error
+	pos    Position
+	meta   metadataState
+	indent indentState
+	last   byte
+	// open is a copy of the BufWriter's open containers.
+	open []byte
+}
+
+// BeginTentative starts holding writes in a side segment until the matching Commit or
+// Discard. Use it around a value whose encoding may fail halfway so the partial output
+// can be abandoned. Tentative segments nest; only committing the outermost one writes
+// the output. Flush doesn't flush an open segment, and Reset drops it.
+func (bw *BufWriter) BeginTentative() {
+	if bw.tentative == nil {
+		bw.tentative = &tentative{}
+	}
+	t := bw.tentative
+	if len(t.marks) == 0 {
+		t.buf = t.spare[:0]
+	}
+	m := tentativeMark{
+		offset: len(t.buf),
+		err:    bw.Error,
+		last:   bw.last,
+	}
+	if len(bw.open) > 0 {
+		m.open = append(m.open, bw.open...)
+	}
+	if bw.pos != nil {
+		m.pos = *bw.pos
+	}
+	if bw.meta != nil {
+		m.meta = bw.meta.state
+	}
+	if bw.indent != nil {
+		m.indent = bw.indent.state
+	}
+	t.marks = append(t.marks, m)
+}
+
+// Tentative reports whether a tentative segment is open.
+func (bw *BufWriter) Tentative() bool {
+	return bw.tentative != nil && len(bw.tentative.marks) > 0
+}
+
+// Commit keeps the writes since the matching BeginTentative. Any error they caused is
+// kept too. It does nothing when no tentative segment is open.
+func (bw *BufWriter) Commit() {
+	if !bw.Tentative() {
+		return
+	}
+	t := bw.tentative
+	t.marks = t.marks[:len(t.marks)-1]
+	if len(t.marks) > 0 {
+		return
+	}
+	if bw.Error == nil {
+		bw.writeOutput(t.buf)
+	}
+	t.spare = t.buf[:0]
+	t.buf = nil
+}
+
+// Discard drops the writes since the matching BeginTentative and restores Error,
+// Position, the containers started with BeginObject and BeginArray, and SetMetadata's
+// and SetIndent's view of the output to what they were then. It does nothing when no
+// tentative segment is open.
+func (bw *BufWriter) Discard() {
+	if !bw.Tentative() {
+		return
+	}
+	t := bw.tentative
+	m := t.marks[len(t.marks)-1]
+	t.marks = t.marks[:len(t.marks)-1]
+	t.buf = t.buf[:m.offset]
+	bw.Error = m.err
+	bw.last = m.last
+	bw.open = append(bw.open[:0], m.open...)
+	if bw.pos != nil {
+		*bw.pos = m.pos
+	}
+	if bw.meta != nil {
+		bw.meta.state = m.meta
+	}
+	if bw.indent != nil {
+		bw.indent.state = m.indent
+	}
+	if len(t.marks) == 0 {
+		t.spare = t.buf[:0]
+		t.buf = nil
+	}
+}
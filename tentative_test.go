@@ -0,0 +1,64 @@
+package jsonappender
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestBufWriter_Tentative(t *testing.T) {
+	var out bytes.Buffer
+	bw := NewBufWriterSize(&out, 4)
+	bw.SetTrackPosition(true)
+	bw.RawByte('{')
+
+	bw.BeginTentative()
+	bw.FieldName("a")
+	bw.Float64(math.NaN())
+	if bw.Error == nil {
+		t.Fatal("expected an error for NaN")
+	}
+	bw.Discard()
+	if bw.Error != nil {
+		t.Errorf("expected Discard to clear the error, got %v", bw.Error)
+	}
+	if got := bw.Position().Offset; got != 1 {
+		t.Errorf("got offset %d after Discard, wanted 1", got)
+	}
+
+	bw.BeginTentative()
+	bw.FieldName("b")
+	bw.BeginTentative()
+	bw.String("dropped")
+	bw.Discard()
+	bw.BeginTentative()
+	bw.String("kept and longer than the buffer")
+	bw.Commit()
+	if out.Len() != 0 || bw.Buffered() != 1 {
+		t.Errorf("expected only committed output, got %q and %d buffered", out.String(), bw.Buffered())
+	}
+	bw.Commit()
+	if bw.Tentative() {
+		t.Error("expected no open tentative segment")
+	}
+	bw.RawByte('}')
+	bw.Flush()
+	if bw.Error != nil {
+		t.Fatal(bw.Error)
+	}
+	const want = `{"b":"kept and longer than the buffer"}`
+	if out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+
+	out.Reset()
+	bw.Reset(&out)
+	bw.BeginTentative()
+	bw.RawString("open")
+	bw.Reset(&out)
+	bw.RawString("1")
+	bw.Flush()
+	if out.String() != "1" {
+		t.Errorf("expected Reset to drop the open segment, got %q", out.String())
+	}
+}
@@ -0,0 +1,78 @@
+package jsonappender
+
+import "time"
+
+// TimeCache appends time.Time values exactly like Time, but keeps the formatted date,
+// time of day and zone from the previous call. When consecutive times fall within the
+// same second only the fractional seconds are formatted. This suits high frequency
+// logging where most timestamps share a second with the one before.
+//
+// The zero value is ready to use. A TimeCache is not safe for concurrent use.
+type TimeCache struct {
+	valid bool
+	sec   int64
+	loc   *time.Location
+	// prefix is the quote, date and time of day
+	prefix []byte
+	// suffix is the zone and closing quote
+	suffix []byte
+}
+
+// AppendTime appends t.
+func (c *TimeCache) AppendTime(t time.Time, buf []byte) ([]byte, error) {
+	if !c.valid || t.Unix() != c.sec || t.Location() != c.loc {
+		err := c.update(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf = append(buf, c.prefix...)
+	buf = appendNanoFraction(t.Nanosecond(), buf)
+	return append(buf, c.suffix...), nil
+}
+
+func (c *TimeCache) update(t time.Time) error {
+	c.valid = false
+	formatted, err := Time(t.Truncate(time.Second), c.prefix[:0])
+	if err != nil {
+		return err
+	}
+	// `"2006-01-02T15:04:05` is 20 bytes
+	c.prefix = formatted[:20]
+	c.suffix = append(c.suffix[:0], formatted[20:]...)
+	c.sec = t.Unix()
+	c.loc = t.Location()
+	c.valid = true
+	return nil
+}
+
+// appendNanoFraction appends the fractional seconds the way time.RFC3339Nano formats them.
+func appendNanoFraction(ns int, buf []byte) []byte {
+	if ns == 0 {
+		return buf
+	}
+	var digits [10]byte
+	digits[0] = '.'
+	for i := 9; i > 0; i-- {
+		digits[i] = byte('0' + ns%10)
+		ns /= 10
+	}
+	n := len(digits)
+	for digits[n-1] == '0' {
+		n--
+	}
+	return append(buf, digits[:n]...)
+}
+
+// TimeCached writes a time.Time value using the BufWriter's TimeCache
+func (bw *BufWriter) TimeCached(t time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = bw.timeCache.AppendTime(t, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
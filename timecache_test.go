@@ -0,0 +1,48 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestTimeCache(t *testing.T) {
+	var cache TimeCache
+	properties := gopter.NewProperties(gopterParams())
+	properties.Property("same as Time", prop.ForAll(
+		func(val time.Time, nanos []int64, buf string) bool {
+			for _, n := range append(nanos, 0) {
+				tm := val.Add(time.Duration(n))
+				want, wantErr := Time(tm, []byte(buf))
+				got, err := cache.AppendTime(tm, []byte(buf))
+				if (err == nil) != (wantErr == nil) || string(got) != string(want) {
+					return false
+				}
+			}
+			return true
+		},
+		gen.Time(),
+		gen.SliceOf(gen.Int64Range(-int64(time.Second), int64(time.Second))),
+		gen.AnyString(),
+	))
+	properties.TestingRun(t)
+
+	base := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	bw := NewBufWriter(&buf)
+	bw.TimeCached(base)
+	bw.TimeCached(base.Add(120 * time.Millisecond))
+	bw.TimeCached(base.In(time.FixedZone("x", 3600)))
+	err := bw.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"2020-01-02T03:04:05Z""2020-01-02T03:04:05.12Z""2020-01-02T04:04:05+01:00"`
+	if buf.String() != want {
+		t.Errorf("got %s", buf.String())
+	}
+}
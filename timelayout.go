@@ -0,0 +1,41 @@
+package jsonappender
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// TimeLayout appends t formatted with layout, which is in the format of time.Time's
+// Format, as a string value. Any characters the layout or zone name put in the output
+// that need escaping are escaped. It fails when the output isn't valid UTF-8.
+func TimeLayout(t time.Time, layout string, buf []byte) ([]byte, error) {
+	start := len(buf)
+	buf = append(buf, '"')
+	buf = t.AppendFormat(buf, layout)
+	if !utf8.Valid(buf[start+1:]) {
+		return buf[:start], errors.New("jsonappender: time layout output is not valid UTF-8")
+	}
+	return quoteAppended(buf, start), nil
+}
+
+// TimeLayout writes t formatted with layout as a string value. The BufWriter's
+// TimeOptions UTC and ZeroAsNull apply.
+func (bw *BufWriter) TimeLayout(t time.Time, layout string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	if bw.timeOpts.ZeroAsNull && t.IsZero() {
+		bw.Null()
+		return
+	}
+	if bw.timeOpts.UTC {
+		t = t.UTC()
+	}
+	bw.stringBuf, bw.Error = TimeLayout(t, layout, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,41 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeLayout(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, td := range []struct {
+		layout string
+		want   string
+	}{
+		{layout: "2006-01-02 15:04:05", want: `x"2021-03-04 05:06:07"`},
+		{layout: time.RFC1123, want: `x"Thu, 04 Mar 2021 05:06:07 UTC"`},
+		{layout: `"Jan" <2>`, want: `x"\"Mar\" \u003c4\u003e"`},
+		{layout: "15h\t04m", want: `x"05h\t06m"`},
+		{layout: "02 Ünï", want: `x"04 Ünï"`},
+	} {
+		got, err := TimeLayout(ts, td.layout, []byte("x"))
+		if err != nil || string(got) != td.want {
+			t.Errorf("%q: got %s, %v, wanted %s", td.layout, got, err, td.want)
+		}
+	}
+	got, err := TimeLayout(ts, "2006\xff", []byte("x"))
+	if err == nil {
+		t.Errorf("expected an error for invalid UTF-8, got %s", got)
+	}
+	if string(got) != "x" {
+		t.Errorf("got %q after the error", got)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.TimeLayout(ts, "2006-01-02")
+	bw.Flush()
+	if out.String() != `"2021-03-04"` {
+		t.Errorf("got %q", out.String())
+	}
+}
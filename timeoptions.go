@@ -0,0 +1,56 @@
+package jsonappender
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeOptions changes how times are formatted. The zero value formats them like
+// encoding/json.
+type TimeOptions struct {
+	// UTC converts times to UTC before formatting them, so the same instant is written
+	// the same way whatever its location.
+	UTC bool
+	// ExtendedYear writes years outside of 0 to 9999 in the ISO 8601 expanded
+	// representation, with a sign and as many digits as needed, like
+	// "+10000-01-01T00:00:00Z", instead of failing. Parsers that only know RFC 3339
+	// can't read them.
+	ExtendedYear bool
+	// ZeroAsNull writes null for the zero time instead of "0001-01-01T00:00:00Z".
+	ZeroAsNull bool
+}
+
+// TimeWithOptions is like Time but formats t according to opts.
+func TimeWithOptions(t time.Time, opts TimeOptions, buf []byte) ([]byte, error) {
+	if opts.ZeroAsNull && t.IsZero() {
+		return Null(buf), nil
+	}
+	if opts.UTC {
+		t = t.UTC()
+	}
+	if y := t.Year(); opts.ExtendedYear && (y < 0 || y >= 10000) {
+		return appendExtendedYearTime(t, y, buf), nil
+	}
+	return Time(t, buf)
+}
+
+func appendExtendedYearTime(t time.Time, year int, buf []byte) []byte {
+	buf = append(buf, '"')
+	if year < 0 {
+		buf = append(buf, '-')
+		year = -year
+	} else {
+		buf = append(buf, '+')
+	}
+	for d := 1000; d > 1 && year < d; d /= 10 {
+		buf = append(buf, '0')
+	}
+	buf = strconv.AppendInt(buf, int64(year), 10)
+	buf = t.AppendFormat(buf, "-01-02T15:04:05.999999999Z07:00")
+	return append(buf, '"')
+}
+
+// SetTimeOptions sets the options Time and TimeLayout format with.
+func (bw *BufWriter) SetTimeOptions(opts TimeOptions) {
+	bw.timeOpts = opts
+}
@@ -0,0 +1,73 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeOptions(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("X", -7*3600))
+	got, err := TimeWithOptions(ts, TimeOptions{UTC: true}, nil)
+	if err != nil || string(got) != `"2021-03-04T12:06:07Z"` {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.Time(ts)
+	bw.SetTimeOptions(TimeOptions{UTC: true})
+	bw.RawByte(',')
+	bw.Time(ts)
+	bw.RawByte(',')
+	bw.TimeLayout(ts, "15:04 MST")
+	bw.Flush()
+	if want := `"2021-03-04T05:06:07-07:00","2021-03-04T12:06:07Z","12:06 UTC"`; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
+
+func TestTimeOptionsExtendedYear(t *testing.T) {
+	opts := TimeOptions{ExtendedYear: true}
+	for _, td := range []struct {
+		t    time.Time
+		want string
+	}{
+		{t: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), want: `"+10000-01-01T00:00:00Z"`},
+		{t: time.Date(-1, 2, 3, 4, 5, 6, 5e8, time.UTC), want: `"-0001-02-03T04:05:06.5Z"`},
+		{t: time.Date(-12345, 6, 7, 8, 9, 10, 0, time.FixedZone("", 3600)), want: `"-12345-06-07T08:09:10+01:00"`},
+		{t: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), want: `"2021-01-01T00:00:00Z"`},
+	} {
+		got, err := TimeWithOptions(td.t, opts, nil)
+		if err != nil || string(got) != td.want {
+			t.Errorf("got %s, %v, wanted %s", got, err, td.want)
+		}
+	}
+	_, err := TimeWithOptions(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), TimeOptions{}, nil)
+	if err == nil {
+		t.Error("expected an error without ExtendedYear")
+	}
+}
+
+func TestTimeOptionsZeroAsNull(t *testing.T) {
+	opts := TimeOptions{ZeroAsNull: true}
+	got, err := TimeWithOptions(time.Time{}, opts, []byte("x"))
+	if err != nil || string(got) != "xnull" {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.SetTimeOptions(opts)
+	bw.Time(time.Time{})
+	bw.RawByte(',')
+	bw.TimePtr(&time.Time{})
+	bw.RawByte(',')
+	bw.TimeLayout(time.Time{}, "2006")
+	bw.RawByte(',')
+	bw.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	bw.Flush()
+	if want := `null,null,null,"2021-01-01T00:00:00Z"`; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
@@ -0,0 +1,239 @@
+package jsonappender
+
+import "sort"
+
+// StringMap appends mp as an object of strings, without boxing each value in an
+// interface like Object needs. A nil mp is null.
+func StringMap(mp map[string]string, buf []byte) []byte {
+	if mp == nil {
+		return Null(buf)
+	}
+	var comma bool
+	buf = append(buf, '{')
+	for k, v := range mp {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = FieldName(k, buf)
+		buf = String(v, buf)
+	}
+	return append(buf, '}')
+}
+
+// StringMapSorted is like StringMap but writes the members sorted by name, which is
+// what Value writes for a map[string]string, like encoding/json.
+func StringMapSorted(mp map[string]string, buf []byte) []byte {
+	kp := sortedKeysPool.Get().(*[]string)
+	buf, keys := appendStringMapSorted(mp, (*kp)[:0], buf)
+	putSortedKeys(kp, keys)
+	return buf
+}
+
+// appendStringMapSorted appends mp sorted using keys, which it returns emptied.
+func appendStringMapSorted(mp map[string]string, keys []string, buf []byte) ([]byte, []string) {
+	if mp == nil {
+		return Null(buf), keys
+	}
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	buf = append(buf, '{')
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(k, buf)
+		buf = String(mp[k], buf)
+	}
+	return append(buf, '}'), clearKeys(keys)
+}
+
+// clearKeys empties keys, dropping the strings so a pooled slice doesn't keep them
+// alive.
+func clearKeys(keys []string) []string {
+	for i := range keys {
+		keys[i] = ""
+	}
+	return keys[:0]
+}
+
+// StringMap writes an object of strings like StringMap.
+func (bw *BufWriter) StringMap(mp map[string]string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = StringMap(mp, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// StringMapSorted writes an object of strings sorted by name like StringMapSorted.
+func (bw *BufWriter) StringMapSorted(mp map[string]string) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.sortKeys = appendStringMapSorted(mp, bw.sortKeys[:0], bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Int64Map appends mp as an object of int64 values without boxing them. A nil mp is
+// null.
+func Int64Map(mp map[string]int64, buf []byte) []byte {
+	return appendInt64Map(mp, false, buf)
+}
+
+func appendInt64Map(mp map[string]int64, quote bool, buf []byte) []byte {
+	if mp == nil {
+		return Null(buf)
+	}
+	var comma bool
+	buf = append(buf, '{')
+	for k, v := range mp {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = FieldName(k, buf)
+		buf = appendInt64Quoted(v, quote, buf)
+	}
+	return append(buf, '}')
+}
+
+// Float64Map appends mp as an object of float64 values without boxing them. A nil mp
+// is null. It fails for NaN and infinities like Float64.
+func Float64Map(mp map[string]float64, buf []byte) ([]byte, error) {
+	return Float64MapWithOptions(mp, FloatOptions{}, buf)
+}
+
+// Float64MapWithOptions is like Float64Map but formats values according to opts.
+func Float64MapWithOptions(mp map[string]float64, opts FloatOptions, buf []byte) ([]byte, error) {
+	if mp == nil {
+		return Null(buf), nil
+	}
+	var comma bool
+	buf = append(buf, '{')
+	var err error
+	for k, v := range mp {
+		if comma {
+			buf = append(buf, ',')
+		}
+		comma = true
+		buf = FieldName(k, buf)
+		buf, err = Float64WithOptions(v, opts, buf)
+		if err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// Int64MapSorted is like Int64Map but writes the members sorted by name, which is what
+// Value writes for a map[string]int64, like encoding/json.
+func Int64MapSorted(mp map[string]int64, buf []byte) []byte {
+	kp := sortedKeysPool.Get().(*[]string)
+	buf, keys := appendInt64MapSorted(mp, false, (*kp)[:0], buf)
+	putSortedKeys(kp, keys)
+	return buf
+}
+
+func appendInt64MapSorted(mp map[string]int64, quote bool, keys []string, buf []byte) ([]byte, []string) {
+	if mp == nil {
+		return Null(buf), keys
+	}
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	buf = append(buf, '{')
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(k, buf)
+		buf = appendInt64Quoted(mp[k], quote, buf)
+	}
+	return append(buf, '}'), clearKeys(keys)
+}
+
+// Float64MapSorted is like Float64Map but writes the members sorted by name, which is
+// what Value writes for a map[string]float64, like encoding/json.
+func Float64MapSorted(mp map[string]float64, buf []byte) ([]byte, error) {
+	kp := sortedKeysPool.Get().(*[]string)
+	buf, keys, err := appendFloat64MapSorted(mp, FloatOptions{}, (*kp)[:0], buf)
+	putSortedKeys(kp, keys)
+	return buf, err
+}
+
+func appendFloat64MapSorted(mp map[string]float64, opts FloatOptions, keys []string, buf []byte) ([]byte, []string, error) {
+	if mp == nil {
+		return Null(buf), keys, nil
+	}
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	buf = append(buf, '{')
+	var err error
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = FieldName(k, buf)
+		buf, err = Float64WithOptions(mp[k], opts, buf)
+		if err != nil {
+			return buf, clearKeys(keys), err
+		}
+	}
+	return append(buf, '}'), clearKeys(keys), nil
+}
+
+// Int64Map writes an object of int64 values like Int64Map.
+func (bw *BufWriter) Int64Map(mp map[string]int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = appendInt64Map(mp, bw.quoteLargeInts, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Float64Map writes an object of float64 values formatted with the BufWriter's
+// FloatOptions.
+func (bw *BufWriter) Float64Map(mp map[string]float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.Error = Float64MapWithOptions(mp, bw.floatOpts, bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
+
+// Int64MapSorted writes an object of int64 values sorted by name like Int64MapSorted.
+func (bw *BufWriter) Int64MapSorted(mp map[string]int64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.sortKeys = appendInt64MapSorted(mp, bw.quoteLargeInts, bw.sortKeys[:0], bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// Float64MapSorted writes an object of float64 values sorted by name and formatted
+// with the BufWriter's FloatOptions.
+func (bw *BufWriter) Float64MapSorted(mp map[string]float64) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf, bw.sortKeys, bw.Error = appendFloat64MapSorted(mp, bw.floatOpts, bw.sortKeys[:0], bw.stringBuf[:0])
+	if bw.Error != nil {
+		return
+	}
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,98 @@
+package jsonappender
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestStringMap(t *testing.T) {
+	got := StringMap(map[string]string{"a": "b\n"}, []byte("x"))
+	if string(got) != `x{"a":"b\n"}` {
+		t.Errorf("got %s", got)
+	}
+	got = StringMap(nil, nil)
+	if string(got) != `null` {
+		t.Errorf("got %s", got)
+	}
+	mp := map[string]string{"c": "3", "a": "1", "b": "2"}
+	want := `{"a":"1","b":"2","c":"3"}`
+	for i := 0; i < 5; i++ {
+		if got := StringMapSorted(mp, nil); string(got) != want {
+			t.Fatalf("got %s", got)
+		}
+	}
+	bw := &BufWriter{}
+	bw.StringMapSorted(mp)
+	bw.StringMap(map[string]string{"d": ""})
+	if got := string(bw.TakeBuffer()); got != want+`{"d":""}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestStringMapValue(t *testing.T) {
+	for _, mp := range []map[string]string{
+		{"b": "1", "a": "2", "c": "<"},
+		{},
+		nil,
+	} {
+		got, err := Value(mp, nil)
+		want, _ := json.Marshal(mp) //nolint:errcheck // can't fail
+		if err != nil || string(got) != string(want) {
+			t.Errorf("got %s, %v, wanted %s", got, err, want)
+		}
+	}
+	if got := StringMapSorted(nil, nil); string(got) != `null` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestNumberMaps(t *testing.T) {
+	got := Int64Map(map[string]int64{"a": -1}, []byte("x"))
+	if string(got) != `x{"a":-1}` {
+		t.Errorf("got %s", got)
+	}
+	got, err := Float64Map(map[string]float64{"b": 0.5}, nil)
+	if err != nil || string(got) != `{"b":0.5}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	if _, err = Float64Map(map[string]float64{"c": math.NaN()}, nil); err == nil {
+		t.Error("expected an error")
+	}
+	got, err = Value(map[string]int64{"d": 2}, nil)
+	if err != nil || string(got) != `{"d":2}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	bw := &BufWriter{}
+	bw.SetFloatOptions(FloatOptions{NonFinite: NonFiniteNull})
+	bw.Float64Map(map[string]float64{"e": math.Inf(1)})
+	bw.Int64Map(nil)
+	bw.Float64MapSorted(map[string]float64{"g": math.Inf(-1), "f": 1})
+	bw.Int64MapSorted(map[string]int64{"i": 1, "h": 2})
+	if got := string(bw.TakeBuffer()); got != `{"e":null}null{"f":1,"g":null}{"h":2,"i":1}` || bw.Error != nil {
+		t.Errorf("got %s, %v", got, bw.Error)
+	}
+}
+
+func TestNumberMapValue(t *testing.T) {
+	for _, val := range []interface{}{
+		map[string]int64{"b": 1, "a": -2, "c": 3, "B": 4},
+		map[string]int64(nil),
+		map[string]float64{"b": 0.5, "a": 1e21, "c": -3},
+		map[string]float64(nil),
+	} {
+		want, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Value(val, nil)
+		if err != nil || string(got) != string(want) {
+			t.Errorf("Value(%#v) = %s, %v, want %s", val, got, err, want)
+		}
+	}
+	got, err := Float64MapWithOptions(nil, FloatOptions{}, nil)
+	if err != nil || string(got) != "null" {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
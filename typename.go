@@ -0,0 +1,14 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import "reflect"
+
+// typeName returns the name of val's dynamic type for error messages.
+func typeName(val interface{}) string {
+	if val == nil {
+		return ""
+	}
+	return reflect.TypeOf(val).String()
+}
@@ -0,0 +1,46 @@
+//go:build jsonappender_noreflect
+// +build jsonappender_noreflect
+
+package jsonappender
+
+import "time"
+
+// typeName returns the name of val's dynamic type for error messages. Without reflect
+// it only knows common types Value has no appender for and returns "" for the rest.
+func typeName(val interface{}) string {
+	switch val.(type) {
+	case time.Duration:
+		return "time.Duration"
+	case *time.Time:
+		return "*time.Time"
+	case error:
+		return "error"
+	case *string:
+		return "*string"
+	case *int:
+		return "*int"
+	case *int64:
+		return "*int64"
+	case *float64:
+		return "*float64"
+	case *bool:
+		return "*bool"
+	case []string:
+		return "[]string"
+	case []int:
+		return "[]int"
+	case []int64:
+		return "[]int64"
+	case []float64:
+		return "[]float64"
+	case []bool:
+		return "[]bool"
+	case []map[string]interface{}:
+		return "[]map[string]interface {}"
+	case map[string]int:
+		return "map[string]int"
+	case map[string]bool:
+		return "map[string]bool"
+	}
+	return ""
+}
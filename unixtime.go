@@ -0,0 +1,67 @@
+package jsonappender
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeUnix appends t as an integer number of seconds since the Unix epoch.
+func TimeUnix(t time.Time, buf []byte) []byte {
+	return strconv.AppendInt(buf, t.Unix(), 10)
+}
+
+// TimeUnixMilli appends t as an integer number of milliseconds since the Unix epoch.
+func TimeUnixMilli(t time.Time, buf []byte) []byte {
+	return strconv.AppendInt(buf, t.Unix()*1e3+int64(t.Nanosecond())/1e6, 10)
+}
+
+// TimeUnixMicro appends t as an integer number of microseconds since the Unix epoch.
+func TimeUnixMicro(t time.Time, buf []byte) []byte {
+	return strconv.AppendInt(buf, t.Unix()*1e6+int64(t.Nanosecond())/1e3, 10)
+}
+
+// TimeUnixNano appends t as an integer number of nanoseconds since the Unix epoch. Like
+// time.Time's UnixNano it's undefined for times outside of the years 1678 to 2262.
+func TimeUnixNano(t time.Time, buf []byte) []byte {
+	return strconv.AppendInt(buf, t.UnixNano(), 10)
+}
+
+// TimeUnix writes t as an integer number of seconds since the Unix epoch.
+func (bw *BufWriter) TimeUnix(t time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = TimeUnix(t, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// TimeUnixMilli writes t as an integer number of milliseconds since the Unix epoch.
+func (bw *BufWriter) TimeUnixMilli(t time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = TimeUnixMilli(t, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// TimeUnixMicro writes t as an integer number of microseconds since the Unix epoch.
+func (bw *BufWriter) TimeUnixMicro(t time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = TimeUnixMicro(t, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
+
+// TimeUnixNano writes t as an integer number of nanoseconds since the Unix epoch.
+func (bw *BufWriter) TimeUnixNano(t time.Time) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = TimeUnixNano(t, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,58 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeUnix(t *testing.T) {
+	for _, td := range []struct {
+		t                       time.Time
+		sec, milli, micro, nano string
+	}{
+		{
+			t:     time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC),
+			sec:   "1614834367",
+			milli: "1614834367123",
+			micro: "1614834367123456",
+			nano:  "1614834367123456789",
+		},
+		{
+			// before the epoch the fractions round down like time.Time's Unix
+			t:     time.Unix(-2, 500000000),
+			sec:   "-2",
+			milli: "-1500",
+			micro: "-1500000",
+			nano:  "-1500000000",
+		},
+	} {
+		if got := TimeUnix(td.t, nil); string(got) != td.sec {
+			t.Errorf("TimeUnix(%v): got %s, wanted %s", td.t, got, td.sec)
+		}
+		if got := TimeUnixMilli(td.t, nil); string(got) != td.milli {
+			t.Errorf("TimeUnixMilli(%v): got %s, wanted %s", td.t, got, td.milli)
+		}
+		if got := TimeUnixMicro(td.t, nil); string(got) != td.micro {
+			t.Errorf("TimeUnixMicro(%v): got %s, wanted %s", td.t, got, td.micro)
+		}
+		if got := TimeUnixNano(td.t, nil); string(got) != td.nano {
+			t.Errorf("TimeUnixNano(%v): got %s, wanted %s", td.t, got, td.nano)
+		}
+	}
+
+	ts := time.Unix(1, 2003004)
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.TimeUnix(ts)
+	bw.RawByte(',')
+	bw.TimeUnixMilli(ts)
+	bw.RawByte(',')
+	bw.TimeUnixMicro(ts)
+	bw.RawByte(',')
+	bw.TimeUnixNano(ts)
+	bw.Flush()
+	if want := "1,1002,1002003,1002003004"; out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import "net/url"
+
+// URL appends u.String() as a string value. A nil u is null.
+func URL(u *url.URL, buf []byte) []byte {
+	if u == nil {
+		return Null(buf)
+	}
+	return String(u.String(), buf)
+}
+
+// URL writes u.String() as a string value. A nil u is null.
+func (bw *BufWriter) URL(u *url.URL) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = URL(u, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
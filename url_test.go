@@ -0,0 +1,31 @@
+//go:build !jsonappender_noreflect
+// +build !jsonappender_noreflect
+
+package jsonappender
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	u, err := url.Parse("https://example.com/a b?q=<x>&r=1#frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := URL(u, []byte("x"))
+	if !matchesEncodingJSON(u.String(), []byte("x"), got, nil) {
+		t.Errorf("got %s", got)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.URL(&url.URL{Scheme: "mailto", Opaque: "a@example.com"})
+	bw.RawByte(',')
+	bw.URL(nil)
+	bw.Flush()
+	if out.String() != `"mailto:a@example.com",null` {
+		t.Errorf("got %q", out.String())
+	}
+}
@@ -0,0 +1,28 @@
+package jsonappender
+
+// UUID appends id as a string value in the canonical lowercase form,
+// "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx". Most UUID types are a [16]byte and can be
+// passed directly.
+func UUID(id [16]byte, buf []byte) []byte {
+	buf = append(buf, '"')
+	buf = appendHex(id[:4], buf)
+	buf = append(buf, '-')
+	buf = appendHex(id[4:6], buf)
+	buf = append(buf, '-')
+	buf = appendHex(id[6:8], buf)
+	buf = append(buf, '-')
+	buf = appendHex(id[8:10], buf)
+	buf = append(buf, '-')
+	buf = appendHex(id[10:], buf)
+	return append(buf, '"')
+}
+
+// UUID writes id as a string value in the canonical lowercase form.
+func (bw *BufWriter) UUID(id [16]byte) {
+	if bw.Error != nil {
+		return
+	}
+	bw.valueComma()
+	bw.stringBuf = UUID(id, bw.stringBuf[:0])
+	bw.write(bw.stringBuf)
+}
@@ -0,0 +1,21 @@
+package jsonappender
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	id := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	if got := UUID(id, []byte("x")); string(got) != `x"123e4567-e89b-12d3-a456-426614174000"` {
+		t.Errorf("got %s", got)
+	}
+
+	var out bytes.Buffer
+	bw := NewBufWriter(&out)
+	bw.UUID([16]byte{})
+	bw.Flush()
+	if out.String() != `"00000000-0000-0000-0000-000000000000"` {
+		t.Errorf("got %q", out.String())
+	}
+}
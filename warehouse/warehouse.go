@@ -0,0 +1,335 @@
+// Package warehouse writes newline delimited json for BigQuery and Snowflake load jobs.
+// Each record is checked against a column schema and its values are coerced to what
+// the target expects, so a bad record is rejected before the load job sees it.
+package warehouse
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+// Target is the warehouse the output is loaded into.
+type Target int
+
+// Targets for NewWriter.
+const (
+	BigQuery Target = iota
+	Snowflake
+)
+
+// Type is the type of a column.
+type Type int
+
+// Column types.
+const (
+	// String accepts strings and []byte, and numbers and bools, which are formatted.
+	String Type = iota
+	// Int64 accepts integers, whole floats and numeric strings. They're written as
+	// strings so no loader reads them as a float64.
+	Int64
+	// Float64 accepts numbers and numeric strings. NaN and infinities are written the
+	// way the target spells them.
+	Float64
+	// Bool accepts bools and the strings "true" and "false".
+	Bool
+	// Timestamp accepts time.Time and RFC 3339 strings. BigQuery gets UTC with
+	// microseconds and Snowflake gets nanoseconds with the offset.
+	Timestamp
+	// JSON accepts any value jsonappender can append, for JSON and VARIANT columns.
+	JSON
+)
+
+var typeNames = [...]string{
+	String:    "string",
+	Int64:     "int64",
+	Float64:   "float64",
+	Bool:      "bool",
+	Timestamp: "timestamp",
+	JSON:      "json",
+}
+
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return "Type(" + strconv.Itoa(int(t)) + ")"
+	}
+	return typeNames[t]
+}
+
+// Column is a column of the destination table. A nil value for a column that isn't
+// Required is left out of the record, which loads as NULL.
+type Column struct {
+	Name     string
+	Type     Type
+	Required bool
+}
+
+// Record is a row to write, keyed by column name.
+type Record map[string]interface{}
+
+// UnknownColumnError is returned for a record with a column that isn't in the schema.
+type UnknownColumnError struct {
+	Column string
+}
+
+func (e *UnknownColumnError) Error() string {
+	return "warehouse: unknown column " + strconv.Quote(e.Column)
+}
+
+// MissingColumnError is returned for a record without a Required column.
+type MissingColumnError struct {
+	Column string
+}
+
+func (e *MissingColumnError) Error() string {
+	return "warehouse: missing required column " + strconv.Quote(e.Column)
+}
+
+// CoercionError is returned when a value can't be coerced to its column's type.
+type CoercionError struct {
+	Column string
+	Type   Type
+	Value  interface{}
+}
+
+func (e *CoercionError) Error() string {
+	return "warehouse: can't use value of column " + strconv.Quote(e.Column) + " as " + e.Type.String()
+}
+
+// Writer writes records as ndjson.
+type Writer struct {
+	bw      *jsonappender.BufWriter
+	records *jsonappender.RecordWriter
+	target  Target
+	columns []Column
+	index   map[string]int
+	line    []byte
+}
+
+// NewWriter returns a Writer that writes records with columns to bw for target.
+func NewWriter(bw *jsonappender.BufWriter, target Target, columns []Column) *Writer {
+	index := make(map[string]int, len(columns))
+	for i, c := range columns {
+		index[c.Name] = i
+	}
+	return &Writer{
+		bw:      bw,
+		records: jsonappender.NewRecordWriter(bw, jsonappender.SeparatorLF, true),
+		target:  target,
+		columns: columns,
+		index:   index,
+	}
+}
+
+// Write checks rec against the schema and writes it. Nothing is written when it
+// returns an error, so the caller can skip the record and carry on. Columns are written
+// in schema order.
+func (w *Writer) Write(rec Record) error {
+	for name := range rec {
+		if _, ok := w.index[name]; !ok {
+			return &UnknownColumnError{Column: name}
+		}
+	}
+	line := append(w.line[:0], '{')
+	comma := false
+	for _, c := range w.columns {
+		v := rec[c.Name]
+		if v == nil {
+			if c.Required {
+				return &MissingColumnError{Column: c.Name}
+			}
+			continue
+		}
+		if comma {
+			line = append(line, ',')
+		}
+		comma = true
+		line = jsonappender.FieldName(c.Name, line)
+		var ok bool
+		line, ok = w.appendValue(c.Type, v, line)
+		if !ok {
+			w.line = line
+			return &CoercionError{Column: c.Name, Type: c.Type, Value: v}
+		}
+	}
+	line = append(line, '}')
+	w.line = line
+	w.records.Next()
+	w.bw.Raw(line)
+	return w.bw.Error
+}
+
+// Records returns the number of records written.
+func (w *Writer) Records() int {
+	return w.records.Records()
+}
+
+// Finish ends the last line and returns the BufWriter's error. It doesn't flush.
+func (w *Writer) Finish() error {
+	return w.records.Finish()
+}
+
+func (w *Writer) appendValue(t Type, v interface{}, buf []byte) ([]byte, bool) {
+	switch t {
+	case String:
+		return appendString(v, buf)
+	case Int64:
+		n, ok := toInt64(v)
+		if !ok {
+			return buf, false
+		}
+		buf = append(buf, '"')
+		buf = strconv.AppendInt(buf, n, 10)
+		return append(buf, '"'), true
+	case Float64:
+		f, ok := toFloat64(v)
+		if !ok {
+			return buf, false
+		}
+		return w.appendFloat(f, buf), true
+	case Bool:
+		switch v := v.(type) {
+		case bool:
+			return jsonappender.Bool(v, buf), true
+		case string:
+			if v != "true" && v != "false" {
+				return buf, false
+			}
+			return jsonappender.Bool(v == "true", buf), true
+		}
+		return buf, false
+	case Timestamp:
+		var ts time.Time
+		switch v := v.(type) {
+		case time.Time:
+			ts = v
+		case string:
+			var err error
+			ts, err = time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return buf, false
+			}
+		default:
+			return buf, false
+		}
+		return w.appendTimestamp(ts, buf), true
+	case JSON:
+		b, err := jsonappender.Value(v, buf)
+		if err != nil {
+			return buf, false
+		}
+		return b, true
+	}
+	return buf, false
+}
+
+func appendString(v interface{}, buf []byte) ([]byte, bool) {
+	switch v := v.(type) {
+	case string:
+		return jsonappender.String(v, buf), true
+	case []byte:
+		return jsonappender.String(string(v), buf), true
+	case json.Number:
+		return jsonappender.String(string(v), buf), true
+	case bool:
+		return jsonappender.String(strconv.FormatBool(v), buf), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return buf, false
+		}
+		return jsonappender.String(strconv.FormatFloat(v, 'g', -1, 64), buf), true
+	case float32:
+		return appendString(float64(v), buf)
+	}
+	if n, ok := toInt64(v); ok {
+		buf = append(buf, '"')
+		buf = strconv.AppendInt(buf, n, 10)
+		return append(buf, '"'), true
+	}
+	return buf, false
+}
+
+// toInt64 converts integers, whole floats and numeric strings.
+func toInt64(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return toInt64(float64(v))
+	case float64:
+		// 2^63 itself doesn't fit, so the upper bound is exclusive
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		return toInt64(string(v))
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		return toFloat64(string(v))
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	if n, ok := toInt64(v); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+var (
+	// snowflakeNames are how Snowflake spells NaN and infinities.
+	snowflakeNames = jsonappender.NonFiniteNames{NaN: "NaN", PosInf: "inf", NegInf: "-inf"}
+	// floatNames are how the other warehouses spell them.
+	floatNames = jsonappender.NonFiniteNames{NaN: "NaN", PosInf: "Infinity", NegInf: "-Infinity"}
+)
+
+func (w *Writer) appendFloat(f float64, buf []byte) []byte {
+	if w.target == Snowflake {
+		return jsonappender.Float64Named(f, snowflakeNames, buf)
+	}
+	return jsonappender.Float64Named(f, floatNames, buf)
+}
+
+func (w *Writer) appendTimestamp(ts time.Time, buf []byte) []byte {
+	buf = append(buf, '"')
+	if w.target == BigQuery {
+		buf = ts.UTC().Truncate(time.Microsecond).AppendFormat(buf, "2006-01-02T15:04:05.999999Z07:00")
+	} else {
+		buf = ts.AppendFormat(buf, time.RFC3339Nano)
+	}
+	return append(buf, '"')
+}
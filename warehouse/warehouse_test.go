@@ -0,0 +1,103 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/killa-beez/jsonappender"
+)
+
+var testColumns = []Column{
+	{Name: "id", Type: Int64, Required: true},
+	{Name: "name", Type: String},
+	{Name: "score", Type: Float64},
+	{Name: "active", Type: Bool},
+	{Name: "seen", Type: Timestamp},
+	{Name: "attrs", Type: JSON},
+}
+
+func TestWriter(t *testing.T) {
+	seen := time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.FixedZone("", 3600))
+	for _, td := range []struct {
+		target Target
+		want   string
+	}{
+		{
+			target: BigQuery,
+			want: `{"id":"9007199254740993","name":"42","score":"-Infinity","active":true,"seen":"2020-01-02T02:04:05.123456Z","attrs":{"a":[1]}}` + "\n" +
+				`{"id":"7","score":1.5,"seen":"2020-01-02T02:04:05Z"}` + "\n",
+		},
+		{
+			target: Snowflake,
+			want: `{"id":"9007199254740993","name":"42","score":"-inf","active":true,"seen":"2020-01-02T03:04:05.123456789+01:00","attrs":{"a":[1]}}` + "\n" +
+				`{"id":"7","score":1.5,"seen":"2020-01-02T02:04:05Z"}` + "\n",
+		},
+	} {
+		var out bytes.Buffer
+		bw := jsonappender.NewBufWriter(&out)
+		w := NewWriter(bw, td.target, testColumns)
+		err := w.Write(Record{
+			"id":     json.Number("9007199254740993"),
+			"name":   42,
+			"score":  math.Inf(-1),
+			"active": "true",
+			"seen":   seen,
+			"attrs":  map[string]interface{}{"a": []interface{}{1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = w.Write(Record{"id": 7.0, "score": "1.5", "seen": "2020-01-02T02:04:05Z", "name": nil})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Finish(); err != nil {
+			t.Fatal(err)
+		}
+		bw.Flush()
+		if out.String() != td.want {
+			t.Errorf("got  %s\nwant %s", out.String(), td.want)
+		}
+		if w.Records() != 2 {
+			t.Errorf("got %d records, wanted 2", w.Records())
+		}
+	}
+}
+
+func TestWriter_reject(t *testing.T) {
+	var out bytes.Buffer
+	bw := jsonappender.NewBufWriter(&out)
+	w := NewWriter(bw, BigQuery, testColumns)
+	var unknown *UnknownColumnError
+	if err := w.Write(Record{"id": 1, "extra": 1}); !errors.As(err, &unknown) || unknown.Column != "extra" {
+		t.Errorf("got %v, wanted an UnknownColumnError", err)
+	}
+	var missing *MissingColumnError
+	if err := w.Write(Record{"name": "x"}); !errors.As(err, &missing) || missing.Column != "id" {
+		t.Errorf("got %v, wanted a MissingColumnError", err)
+	}
+	for _, rec := range []Record{
+		{"id": 1.5},
+		{"id": uint64(math.MaxUint64)},
+		{"id": 1, "active": "yes"},
+		{"id": 1, "score": "high"},
+		{"id": 1, "seen": 12},
+		{"id": 1, "attrs": make(chan int)},
+	} {
+		var coercion *CoercionError
+		if err := w.Write(rec); !errors.As(err, &coercion) {
+			t.Errorf("%v: got %v, wanted a CoercionError", rec, err)
+		}
+	}
+	if err := w.Write(Record{"id": 2}); err != nil {
+		t.Fatal(err)
+	}
+	bw.Flush()
+	if out.String() != `{"id":"2"}` {
+		t.Errorf("expected only the valid record, got %q", out.String())
+	}
+}
@@ -0,0 +1,62 @@
+package jsonappender
+
+// Watermarks configures notifications about the amount of buffered output. OnHigh is
+// called when the number of buffered bytes reaches High. OnLow is called the next time
+// it drops to Low or below, usually on Flush. Producers can use the pair to pause and
+// resume feeding the writer. Either callback may be nil. Callbacks are called after
+// the BufWriter is done with its buffer, so they may use methods like Buffered.
+//
+// Buffered output never exceeds the size of the BufWriter's buffer unless it has no
+// writer, so High must be smaller than that to ever be reached.
+type Watermarks struct {
+	Low    int
+	High   int
+	OnHigh func()
+	OnLow  func()
+}
+
+type watermarks struct {
+	Watermarks
+	above bool
+}
+
+// SetWatermarks sets the buffered byte thresholds used for backpressure notifications.
+// Watermarks without callbacks disables them.
+func (bw *BufWriter) SetWatermarks(wm Watermarks) {
+	bw.lockIdle()
+	defer bw.unlockIdle()
+	if wm.OnHigh == nil && wm.OnLow == nil {
+		bw.watermarks = nil
+		return
+	}
+	bw.watermarks = &watermarks{
+		Watermarks: wm,
+	}
+	bw.checkWatermarks()
+}
+
+func (bw *BufWriter) checkWatermarks() {
+	wm := bw.watermarks
+	if wm == nil {
+		return
+	}
+	n := len(bw.buf)
+	var fn func()
+	switch {
+	case !wm.above && n >= wm.High:
+		wm.above = true
+		fn = wm.OnHigh
+	case wm.above && n <= wm.Low:
+		wm.above = false
+		fn = wm.OnLow
+	}
+	if fn == nil {
+		return
+	}
+	if bw.idle != nil {
+		// idle.mu is locked, and a callback that uses the BufWriter would deadlock on it.
+		bw.idle.callbacks = append(bw.idle.callbacks, fn)
+		return
+	}
+	fn()
+}
@@ -0,0 +1,41 @@
+package jsonappender
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBufWriter_SetWatermarks(t *testing.T) {
+	var events []string
+	bw := NewBufWriter(&bytes.Buffer{})
+	bw.SetWatermarks(Watermarks{
+		Low:  10,
+		High: 1000,
+		OnHigh: func() {
+			events = append(events, "high")
+		},
+		OnLow: func() {
+			events = append(events, "low")
+		},
+	})
+	for i := 0; i < 20; i++ {
+		bw.String(strings.Repeat("a", 98))
+	}
+	if strings.Join(events, ",") != "high" {
+		t.Errorf("got events %v", events)
+	}
+	err := bw.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bw.Int64(1)
+	if strings.Join(events, ",") != "high,low" {
+		t.Errorf("got events %v", events)
+	}
+	bw.SetWatermarks(Watermarks{})
+	bw.RawString(strings.Repeat("a", 2000))
+	if len(events) != 2 {
+		t.Errorf("got events %v", events)
+	}
+}